@@ -1,249 +1,168 @@
 package main
 
 import (
+    "context"
+    "flag"
     "log"
     "net/http"
-    "encoding/json"
-    "strings"
+    "os"
     "time"
-    "fmt"
+    "bounty-system/internal/auth"
     "bounty-system/internal/client"
-    intTypes "bounty-system/internal/types"
+    "bounty-system/internal/handlers"
+    "bounty-system/internal/indexer"
+    "bounty-system/internal/scheduler"
+    "bounty-system/internal/store"
+    sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
-type Server struct {
-    tasks map[string]intTypes.Task
-    bc    *client.BlockchainClient
-}
-
-func NewServer() *Server {
-    bc := client.NewBlockchainClient()
-    
-    // Get admin address
-    adminAddr := bc.GetAdminAddress()
-    
-    log.Printf("\n=== IMPORTANT ADDRESSES ===")
-    log.Printf("Admin Address: %s", adminAddr)
-    log.Printf("Use this address in X-Wallet-Address header for admin operations")
-    log.Printf("========================\n")
-
-    return &Server{
-        tasks: make(map[string]intTypes.Task),
-        bc:    bc,
-    }
-}
-
-func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-    log.Printf("Received request: %s %s", r.Method, r.URL.Path)
-    
-    switch {
-    case r.Method == "GET" && r.URL.Path == "/tasks":
-        s.handleGetTasks(w, r)
-    case r.Method == "POST" && r.URL.Path == "/tasks":
-        s.handleCreateTask(w, r)
-    case r.Method == "PUT" && strings.HasSuffix(r.URL.Path, "/claim"):
-        s.handleClaimTask(w, r)
-    case r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/admin/tasks/"):
-        s.handleApproveTask(w, r)
-    case r.Method == "POST" && r.URL.Path == "/admin/admins":
-        s.handleAdmins(w, r)
-    case r.Method == "POST" && r.URL.Path == "/generate-address":
-        s.handleGenerateAddress(w, r)
-    case r.Method == "GET" && r.URL.Path == "/addresses":
-        s.handleListAddresses(w, r)
-    default:
-        log.Printf("No route match found for: %s %s", r.Method, r.URL.Path)
-        http.NotFound(w, r)
-    }
-}
-
-func (s *Server) handleListAddresses(w http.ResponseWriter, r *http.Request) {
-    w.Header().Set("Content-Type", "application/json")
-    addresses := s.bc.ListAddresses()
-    json.NewEncoder(w).Encode(map[string]interface{}{
-        "admin_address": s.bc.GetAdminAddress(),
-        "all_addresses": addresses,
-    })
-}
-
-func (s *Server) handleCreateTask(w http.ResponseWriter, r *http.Request) {
-    w.Header().Set("Content-Type", "application/json")
-    var task intTypes.Task
-    if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
-        http.Error(w, err.Error(), http.StatusBadRequest)
-        return
-    }
-    
-    task.ID = fmt.Sprintf("task-%d", time.Now().Unix())
-    task.Status = "OPEN"
-
-    if err := s.bc.CreateTask(task); err != nil {
-        http.Error(w, err.Error(), http.StatusInternalServerError)
-        return
-    }
-    
-    s.tasks[task.ID] = task
-    log.Printf("Created task: %s", task.ID)
-    json.NewEncoder(w).Encode(task)
-}
-
-func (s *Server) handleGetTasks(w http.ResponseWriter, r *http.Request) {
-    w.Header().Set("Content-Type", "application/json")
-    tasks, err := s.bc.ListTasks()
+func main() {
+    dbPath := flag.String("db", "bounty.db", "path to the task database file (empty keeps tasks in memory)")
+    rejectPolicy := flag.String("reject-policy", string(client.RejectReopen), "what a rejected claim does to its task: reopen or close (refund)")
+    expiryInterval := flag.Duration("expiry-interval", 30*time.Second, "how often task deadlines are checked (0 disables the scheduler)")
+    approvalThreshold := flag.String("approval-threshold", "", "bounties above this amount need several approvals (empty disables)")
+    approvalsRequired := flag.Int("approvals-required", 2, "distinct approvals needed above -approval-threshold")
+    governance := client.DefaultGovernancePolicy()
+    flag.IntVar(&governance.Quorum, "admin-quorum", 0, "yes votes needed to change the admin set (0 means a majority of admins)")
+    flag.DurationVar(&governance.VotingPeriod, "voting-period", governance.VotingPeriod, "how long admin-set proposals are open for votes")
+    flag.DurationVar(&governance.Timelock, "admin-timelock", governance.Timelock, "delay between a proposal passing and its execution")
+    confirmation := client.DefaultConfirmPolicy()
+    flag.DurationVar(&confirmation.Timeout, "confirm-timeout", confirmation.Timeout, "how long a request waits for its transaction to be included in a block")
+    flag.DurationVar(&confirmation.Interval, "confirm-interval", confirmation.Interval, "how often a pending transaction is looked up")
+    flag.DurationVar(&confirmation.Reconcile, "reconcile-timeout", confirmation.Reconcile, "how long a transaction that timed out is still looked up in the background")
+    fees := client.DefaultFeePolicy()
+    flag.BoolVar(&fees.Simulate, "simulate-gas", fees.Simulate, "simulate transactions to size their gas limit (otherwise every transaction gets the default limit)")
+    flag.Float64Var(&fees.GasAdjustment, "gas-adjustment", fees.GasAdjustment, "factor applied to simulated gas")
+    gasPrice := flag.String("gas-price", fees.GasPrice.String(), "fee per unit of gas, e.g. 0.005microSERVDR")
+    sessionSecret := flag.String("session-secret", os.Getenv("BOUNTY_SESSION_SECRET"), "secret signing session tokens (random per start if empty)")
+    sessionTTL := flag.Duration("session-ttl", auth.DefaultSessionTTL, "lifetime of session access tokens")
+    admin := flag.String("admin", os.Getenv("BOUNTY_ADMIN_ADDRESS"), "address of the bootstrap admin wallet (required)")
+    escrowKey := flag.String("escrow-key", os.Getenv("BOUNTY_ESCROW_KEY"), "hex secp256k1 key of the escrow account (required for rest, random per start otherwise)")
+    index := flag.Bool("index", os.Getenv("BOUNTY_INDEX") != "", "follow the chain over the RPC websocket and index task transactions (rest)")
+    cfg := client.ConfigFromEnv()
+    cfg.RegisterFlags(flag.CommandLine)
+    flag.Parse()
+
+    backend, err := client.NewBackend(cfg)
     if err != nil {
-        http.Error(w, err.Error(), http.StatusInternalServerError)
-        return
-    }
-    json.NewEncoder(w).Encode(tasks)
-}
-
-func (s *Server) handleGenerateAddress(w http.ResponseWriter, r *http.Request) {
-    w.Header().Set("Content-Type", "application/json")
-    
-    var req struct {
-        Seed string `json:"seed"`
+        log.Fatalf("Failed to configure chain backend: %v", err)
     }
-    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        http.Error(w, err.Error(), http.StatusBadRequest)
-        return
+    if p := client.RejectPolicy(*rejectPolicy); p != client.RejectReopen && p != client.RejectClose {
+        log.Fatalf("Unknown reject policy %q", *rejectPolicy)
     }
-    
-    address := s.bc.GenerateTestAddress(req.Seed)
-    if address == "" {
-        http.Error(w, "Failed to generate address", http.StatusInternalServerError)
-        return
+    if fees.GasPrice, err = client.ParseGasPrice(*gasPrice); err != nil {
+        log.Fatalf("%v", err)
     }
-    
-    json.NewEncoder(w).Encode(map[string]string{
-        "address": address,
-    })
-}
-
-func (s *Server) handleClaimTask(w http.ResponseWriter, r *http.Request) {
-    w.Header().Set("Content-Type", "application/json")
-    
-    parts := strings.Split(r.URL.Path, "/")
-    if len(parts) < 4 {
-        http.Error(w, "Invalid URL format", http.StatusBadRequest)
-        return
+    if fees.GasAdjustment < 1 {
+        log.Fatalf("Gas adjustment must be at least 1, got %v", fees.GasAdjustment)
     }
-    taskID := parts[2]
-
-    var claim struct {
-        Claimer string `json:"claimer"`
-        Proof   string `json:"proof"`
-    }
-    if err := json.NewDecoder(r.Body).Decode(&claim); err != nil {
-        http.Error(w, err.Error(), http.StatusBadRequest)
-        return
+    if _, err := sdk.AccAddressFromBech32(*admin); err != nil {
+        log.Fatalf("Set -admin or BOUNTY_ADMIN_ADDRESS to the bootstrap admin's address: %v", err)
     }
-
-    if err := s.bc.ClaimTask(taskID, claim.Claimer, claim.Proof); err != nil {
-        http.Error(w, err.Error(), http.StatusInternalServerError)
-        return
-    }
-
-    // Get updated task
-    tasks, _ := s.bc.ListTasks()
-    var claimedTask intTypes.Task
-    for _, t := range tasks {
-        if t.ID == taskID {
-            claimedTask = t
-            break
+    options := []client.Option{client.WithAdmin(*admin)}
+    if *escrowKey != "" {
+        key, err := client.ParsePrivKey(*escrowKey)
+        if err != nil {
+            log.Fatalf("Invalid escrow key: %v", err)
         }
+        options = append(options, client.WithEscrowKey(key))
+    } else if cfg.Backend == client.BackendREST {
+        log.Fatalf("Set -escrow-key or BOUNTY_ESCROW_KEY when running against a chain")
     }
-
-    json.NewEncoder(w).Encode(claimedTask)
-}
-
-func (s *Server) handleApproveTask(w http.ResponseWriter, r *http.Request) {
-    w.Header().Set("Content-Type", "application/json")
-
-    adminAddr := r.Header.Get("X-Wallet-Address")
-    if !s.bc.IsAdmin(adminAddr) {
-        http.Error(w, "Unauthorized - Admin access required", http.StatusUnauthorized)
-        return
-    }
-
-    parts := strings.Split(r.URL.Path, "/")
-    if len(parts) < 4 {
-        http.Error(w, "Invalid URL format", http.StatusBadRequest)
-        return
-    }
-    taskID := parts[3]
-
-    tasks, _ := s.bc.ListTasks()
-    var task intTypes.Task
-    for _, t := range tasks {
-        if t.ID == taskID {
-            task = t
-            break
+    if *approvalThreshold != "" {
+        if _, ok := sdk.NewIntFromString(*approvalThreshold); !ok {
+            log.Fatalf("Invalid approval threshold %q", *approvalThreshold)
         }
     }
 
-    if task.ID == "" {
-        http.Error(w, "Task not found", http.StatusNotFound)
-        return
-    }
-
-    if err := s.bc.ApproveTask(task, adminAddr); err != nil {
-        http.Error(w, err.Error(), http.StatusInternalServerError)
-        return
-    }
-
-    // Get updated task
-    tasks, _ = s.bc.ListTasks()
-    for _, t := range tasks {
-        if t.ID == taskID {
-            task = t
-            break
+    var st store.Store = store.NewMemoryStore()
+    if *dbPath != "" {
+        boltStore, err := store.OpenBoltStore(*dbPath)
+        if err != nil {
+            log.Fatalf("Failed to open task store: %v", err)
         }
+        defer boltStore.Close()
+        st = boltStore
+        log.Printf("Persisting tasks in %s", *dbPath)
+    }
+
+    bc := client.NewBlockchainClient(append(options,
+        client.WithStore(st),
+        client.WithBackend(backend),
+        client.WithRejectPolicy(client.RejectPolicy(*rejectPolicy)),
+        client.WithApprovalPolicy(client.ApprovalPolicy{Threshold: *approvalThreshold, Required: *approvalsRequired}),
+        client.WithGovernancePolicy(governance),
+        client.WithConfirmPolicy(confirmation),
+        client.WithFeePolicy(fees),
+    )...)
+    secret := []byte(*sessionSecret)
+    if len(secret) == 0 {
+        log.Printf("Warning: no -session-secret set, sessions will not survive a restart")
+        secret = auth.RandomSecret()
+    }
+    sessions := auth.NewSessionManager(secret, *sessionTTL, auth.DefaultRefreshTTL, bc.GetRole, auth.WithRevocationStore(st))
+    router := handlers.NewRouter(handlers.NewTaskHandler(bc, auth.NewAuthenticator(auth.DefaultChallengeTTL), sessions))
+    
+    if *expiryInterval > 0 {
+        deadlines := scheduler.New(bc, *expiryInterval)
+        deadlines.Start()
+        defer deadlines.Stop()
+        log.Printf("Checking task deadlines every %s", *expiryInterval)
     }
 
-    json.NewEncoder(w).Encode(task)
-}
-
-func (s *Server) handleAdmins(w http.ResponseWriter, r *http.Request) {
-    w.Header().Set("Content-Type", "application/json")
-
-    adminAddr := r.Header.Get("X-Wallet-Address")
-    if !s.bc.IsAdmin(adminAddr) {
-        http.Error(w, "Unauthorized - Admin access required", http.StatusUnauthorized)
-        return
-    }
-
-    var req struct {
-        Address string `json:"address"`
-    }
-    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-        http.Error(w, err.Error(), http.StatusBadRequest)
-        return
-    }
-
-    if err := s.bc.AddAdmin(req.Address, adminAddr); err != nil {
-        http.Error(w, err.Error(), http.StatusInternalServerError)
-        return
+    if *index {
+        source, err := indexer.NewTendermintSource(cfg.RPCEndpoint)
+        if err != nil {
+            log.Fatalf("Failed to connect the chain indexer: %v", err)
+        }
+        ix := indexer.NewIndexer(indexer.DefaultCheckpoint, st, client.MakeEncodingConfig().TxConfig.TxDecoder(), bc.GetEscrowAddress())
+        ctx, cancel := context.WithCancel(context.Background())
+        defer cancel()
+        go indexer.NewSubscriber(source, ix).Run(ctx)
+        log.Printf("Indexing task transactions from %s", cfg.RPCEndpoint)
     }
     
-    json.NewEncoder(w).Encode(map[string]string{"message": "Admin added successfully"})
-}
-
-func main() {
-    server := NewServer()
-    
     log.Printf("Starting Tokenized Task Bounty System...")
-    log.Printf("Chain ID: %s", server.bc.GetChainID())
-    log.Printf("RPC Endpoint: %s", server.bc.GetRPCEndpoint())
-    log.Printf("REST Endpoint: %s", server.bc.GetRESTEndpoint())
+    log.Printf("Chain backend: %s", cfg.Backend)
+    log.Printf("Chain ID: %s", bc.GetChainID())
+    log.Printf("RPC Endpoint: %s", bc.GetRPCEndpoint())
+    log.Printf("REST Endpoint: %s", bc.GetRESTEndpoint())
+    
+    log.Printf("\n=== IMPORTANT ADDRESSES ===")
+    log.Printf("Admin Address: %s", bc.GetAdminAddress())
+    log.Printf("Escrow Address: %s", bc.GetEscrowAddress())
+    log.Printf("Sign a challenge from POST /auth/challenge with this wallet for admin operations")
+    log.Printf("========================\n")
     
     log.Printf("\nServer starting on :8080")
     log.Printf("Available endpoints:")
+    log.Printf("POST /auth/challenge   - Get a challenge to sign (required for writes)")
+    log.Printf("POST /auth/login       - Exchange a signed challenge for a session token")
+    log.Printf("POST /auth/refresh     - Refresh a session")
+    log.Printf("POST /auth/logout      - Revoke a session")
     log.Printf("GET  /addresses        - List all addresses")
     log.Printf("POST /generate-address - Generate a new address")
     log.Printf("POST /tasks           - Create a task")
-    log.Printf("GET  /tasks           - List all tasks")
-    log.Printf("PUT  /tasks/{id}/claim- Claim a task")
+    log.Printf("POST /tasks/estimate  - Estimate the cost of creating a task without creating it")
+    log.Printf("GET  /tasks           - List tasks (filters, sort, cursor paging)")
+    log.Printf("GET  /tasks/status/{status} - List tasks in one status")
+    log.Printf("GET  /tasks/{id}      - One task with its history")
+    log.Printf("PUT  /tasks/{id}/claim- Claim a task (proof optional)")
+    log.Printf("PUT  /tasks/{id}/proof- Submit the proof for a claim")
+    log.Printf("PUT  /tasks/{id}/cancel- Cancel an open task (creator or admin)")
     log.Printf("PUT  /admin/tasks/{id}- Approve a task")
+    log.Printf("PUT  /admin/tasks/{id}/reject - Reject a claim with a reason")
+    log.Printf("POST /admin/admins    - Propose a new admin (admin)")
+    log.Printf("DELETE /admin/admins/{address} - Propose removing an admin (admin)")
+    log.Printf("GET  /admin/proposals - List admin-set proposals (admin, auditor)")
+    log.Printf("POST /admin/proposals - Propose adding or removing an admin (admin)")
+    log.Printf("GET  /admin/proposals/{id} - One proposal with its votes and audit trail")
+    log.Printf("POST /admin/proposals/{id}/vote - Vote on a proposal (admin)")
+    log.Printf("POST /admin/proposals/{id}/execute - Apply a passed proposal after its time-lock (admin)")
+    log.Printf("GET  /admin/roles     - List roles (admin, auditor)")
+    log.Printf("PUT  /admin/roles     - Assign a role (admin)")
+    log.Printf("GET  /escrow          - Escrow totals and entries")
+    log.Printf("GET  /tasks/{id}/escrow - Escrow of one task")
     
-    log.Fatal(http.ListenAndServe(":8080", server))
+    log.Fatal(http.ListenAndServe(":8080", router))
 }
\ No newline at end of file
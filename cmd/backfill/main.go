@@ -0,0 +1,67 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "flag"
+    "log"
+    "os"
+    "os/signal"
+    "bounty-system/internal/client"
+    "bounty-system/internal/indexer"
+    "bounty-system/internal/store"
+)
+
+func main() {
+    cfg := client.ConfigFromEnv()
+    dbPath := flag.String("db", "bounty.db", "path to the task database file")
+    rpc := flag.String("rpc", cfg.RPCEndpoint, "Tendermint RPC endpoint to read blocks from")
+    from := flag.Int64("from", 1, "first block height to index when there is no later checkpoint")
+    to := flag.Int64("to", 0, "last block height to index (0 means the latest block)")
+    checkpointName := flag.String("checkpoint", indexer.DefaultCheckpoint, "name of the checkpoint to resume from and update")
+    reset := flag.Bool("reset", false, "forget the checkpoint and start again at -from")
+    escrow := flag.String("escrow", os.Getenv("BOUNTY_ESCROW_ADDRESS"), "escrow address of the API server; memo transactions moving funds elsewhere are ignored")
+    flag.Parse()
+    if *escrow == "" {
+        log.Fatalf("Set -escrow (or BOUNTY_ESCROW_ADDRESS) to the escrow address the API server logs at startup")
+    }
+
+    st, err := store.OpenBoltStore(*dbPath)
+    if err != nil {
+        log.Fatalf("Failed to open task store: %v", err)
+    }
+    defer st.Close()
+
+    source, err := indexer.NewTendermintSource(*rpc)
+    if err != nil {
+        log.Fatalf("Failed to connect to %s: %v", *rpc, err)
+    }
+    ix := indexer.NewIndexer(*checkpointName, st, client.MakeEncodingConfig().TxConfig.TxDecoder(), *escrow)
+    if *reset {
+        if err := ix.Reset(); err != nil {
+            log.Fatalf("Failed to reset checkpoint: %v", err)
+        }
+    }
+    checkpoint, err := ix.Checkpoint()
+    if err != nil {
+        log.Fatalf("Failed to read checkpoint: %v", err)
+    }
+    log.Printf("Backfilling %s from %s (checkpoint %q at block %d)", *dbPath, *rpc, *checkpointName, checkpoint.Height)
+
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+    defer stop()
+    last, err := indexer.NewBackfiller(source, ix, *from).Run(ctx, *to)
+    var reorg *indexer.ReorgError
+    switch {
+    case errors.As(err, &reorg):
+        log.Printf("Stopped at block %d: %v", last, err)
+        log.Printf("Rerun with -reset, and -from before block %d, to index the current chain", reorg.Height)
+        st.Close()
+        os.Exit(1)
+    case err != nil:
+        log.Printf("Stopped at block %d: %v", last, err)
+        st.Close()
+        os.Exit(1)
+    }
+    log.Printf("Indexed up to block %d", last)
+}
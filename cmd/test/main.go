@@ -1,15 +1,26 @@
 package main
 
 import (
+    "flag"
     "log"
-    "fmt"
-    "time"
+    "bounty-system/internal/auth"
     "bounty-system/internal/client"
+    "bounty-system/internal/ids"
     "bounty-system/internal/types"
 )
 
 func main() {
-    c := client.NewBlockchainClient()
+    cfg := client.ConfigFromEnv()
+    cfg.RegisterFlags(flag.CommandLine)
+    flag.Parse()
+
+    backend, err := client.NewBackend(cfg)
+    if err != nil {
+        log.Fatalf("Failed to configure chain backend: %v", err)
+    }
+    // The admin's key stays with its owner, so the demo only signs as the user wallet
+    c := client.NewBlockchainClient(client.WithBackend(backend), client.WithAdmin(client.TestWalletAddress("admin-1")))
+    log.Printf("Running against %s backend (chain %s)", cfg.Backend, c.GetChainID())
     
     // Get test wallets
     wallets := c.GetTestWallets()
@@ -22,17 +33,17 @@ func main() {
     }
     
     // Create task with test user wallet
-    taskID := fmt.Sprintf("task-%d", time.Now().Unix())
+    taskID := ids.New("task")
     task := types.Task{
         ID:          taskID,
         Title:       "Test Task",
         Description: "Test Description",
         Creator:     wallets[1], // Use non-admin wallet
         Bounty:      "1000000", // Amount without denom
-        Status:      "OPEN",
+        Status:      types.TaskStatusOpen,
     }
     
-    err := c.CreateTask(task)
+    err = c.CreateTask(task)
     if err != nil {
         log.Printf("Error creating task: %v", err)
     } else {
@@ -44,9 +55,11 @@ func main() {
             log.Printf("Expected error with non-admin approval: %v", err)
         }
         
-        // Try to approve with admin
-        task.Status = "CLAIMED"
-        task.Claimer = wallets[1]
+        // Claim the task, then approve with admin
+        err = c.ClaimTask(task.ID, wallets[1], "https://github.com/proof")
+        if err != nil {
+            log.Printf("Error claiming task: %v", err)
+        }
         err = c.ApproveTask(task, wallets[0])
         if err != nil {
             log.Printf("Error approving task with admin: %v", err)
@@ -56,23 +69,66 @@ func main() {
     }
     
     // Test admin management
-    newAdmin := "serv1newadmin..."
+    newAdmin := c.GenerateTestAddress("new-admin")
     
     // Try adding admin with non-admin wallet
-    err = c.AddAdmin(newAdmin, wallets[1])
+    _, err = c.AddAdmin(newAdmin, wallets[1])
     if err != nil {
         log.Printf("Expected error when non-admin adds admin: %v", err)
     }
     
-    // Add admin with admin wallet
-    err = c.AddAdmin(newAdmin, wallets[0])
+    // Propose the new admin with the admin wallet; as the only admin its
+    // vote is a quorum, but the change waits for the time-lock
+    proposal, err := c.AddAdmin(newAdmin, wallets[0])
     if err != nil {
-        log.Printf("Error adding new admin: %v", err)
+        log.Printf("Error proposing new admin: %v", err)
     } else {
-        log.Printf("New admin added successfully")
+        log.Printf("Proposal %s is %s, executable at %s", proposal.ID, proposal.Status, proposal.ExecutableAt)
+        if _, err := c.ExecuteProposal(proposal.ID, wallets[0]); err != nil {
+            log.Printf("Expected error executing before the time-lock: %v", err)
+        }
     }
     
     // List all admins
     admins := c.ListAdmins()
     log.Printf("Current admins: %v", admins)
+    
+    // A reviewer can approve but not manage roles
+    reviewer := c.GenerateTestAddress("reviewer-1")
+    if err := c.AssignRole(reviewer, types.ROLE_REVIEWER, wallets[0]); err != nil {
+        log.Printf("Error assigning reviewer role: %v", err)
+    }
+    if err := c.AssignRole(wallets[1], types.ROLE_ADMIN, reviewer); err != nil {
+        log.Printf("Expected error when a reviewer assigns roles: %v", err)
+    }
+    
+    // Prove control of the user wallet with a signed challenge
+    authenticator := auth.NewAuthenticator(auth.DefaultChallengeTTL)
+    challenge, err := authenticator.Challenge(wallets[1])
+    if err != nil {
+        log.Fatalf("Error issuing challenge: %v", err)
+    }
+    signed, err := c.SignChallenge(challenge)
+    if err != nil {
+        log.Fatalf("Error signing challenge: %v", err)
+    }
+    if address, err := authenticator.Verify(signed); err != nil {
+        log.Printf("Error verifying signed challenge: %v", err)
+    } else {
+        log.Printf("Wallet %s authenticated by signature", address)
+    }
+    if _, err := authenticator.Verify(signed); err != nil {
+        log.Printf("Expected error when replaying a challenge: %v", err)
+    }
+    
+    // Log in with a fresh challenge and use the session instead
+    sessions := auth.NewSessionManager(auth.RandomSecret(), auth.DefaultSessionTTL, auth.DefaultRefreshTTL, c.GetRole)
+    challenge, _ = authenticator.Challenge(wallets[1])
+    signed, _ = c.SignChallenge(challenge)
+    session, err := sessions.Login(authenticator, signed)
+    if err != nil {
+        log.Printf("Error logging in: %v", err)
+    } else {
+        log.Printf("Session for %s with role %s valid until %s", session.Address, session.Role, session.ExpiresAt)
+    }
 }
@@ -0,0 +1,204 @@
+// Package auth proves that a caller controls a wallet address. The server
+// hands out single-use challenges which the wallet signs ADR-036 style
+// (arbitrary message signing, as done by Keplr's signArbitrary); the
+// signature is checked against the bech32 address before any action is
+// attributed to it.
+package auth
+
+import (
+    "crypto/rand"
+    "encoding/base64"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "sync"
+    "time"
+
+    "bounty-system/internal/types"
+    "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// DefaultChallengeTTL is how long an issued challenge can be answered.
+const DefaultChallengeTTL = 5 * time.Minute
+
+// MaxChallenges bounds how many challenges issued within one TTL are kept.
+// Anyone may ask for challenges, so without a bound they could fill memory.
+const MaxChallenges = 10000
+
+var (
+    ErrUnknownChallenge  = errors.New("unknown or already used challenge")
+    ErrExpiredChallenge  = errors.New("challenge expired")
+    ErrInvalidSignature  = errors.New("invalid signature")
+    ErrTooManyChallenges = errors.New("too many pending challenges, try again later")
+)
+
+// Challenge is a nonce issued to an address, together with the exact
+// message the wallet has to sign.
+type Challenge struct {
+    Address   string    `json:"address"`
+    Nonce     string    `json:"nonce"`
+    Message   string    `json:"message"`
+    ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SignedChallenge is a wallet's answer to a Challenge. PubKey is the
+// compressed secp256k1 key and Signature the 64 byte r||s signature, both
+// base64 encoded as wallets return them.
+type SignedChallenge struct {
+    Address   string `json:"address"`
+    Nonce     string `json:"nonce"`
+    PubKey    string `json:"pub_key"`
+    Signature string `json:"signature"`
+}
+
+// Authenticator issues challenges and verifies the signed answers. Each
+// challenge can be used once.
+type Authenticator struct {
+    mu         sync.Mutex
+    challenges map[string]Challenge // Nonce -> pending challenge
+    issued     []issued             // Every challenge in the order it was issued, which is the order it expires in
+    max        int
+    ttl        time.Duration
+    now        func() time.Time
+}
+
+type issued struct {
+    nonce     string
+    expiresAt time.Time
+}
+
+func NewAuthenticator(ttl time.Duration) *Authenticator {
+    return &Authenticator{
+        challenges: make(map[string]Challenge),
+        max:        MaxChallenges,
+        ttl:        ttl,
+        now:        time.Now,
+    }
+}
+
+// Challenge issues a fresh nonce for address.
+func (a *Authenticator) Challenge(address string) (Challenge, error) {
+    if _, err := sdk.AccAddressFromBech32(address); err != nil {
+        return Challenge{}, types.Invalidf("invalid address: %v", err)
+    }
+
+    raw := make([]byte, 32)
+    if _, err := rand.Read(raw); err != nil {
+        return Challenge{}, fmt.Errorf("failed to generate nonce: %v", err)
+    }
+    nonce := hex.EncodeToString(raw)
+
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    now := a.now()
+    a.pruneLocked(now)
+    if len(a.issued) >= a.max {
+        return Challenge{}, ErrTooManyChallenges
+    }
+    challenge := Challenge{
+        Address:   address,
+        Nonce:     nonce,
+        Message:   ChallengeMessage(address, nonce),
+        ExpiresAt: now.Add(a.ttl).UTC(),
+    }
+    a.challenges[nonce] = challenge
+    a.issued = append(a.issued, issued{nonce, challenge.ExpiresAt})
+    return challenge, nil
+}
+
+// Verify checks a signed challenge and returns the address it proves. The
+// challenge is consumed whether or not the signature is valid.
+func (a *Authenticator) Verify(signed SignedChallenge) (string, error) {
+    a.mu.Lock()
+    challenge, ok := a.challenges[signed.Nonce]
+    delete(a.challenges, signed.Nonce)
+    now := a.now()
+    a.mu.Unlock()
+
+    if !ok || challenge.Address != signed.Address {
+        return "", ErrUnknownChallenge
+    }
+    if now.After(challenge.ExpiresAt) {
+        return "", ErrExpiredChallenge
+    }
+    if err := VerifyArbitrary(signed.Address, []byte(challenge.Message), signed.PubKey, signed.Signature); err != nil {
+        return "", err
+    }
+    return signed.Address, nil
+}
+
+// pruneLocked drops the challenges that have expired. Used ones keep
+// counting against max until then, so answering challenges does not make
+// room for more.
+func (a *Authenticator) pruneLocked(now time.Time) {
+    expired := 0
+    for expired < len(a.issued) && now.After(a.issued[expired].expiresAt) {
+        delete(a.challenges, a.issued[expired].nonce)
+        expired++
+    }
+    a.issued = a.issued[expired:]
+}
+
+// ChallengeMessage is the text a wallet signs to answer a challenge.
+func ChallengeMessage(address string, nonce string) string {
+    return fmt.Sprintf("Sign in to the Task Bounty System\nAddress: %s\nNonce: %s", address, nonce)
+}
+
+// VerifyArbitrary checks an ADR-036 signature of data by the wallet at
+// address. The public key must belong to the address.
+func VerifyArbitrary(address string, data []byte, pubKeyB64 string, signatureB64 string) error {
+    signer, err := sdk.AccAddressFromBech32(address)
+    if err != nil {
+        return fmt.Errorf("invalid address: %v", err)
+    }
+    keyBytes, err := base64.StdEncoding.DecodeString(pubKeyB64)
+    if err != nil || len(keyBytes) != secp256k1.PubKeySize {
+        return fmt.Errorf("invalid public key")
+    }
+    signature, err := base64.StdEncoding.DecodeString(signatureB64)
+    if err != nil {
+        return fmt.Errorf("invalid signature encoding")
+    }
+
+    pubKey := &secp256k1.PubKey{Key: keyBytes}
+    if !signer.Equals(sdk.AccAddress(pubKey.Address())) {
+        return fmt.Errorf("public key does not belong to %s", address)
+    }
+    if !pubKey.VerifySignature(ADR036SignBytes(address, data), signature) {
+        return ErrInvalidSignature
+    }
+    return nil
+}
+
+// ADR036SignBytes returns the amino JSON sign document ADR-036 defines for
+// signing data off chain: a zero-fee StdSignDoc with an empty chain ID
+// carrying a single sign/MsgSignData message.
+func ADR036SignBytes(signer string, data []byte) []byte {
+    // encoding/json sorts map keys, which gives the canonical form
+    doc := map[string]interface{}{
+        "account_number": "0",
+        "chain_id":       "",
+        "fee": map[string]interface{}{
+            "amount": []interface{}{},
+            "gas":    "0",
+        },
+        "memo": "",
+        "msgs": []interface{}{
+            map[string]interface{}{
+                "type": "sign/MsgSignData",
+                "value": map[string]interface{}{
+                    "data":   base64.StdEncoding.EncodeToString(data),
+                    "signer": signer,
+                },
+            },
+        },
+        "sequence": "0",
+    }
+    bz, err := json.Marshal(doc)
+    if err != nil {
+        panic(err)
+    }
+    return bz
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+    "encoding/base64"
+    "errors"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func sign(t *testing.T, key *secp256k1.PrivKey, challenge Challenge) SignedChallenge {
+    t.Helper()
+    signature, err := key.Sign(ADR036SignBytes(challenge.Address, []byte(challenge.Message)))
+    if err != nil {
+        t.Fatalf("sign failed: %v", err)
+    }
+    return SignedChallenge{
+        Address:   challenge.Address,
+        Nonce:     challenge.Nonce,
+        PubKey:    base64.StdEncoding.EncodeToString(key.PubKey().Bytes()),
+        Signature: base64.StdEncoding.EncodeToString(signature),
+    }
+}
+
+func TestChallengeResponse(t *testing.T) {
+    key := secp256k1.GenPrivKey()
+    address := sdk.AccAddress(key.PubKey().Address()).String()
+    a := NewAuthenticator(time.Minute)
+
+    if _, err := a.Challenge("serv1notanaddress"); err == nil {
+        t.Fatalf("expected error for an invalid address")
+    }
+
+    challenge, err := a.Challenge(address)
+    if err != nil {
+        t.Fatalf("challenge failed: %v", err)
+    }
+    signed := sign(t, key, challenge)
+    if got, err := a.Verify(signed); err != nil || got != address {
+        t.Fatalf("verify failed: %q %v", got, err)
+    }
+    if _, err := a.Verify(signed); !errors.Is(err, ErrUnknownChallenge) {
+        t.Fatalf("expected replay to fail, got %v", err)
+    }
+
+    // A key that does not belong to the address is refused even with a valid signature
+    challenge, _ = a.Challenge(address)
+    if _, err := a.Verify(sign(t, secp256k1.GenPrivKey(), challenge)); err == nil {
+        t.Fatalf("expected error for a foreign key")
+    }
+
+    // Signing a different message fails verification
+    challenge, _ = a.Challenge(address)
+    forged := challenge
+    forged.Message = ChallengeMessage(address, "other-nonce")
+    if _, err := a.Verify(sign(t, key, forged)); !errors.Is(err, ErrInvalidSignature) {
+        t.Fatalf("expected ErrInvalidSignature, got %v", err)
+    }
+
+    // Challenges must be answered before they expire
+    challenge, _ = a.Challenge(address)
+    a.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+    if _, err := a.Verify(sign(t, key, challenge)); !errors.Is(err, ErrExpiredChallenge) {
+        t.Fatalf("expected ErrExpiredChallenge, got %v", err)
+    }
+}
+
+func TestChallengeLimit(t *testing.T) {
+    address := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address()).String()
+    a := NewAuthenticator(time.Minute)
+    a.max = 3
+
+    start := time.Now()
+    a.now = func() time.Time { return start }
+    var first Challenge
+    for i := 0; i < 3; i++ {
+        challenge, err := a.Challenge(address)
+        if err != nil {
+            t.Fatalf("challenge %d failed: %v", i, err)
+        }
+        if i == 0 {
+            first = challenge
+        }
+    }
+    if _, err := a.Challenge(address); !errors.Is(err, ErrTooManyChallenges) {
+        t.Fatalf("expected ErrTooManyChallenges, got %v", err)
+    }
+
+    // Using a challenge does not free its slot before it expires
+    a.Verify(SignedChallenge{Address: address, Nonce: first.Nonce})
+    if _, err := a.Challenge(address); !errors.Is(err, ErrTooManyChallenges) {
+        t.Fatalf("expected ErrTooManyChallenges after a used challenge, got %v", err)
+    }
+
+    // Expired challenges are dropped when the next one is issued
+    a.now = func() time.Time { return start.Add(2 * time.Minute) }
+    if _, err := a.Challenge(address); err != nil {
+        t.Fatalf("challenge after expiry failed: %v", err)
+    }
+    if len(a.challenges) != 1 || len(a.issued) != 1 {
+        t.Fatalf("expected only the new challenge to be kept, got %d pending and %d issued", len(a.challenges), len(a.issued))
+    }
+}
+
+func TestAuthenticateSignedHeaders(t *testing.T) {
+    key := secp256k1.GenPrivKey()
+    address := sdk.AccAddress(key.PubKey().Address()).String()
+    a := NewAuthenticator(time.Minute)
+
+    // The bare address header no longer authenticates anyone
+    req := httptest.NewRequest("PUT", "/admin/tasks/task-1", nil)
+    req.Header.Set(HeaderAddress, address)
+    if _, err := a.Authenticate(req); !errors.Is(err, ErrNoCredentials) {
+        t.Fatalf("expected ErrNoCredentials, got %v", err)
+    }
+
+    challenge, _ := a.Challenge(address)
+    signed := sign(t, key, challenge)
+    req = httptest.NewRequest("PUT", "/admin/tasks/task-1", nil)
+    req.Header.Set(HeaderAddress, signed.Address)
+    req.Header.Set(HeaderNonce, signed.Nonce)
+    req.Header.Set(HeaderPubKey, signed.PubKey)
+    req.Header.Set(HeaderSignature, signed.Signature)
+    if seen, err := a.Authenticate(req); err != nil || seen != address {
+        t.Fatalf("expected authenticated request, got %q %v", seen, err)
+    }
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+    "errors"
+    "net/http"
+    "strings"
+)
+
+// Headers carrying a signed challenge on an authenticated request.
+const (
+    HeaderAddress   = "X-Wallet-Address"
+    HeaderNonce     = "X-Wallet-Nonce"
+    HeaderPubKey    = "X-Wallet-PubKey"
+    HeaderSignature = "X-Wallet-Signature"
+)
+
+// ErrNoCredentials means a request carries no credentials of the kind a
+// RequestAuthenticator understands.
+var ErrNoCredentials = errors.New("credentials required: a signed wallet challenge or a bearer session token")
+
+// RequestAuthenticator proves the wallet address behind an HTTP request.
+type RequestAuthenticator interface {
+    Authenticate(r *http.Request) (string, error)
+}
+
+// SignedChallengeFromRequest reads a signed challenge from the request headers.
+func SignedChallengeFromRequest(r *http.Request) SignedChallenge {
+    return SignedChallenge{
+        Address:   r.Header.Get(HeaderAddress),
+        Nonce:     r.Header.Get(HeaderNonce),
+        PubKey:    r.Header.Get(HeaderPubKey),
+        Signature: r.Header.Get(HeaderSignature),
+    }
+}
+
+// BearerToken returns the token of an "Authorization: Bearer" header.
+func BearerToken(r *http.Request) string {
+    header := r.Header.Get("Authorization")
+    if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+        return strings.TrimSpace(header[7:])
+    }
+    return ""
+}
+
+// Authenticate verifies the signed challenge in the request headers.
+func (a *Authenticator) Authenticate(r *http.Request) (string, error) {
+    signed := SignedChallengeFromRequest(r)
+    if signed.Address == "" || signed.Nonce == "" {
+        return "", ErrNoCredentials
+    }
+    return a.Verify(signed)
+}
+
+// Authenticate validates the bearer access token of the request.
+func (s *SessionManager) Authenticate(r *http.Request) (string, error) {
+    token := BearerToken(r)
+    if token == "" {
+        return "", ErrNoCredentials
+    }
+    claims, err := s.Validate(token, TokenAccess)
+    if err != nil {
+        return "", err
+    }
+    return claims.Subject, nil
+}
+
+// Identify returns the address proven by the first authenticator that finds
+// credentials on the request.
+func Identify(r *http.Request, authenticators ...RequestAuthenticator) (string, error) {
+    for _, authenticator := range authenticators {
+        address, err := authenticator.Authenticate(r)
+        if errors.Is(err, ErrNoCredentials) {
+            continue
+        }
+        return address, err
+    }
+    return "", ErrNoCredentials
+}
@@ -0,0 +1,303 @@
+package auth
+
+import (
+    "crypto/hmac"
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/base64"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "strings"
+    "sync"
+    "time"
+)
+
+// Session lifetimes used when none are configured.
+const (
+    DefaultSessionTTL = 15 * time.Minute
+    DefaultRefreshTTL = 24 * time.Hour
+)
+
+// Token types carried in the typ claim.
+const (
+    TokenAccess  = "access"
+    TokenRefresh = "refresh"
+)
+
+var (
+    ErrInvalidToken = errors.New("invalid session token")
+    ErrExpiredToken = errors.New("session token expired")
+    ErrRevokedToken = errors.New("session token revoked")
+)
+
+// jwtHeader is the only header this package issues or accepts.
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Claims is the payload of a session token.
+type Claims struct {
+    Subject   string `json:"sub"`  // Wallet address
+    Role      string `json:"role"` // types.User role when the token was issued
+    Type      string `json:"typ"`
+    ID        string `json:"jti"`
+    IssuedAt  int64  `json:"iat"`
+    ExpiresAt int64  `json:"exp"`
+}
+
+// Session is the token pair handed to a client after login or refresh.
+type Session struct {
+    AccessToken      string    `json:"access_token"`
+    RefreshToken     string    `json:"refresh_token"`
+    Address          string    `json:"address"`
+    Role             string    `json:"role"`
+    ExpiresAt        time.Time `json:"expires_at"`
+    RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// RoleFunc looks up the current role of an address.
+type RoleFunc func(address string) string
+
+// RevocationStore keeps the IDs of revoked tokens until they expire.
+// store.Store implements it.
+type RevocationStore interface {
+    // Revoke reports false if id was already revoked.
+    Revoke(id string, expiresAt time.Time) (bool, error)
+    IsRevoked(id string) (bool, error)
+    PruneRevocations(now time.Time) error
+}
+
+// SessionManager issues HS256 JWT session tokens and keeps track of revoked
+// ones until they would have expired anyway.
+type SessionManager struct {
+    secret     []byte
+    ttl        time.Duration
+    refreshTTL time.Duration
+    roleOf     RoleFunc
+
+    mu      sync.Mutex // Serializes revocation checks and writes
+    revoked RevocationStore
+    now     func() time.Time
+}
+
+// SessionOption configures a SessionManager.
+type SessionOption func(*SessionManager)
+
+// WithRevocationStore keeps revoked token IDs in r, so logouts and spent
+// refresh tokens stay revoked across restarts. By default they are only
+// kept in memory.
+func WithRevocationStore(r RevocationStore) SessionOption {
+    return func(s *SessionManager) {
+        s.revoked = r
+    }
+}
+
+// NewSessionManager creates a manager signing with secret. Roles are looked
+// up with roleOf whenever a token pair is issued, so refreshed tokens pick
+// up role changes.
+func NewSessionManager(secret []byte, ttl time.Duration, refreshTTL time.Duration, roleOf RoleFunc, opts ...SessionOption) *SessionManager {
+    s := &SessionManager{
+        secret:     secret,
+        ttl:        ttl,
+        refreshTTL: refreshTTL,
+        roleOf:     roleOf,
+        revoked:    memoryRevocations{},
+        now:        time.Now,
+    }
+    for _, opt := range opts {
+        opt(s)
+    }
+    return s
+}
+
+// memoryRevocations is the default RevocationStore. SessionManager.mu
+// guards it.
+type memoryRevocations map[string]time.Time
+
+func (m memoryRevocations) Revoke(id string, expiresAt time.Time) (bool, error) {
+    if _, exists := m[id]; exists {
+        return false, nil
+    }
+    m[id] = expiresAt
+    return true, nil
+}
+
+func (m memoryRevocations) IsRevoked(id string) (bool, error) {
+    _, exists := m[id]
+    return exists, nil
+}
+
+func (m memoryRevocations) PruneRevocations(now time.Time) error {
+    for id, expiresAt := range m {
+        if expiresAt.Before(now) {
+            delete(m, id)
+        }
+    }
+    return nil
+}
+
+// RandomSecret returns a fresh signing secret. Sessions signed with it do
+// not survive a restart.
+func RandomSecret() []byte {
+    secret := make([]byte, 32)
+    if _, err := rand.Read(secret); err != nil {
+        panic(err)
+    }
+    return secret
+}
+
+// Login exchanges a signed challenge, verified by a, for a session.
+func (s *SessionManager) Login(a *Authenticator, signed SignedChallenge) (Session, error) {
+    address, err := a.Verify(signed)
+    if err != nil {
+        return Session{}, err
+    }
+    return s.Issue(address)
+}
+
+// Issue creates a new access/refresh token pair for address.
+func (s *SessionManager) Issue(address string) (Session, error) {
+    now := s.now()
+    role := s.roleOf(address)
+    access, accessExp, err := s.sign(address, role, TokenAccess, now, s.ttl)
+    if err != nil {
+        return Session{}, err
+    }
+    refresh, refreshExp, err := s.sign(address, role, TokenRefresh, now, s.refreshTTL)
+    if err != nil {
+        return Session{}, err
+    }
+    return Session{
+        AccessToken:      access,
+        RefreshToken:     refresh,
+        Address:          address,
+        Role:             role,
+        ExpiresAt:        accessExp,
+        RefreshExpiresAt: refreshExp,
+    }, nil
+}
+
+// Refresh trades a refresh token for a new pair. The old refresh token is
+// revoked in the same step that checks it, so of two concurrent refreshes
+// with one token only the first gets a new pair.
+func (s *SessionManager) Refresh(refreshToken string) (Session, error) {
+    claims, err := s.check(refreshToken, TokenRefresh)
+    if err != nil {
+        return Session{}, err
+    }
+    first, err := s.revoke(claims)
+    if err != nil {
+        return Session{}, err
+    }
+    if !first {
+        return Session{}, ErrRevokedToken
+    }
+    return s.Issue(claims.Subject)
+}
+
+// Revoke invalidates a token of either type before it expires.
+func (s *SessionManager) Revoke(token string) error {
+    claims, err := s.parse(token)
+    if err != nil {
+        return err
+    }
+    _, err = s.revoke(claims)
+    return err
+}
+
+// Validate checks the signature, type, expiry and revocation of token.
+func (s *SessionManager) Validate(token string, tokenType string) (*Claims, error) {
+    claims, err := s.check(token, tokenType)
+    if err != nil {
+        return nil, err
+    }
+
+    s.mu.Lock()
+    revoked, err := s.revoked.IsRevoked(claims.ID)
+    s.mu.Unlock()
+    if err != nil {
+        return nil, fmt.Errorf("failed to check token revocation: %v", err)
+    }
+    if revoked {
+        return nil, ErrRevokedToken
+    }
+    return claims, nil
+}
+
+// check verifies everything about token but its revocation.
+func (s *SessionManager) check(token string, tokenType string) (*Claims, error) {
+    claims, err := s.parse(token)
+    if err != nil {
+        return nil, err
+    }
+    if claims.Type != tokenType {
+        return nil, ErrInvalidToken
+    }
+    if !s.now().Before(time.Unix(claims.ExpiresAt, 0)) {
+        return nil, ErrExpiredToken
+    }
+    return claims, nil
+}
+
+// revoke records the token as revoked and reports whether it was not
+// revoked before. Expired revocations are dropped on the way.
+func (s *SessionManager) revoke(claims *Claims) (bool, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if err := s.revoked.PruneRevocations(s.now()); err != nil {
+        return false, fmt.Errorf("failed to prune revoked tokens: %v", err)
+    }
+    first, err := s.revoked.Revoke(claims.ID, time.Unix(claims.ExpiresAt, 0))
+    if err != nil {
+        return false, fmt.Errorf("failed to revoke token: %v", err)
+    }
+    return first, nil
+}
+
+func (s *SessionManager) sign(address string, role string, tokenType string, now time.Time, ttl time.Duration) (string, time.Time, error) {
+    raw := make([]byte, 16)
+    if _, err := rand.Read(raw); err != nil {
+        return "", time.Time{}, fmt.Errorf("failed to generate token id: %v", err)
+    }
+    expires := now.Add(ttl)
+    payload, err := json.Marshal(Claims{
+        Subject:   address,
+        Role:      role,
+        Type:      tokenType,
+        ID:        hex.EncodeToString(raw),
+        IssuedAt:  now.Unix(),
+        ExpiresAt: expires.Unix(),
+    })
+    if err != nil {
+        return "", time.Time{}, err
+    }
+
+    signingInput := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+    return signingInput + "." + s.mac(signingInput), time.Unix(expires.Unix(), 0).UTC(), nil
+}
+
+// parse verifies the token signature and decodes its claims.
+func (s *SessionManager) parse(token string) (*Claims, error) {
+    parts := strings.Split(token, ".")
+    if len(parts) != 3 || parts[0] != jwtHeader {
+        return nil, ErrInvalidToken
+    }
+    if !hmac.Equal([]byte(parts[2]), []byte(s.mac(parts[0]+"."+parts[1]))) {
+        return nil, ErrInvalidToken
+    }
+    payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+    if err != nil {
+        return nil, ErrInvalidToken
+    }
+    var claims Claims
+    if err := json.Unmarshal(payload, &claims); err != nil || claims.Subject == "" || claims.ID == "" {
+        return nil, ErrInvalidToken
+    }
+    return &claims, nil
+}
+
+func (s *SessionManager) mac(signingInput string) string {
+    h := hmac.New(sha256.New, s.secret)
+    h.Write([]byte(signingInput))
+    return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+    "errors"
+    "net/http/httptest"
+    "path/filepath"
+    "strings"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "bounty-system/internal/store"
+
+    "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestSessionLifecycle(t *testing.T) {
+    key := secp256k1.GenPrivKey()
+    address := sdk.AccAddress(key.PubKey().Address()).String()
+    role := "USER"
+    a := NewAuthenticator(time.Minute)
+    sessions := NewSessionManager([]byte("secret"), time.Minute, time.Hour, func(string) string { return role })
+
+    challenge, _ := a.Challenge(address)
+    session, err := sessions.Login(a, sign(t, key, challenge))
+    if err != nil {
+        t.Fatalf("login failed: %v", err)
+    }
+    if session.Address != address || session.Role != "USER" {
+        t.Fatalf("unexpected session: %+v", session)
+    }
+    claims, err := sessions.Validate(session.AccessToken, TokenAccess)
+    if err != nil || claims.Subject != address {
+        t.Fatalf("validate failed: %+v %v", claims, err)
+    }
+
+    // Token types are not interchangeable
+    if _, err := sessions.Validate(session.RefreshToken, TokenAccess); !errors.Is(err, ErrInvalidToken) {
+        t.Fatalf("expected refresh token to be refused as access token, got %v", err)
+    }
+
+    // Tampering with the payload breaks the signature
+    parts := strings.Split(session.AccessToken, ".")
+    tampered := parts[0] + "." + parts[1] + "x." + parts[2]
+    if _, err := sessions.Validate(tampered, TokenAccess); !errors.Is(err, ErrInvalidToken) {
+        t.Fatalf("expected tampered token to fail, got %v", err)
+    }
+    other := NewSessionManager([]byte("other"), time.Minute, time.Hour, func(string) string { return role })
+    if _, err := other.Validate(session.AccessToken, TokenAccess); !errors.Is(err, ErrInvalidToken) {
+        t.Fatalf("expected token from another secret to fail, got %v", err)
+    }
+
+    // Refresh rotates the pair and picks up role changes
+    role = "ADMIN"
+    refreshed, err := sessions.Refresh(session.RefreshToken)
+    if err != nil || refreshed.Role != "ADMIN" {
+        t.Fatalf("refresh failed: %+v %v", refreshed, err)
+    }
+    if _, err := sessions.Refresh(session.RefreshToken); !errors.Is(err, ErrRevokedToken) {
+        t.Fatalf("expected reused refresh token to fail, got %v", err)
+    }
+
+    if err := sessions.Revoke(refreshed.AccessToken); err != nil {
+        t.Fatalf("revoke failed: %v", err)
+    }
+    if _, err := sessions.Validate(refreshed.AccessToken, TokenAccess); !errors.Is(err, ErrRevokedToken) {
+        t.Fatalf("expected revoked token to fail, got %v", err)
+    }
+
+    sessions.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+    if _, err := sessions.Validate(session.AccessToken, TokenAccess); !errors.Is(err, ErrExpiredToken) {
+        t.Fatalf("expected expired token to fail, got %v", err)
+    }
+}
+
+func TestRevocationsArePersisted(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "bounty.db")
+    st, err := store.OpenBoltStore(path)
+    if err != nil {
+        t.Fatalf("open failed: %v", err)
+    }
+    sessions := NewSessionManager([]byte("secret"), time.Minute, time.Hour, func(string) string { return "USER" }, WithRevocationStore(st))
+    session, _ := sessions.Issue("serv1address")
+
+    // Concurrent refreshes with one token yield a single new pair
+    var wg sync.WaitGroup
+    var refreshed int32
+    for i := 0; i < 8; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            if _, err := sessions.Refresh(session.RefreshToken); err == nil {
+                atomic.AddInt32(&refreshed, 1)
+            } else if !errors.Is(err, ErrRevokedToken) {
+                t.Errorf("unexpected refresh error: %v", err)
+            }
+        }()
+    }
+    wg.Wait()
+    if refreshed != 1 {
+        t.Fatalf("expected exactly one refresh to succeed, got %d", refreshed)
+    }
+    if err := sessions.Revoke(session.AccessToken); err != nil {
+        t.Fatalf("revoke failed: %v", err)
+    }
+    st.Close()
+
+    // After a restart both tokens are still revoked
+    if st, err = store.OpenBoltStore(path); err != nil {
+        t.Fatalf("reopen failed: %v", err)
+    }
+    defer st.Close()
+    restarted := NewSessionManager([]byte("secret"), time.Minute, time.Hour, func(string) string { return "USER" }, WithRevocationStore(st))
+    if _, err := restarted.Validate(session.AccessToken, TokenAccess); !errors.Is(err, ErrRevokedToken) {
+        t.Fatalf("expected the logged out token to stay revoked, got %v", err)
+    }
+    if _, err := restarted.Refresh(session.RefreshToken); !errors.Is(err, ErrRevokedToken) {
+        t.Fatalf("expected the spent refresh token to stay revoked, got %v", err)
+    }
+
+    // Revocations are forgotten once the tokens expired
+    restarted.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+    other, _ := restarted.Issue("serv1other")
+    if err := restarted.Revoke(other.AccessToken); err != nil {
+        t.Fatalf("revoke failed: %v", err)
+    }
+    if revoked, _ := st.IsRevoked(mustClaims(t, restarted, session.RefreshToken).ID); revoked {
+        t.Fatalf("expected the expired revocation to be pruned")
+    }
+}
+
+// mustClaims decodes token without checking expiry or revocation.
+func mustClaims(t *testing.T, s *SessionManager, token string) *Claims {
+    t.Helper()
+    claims, err := s.parse(token)
+    if err != nil {
+        t.Fatalf("parse failed: %v", err)
+    }
+    return claims
+}
+
+func TestIdentifyAcceptsSessionOrSignature(t *testing.T) {
+    key := secp256k1.GenPrivKey()
+    address := sdk.AccAddress(key.PubKey().Address()).String()
+    a := NewAuthenticator(time.Minute)
+    sessions := NewSessionManager(RandomSecret(), time.Minute, time.Hour, func(string) string { return "USER" })
+
+    session, _ := sessions.Issue(address)
+    req := httptest.NewRequest("PUT", "/tasks/task-1/claim", nil)
+    req.Header.Set("Authorization", "Bearer "+session.AccessToken)
+    if seen, err := Identify(req, sessions, a); err != nil || seen != address {
+        t.Fatalf("expected bearer request to pass, got %q %v", seen, err)
+    }
+
+    // A bad token is refused even if other credentials could have been tried
+    req = httptest.NewRequest("PUT", "/tasks/task-1/claim", nil)
+    req.Header.Set("Authorization", "Bearer "+session.RefreshToken)
+    if _, err := Identify(req, sessions, a); err == nil || errors.Is(err, ErrNoCredentials) {
+        t.Fatalf("expected the refresh token to be refused, got %v", err)
+    }
+
+    if _, err := Identify(httptest.NewRequest("PUT", "/tasks/task-1/claim", nil), sessions, a); !errors.Is(err, ErrNoCredentials) {
+        t.Fatalf("expected ErrNoCredentials without credentials, got %v", err)
+    }
+}
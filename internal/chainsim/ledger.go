@@ -0,0 +1,265 @@
+// Package chainsim is a minimal in-process Cosmos chain: it keeps bank
+// balances and account sequences and executes signed bank transactions the
+// way a v0.45 node's ante handler and bank module would.
+package chainsim
+
+import (
+    "fmt"
+    "strings"
+    "sync"
+    "time"
+
+    sdkclient "github.com/cosmos/cosmos-sdk/client"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+    authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+    banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+    "github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+// Simple deterministic gas model, roughly in line with what a v0.45 chain
+// charges for a single bank send.
+const (
+    baseGas    = uint64(50000)
+    gasPerByte = uint64(10)
+    gasPerMsg  = uint64(20000)
+)
+
+// Account is the auth state of one address.
+type Account struct {
+    Address       string
+    AccountNumber uint64
+    Sequence      uint64
+}
+
+// TxResult is the outcome of a delivered transaction.
+type TxResult struct {
+    Hash      string
+    Height    int64
+    Code      uint32
+    Codespace string
+    Log       string
+    GasWanted uint64
+    GasUsed   uint64
+    Memo      string
+    Time      time.Time
+    TxBytes   []byte
+}
+
+// Ledger holds the chain state. Every accepted transaction is committed in
+// its own block.
+type Ledger struct {
+    mu          sync.Mutex
+    chainID     string
+    txConfig    sdkclient.TxConfig
+    balances    map[string]sdk.Coins
+    accounts    map[string]*Account
+    nextAccount uint64
+    height      int64
+    txs         map[string]TxResult
+}
+
+func NewLedger(chainID string, txConfig sdkclient.TxConfig) *Ledger {
+    return &Ledger{
+        chainID:  chainID,
+        txConfig: txConfig,
+        balances: make(map[string]sdk.Coins),
+        accounts: make(map[string]*Account),
+        txs:      make(map[string]TxResult),
+    }
+}
+
+func (l *Ledger) ChainID() string {
+    return l.chainID
+}
+
+// Height returns the height of the latest block.
+func (l *Ledger) Height() int64 {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    return l.height
+}
+
+// Fund mints coins to address, creating its account if needed.
+func (l *Ledger) Fund(address string, coins sdk.Coins) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    l.account(address)
+    l.balances[address] = l.balances[address].Add(coins...)
+}
+
+// Balance returns all coins held by address.
+func (l *Ledger) Balance(address string) sdk.Coins {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    return l.balances[address]
+}
+
+// Account returns the auth state of address. Like a real chain, accounts
+// only exist once they have received funds.
+func (l *Ledger) Account(address string) (Account, bool) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    account, exists := l.accounts[address]
+    if !exists {
+        return Account{}, false
+    }
+    return *account, true
+}
+
+// Tx looks up a delivered transaction by its uppercase hex hash.
+func (l *Ledger) Tx(hash string) (TxResult, bool) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    result, exists := l.txs[strings.ToUpper(hash)]
+    return result, exists
+}
+
+// DeliverTx decodes, authenticates and executes txBytes. A failed tx leaves
+// no trace in the state; its result carries the ABCI error code and log.
+func (l *Ledger) DeliverTx(txBytes []byte) TxResult {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    result := TxResult{
+        Hash:    fmt.Sprintf("%X", tmhash.Sum(txBytes)),
+        Time:    time.Now().UTC(),
+        TxBytes: txBytes,
+    }
+
+    err := l.deliver(txBytes, &result, false)
+    if err != nil {
+        result.Codespace, result.Code, result.Log = sdkerrors.ABCIInfo(err, false)
+        return result
+    }
+
+    l.height++
+    result.Height = l.height
+    l.txs[result.Hash] = result
+    return result
+}
+
+// Simulate executes txBytes like DeliverTx but commits nothing, and like a
+// node's simulate endpoint it ignores the tx's gas limit. GasUsed of the
+// result is the gas the tx needs.
+func (l *Ledger) Simulate(txBytes []byte) TxResult {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    result := TxResult{Hash: fmt.Sprintf("%X", tmhash.Sum(txBytes)), Time: time.Now().UTC()}
+    if err := l.deliver(txBytes, &result, true); err != nil {
+        result.Codespace, result.Code, result.Log = sdkerrors.ABCIInfo(err, false)
+    }
+    return result
+}
+
+func (l *Ledger) deliver(txBytes []byte, result *TxResult, simulate bool) error {
+    decoded, err := l.txConfig.TxDecoder()(txBytes)
+    if err != nil {
+        return sdkerrors.Wrap(sdkerrors.ErrTxDecode, err.Error())
+    }
+    sigTx, ok := decoded.(authsigning.Tx)
+    if !ok {
+        return sdkerrors.Wrap(sdkerrors.ErrTxDecode, "transaction is not signed")
+    }
+    if err := sigTx.ValidateBasic(); err != nil {
+        return err
+    }
+    result.Memo = sigTx.GetMemo()
+    result.GasWanted = sigTx.GetGas()
+    result.GasUsed = baseGas + gasPerByte*uint64(len(txBytes)) + gasPerMsg*uint64(len(sigTx.GetMsgs()))
+    if result.GasUsed > result.GasWanted && !simulate {
+        return sdkerrors.Wrapf(sdkerrors.ErrOutOfGas, "out of gas: limit %d, used %d", result.GasWanted, result.GasUsed)
+    }
+
+    // Ante handler: one signer, known account, matching sequence, valid signature
+    signers := sigTx.GetSigners()
+    if len(signers) != 1 {
+        return sdkerrors.Wrapf(sdkerrors.ErrNotSupported, "expected exactly one signer, got %d", len(signers))
+    }
+    signer := signers[0].String()
+    account, exists := l.accounts[signer]
+    if !exists {
+        return sdkerrors.Wrapf(sdkerrors.ErrUnknownAddress, "account %s does not exist", signer)
+    }
+
+    sigs, err := sigTx.GetSignaturesV2()
+    if err != nil {
+        return sdkerrors.Wrap(sdkerrors.ErrUnauthorized, err.Error())
+    }
+    if len(sigs) != 1 {
+        return sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "expected exactly one signature, got %d", len(sigs))
+    }
+    sig := sigs[0]
+    if !sdk.AccAddress(sig.PubKey.Address()).Equals(signers[0]) {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidPubKey, "pubkey does not match signer address %s", signer)
+    }
+    if sig.Sequence != account.Sequence {
+        return sdkerrors.Wrapf(sdkerrors.ErrWrongSequence, "account sequence mismatch, expected %d, got %d", account.Sequence, sig.Sequence)
+    }
+    signerData := authsigning.SignerData{
+        ChainID:       l.chainID,
+        AccountNumber: account.AccountNumber,
+        Sequence:      account.Sequence,
+    }
+    if err := authsigning.VerifySignature(sig.PubKey, signerData, sig.Data, l.txConfig.SignModeHandler(), sigTx); err != nil {
+        return sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "signature verification failed; please verify account number (%d) and chain-id (%s)", account.AccountNumber, l.chainID)
+    }
+
+    // Work on a copy of the balances so a failing message rolls everything back
+    balances := make(map[string]sdk.Coins, len(l.balances))
+    for addr, coins := range l.balances {
+        balances[addr] = coins
+    }
+
+    fee := sigTx.GetFee()
+    remaining, negative := balances[signer].SafeSub(fee)
+    if negative {
+        return sdkerrors.Wrapf(sdkerrors.ErrInsufficientFunds, "%s is smaller than %s", balances[signer], fee)
+    }
+    balances[signer] = remaining
+
+    // Past the ante handler the fee is paid and the sequence used, even if
+    // a message fails
+    if !simulate {
+        l.balances[signer] = remaining
+        account.Sequence++
+    }
+
+    for _, msg := range sigTx.GetMsgs() {
+        send, ok := msg.(*banktypes.MsgSend)
+        if !ok {
+            return sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unrecognized message type: %T", msg)
+        }
+        remaining, negative := balances[send.FromAddress].SafeSub(send.Amount)
+        if negative {
+            return sdkerrors.Wrapf(sdkerrors.ErrInsufficientFunds, "%s is smaller than %s", balances[send.FromAddress], send.Amount)
+        }
+        balances[send.FromAddress] = remaining
+        balances[send.ToAddress] = balances[send.ToAddress].Add(send.Amount...)
+    }
+
+    if simulate {
+        return nil
+    }
+    for _, msg := range sigTx.GetMsgs() {
+        l.account(msg.(*banktypes.MsgSend).ToAddress)
+    }
+    l.balances = balances
+    return nil
+}
+
+// account returns the account for address, creating it if needed. Callers
+// must hold l.mu.
+func (l *Ledger) account(address string) *Account {
+    account, exists := l.accounts[address]
+    if !exists {
+        account = &Account{Address: address, AccountNumber: l.nextAccount}
+        l.nextAccount++
+        l.accounts[address] = account
+    }
+    return account
+}
@@ -0,0 +1,226 @@
+package chainsim
+
+import (
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+// FakeNode serves the subset of the Cosmos SDK REST gateway the bounty
+// client uses, backed by a Ledger. It is meant for offline integration tests.
+type FakeNode struct {
+    *httptest.Server
+    Ledger *Ledger
+
+    mu      sync.Mutex
+    holding bool
+    mempool [][]byte
+    failed  map[string]TxResult // Held transactions that failed in their block
+}
+
+// NewFakeNode starts an HTTP server for ledger. Callers must Close it.
+func NewFakeNode(ledger *Ledger) *FakeNode {
+    node := &FakeNode{Ledger: ledger, failed: make(map[string]TxResult)}
+    mux := http.NewServeMux()
+    mux.HandleFunc("/cosmos/tx/v1beta1/txs", node.handleBroadcast)
+    mux.HandleFunc("/cosmos/tx/v1beta1/txs/", node.handleGetTx)
+    mux.HandleFunc("/cosmos/tx/v1beta1/simulate", node.handleSimulate)
+    mux.HandleFunc("/cosmos/auth/v1beta1/accounts/", node.handleAccount)
+    mux.HandleFunc("/cosmos/bank/v1beta1/balances/", node.handleBalances)
+    node.Server = httptest.NewServer(mux)
+    return node
+}
+
+type txResponse struct {
+    Height    string `json:"height"`
+    TxHash    string `json:"txhash"`
+    Codespace string `json:"codespace"`
+    Code      uint32 `json:"code"`
+    RawLog    string `json:"raw_log"`
+    GasWanted string `json:"gas_wanted"`
+    GasUsed   string `json:"gas_used"`
+    Timestamp string `json:"timestamp"`
+}
+
+func newTxResponse(result TxResult) txResponse {
+    return txResponse{
+        Height:    strconv.FormatInt(result.Height, 10),
+        TxHash:    result.Hash,
+        Codespace: result.Codespace,
+        Code:      result.Code,
+        RawLog:    result.Log,
+        GasWanted: strconv.FormatUint(result.GasWanted, 10),
+        GasUsed:   strconv.FormatUint(result.GasUsed, 10),
+        Timestamp: result.Time.Format(time.RFC3339),
+    }
+}
+
+// Hold makes the node accept broadcast transactions into a mempool without
+// executing them, like BROADCAST_MODE_SYNC on a real node. They are looked
+// up as not found until Commit puts them in a block, where they can still
+// fail.
+func (n *FakeNode) Hold() {
+    n.mu.Lock()
+    defer n.mu.Unlock()
+    n.holding = true
+}
+
+// Commit delivers the held transactions in order and returns their results.
+func (n *FakeNode) Commit() []TxResult {
+    n.mu.Lock()
+    defer n.mu.Unlock()
+
+    var results []TxResult
+    for _, txBytes := range n.mempool {
+        result := n.Ledger.DeliverTx(txBytes)
+        if result.Code != 0 {
+            n.failed[result.Hash] = result
+        }
+        results = append(results, result)
+    }
+    n.mempool = nil
+    return results
+}
+
+// Mempool returns the number of held transactions.
+func (n *FakeNode) Mempool() int {
+    n.mu.Lock()
+    defer n.mu.Unlock()
+    return len(n.mempool)
+}
+
+func (n *FakeNode) handleBroadcast(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost {
+        writeGRPCError(w, http.StatusMethodNotAllowed, 12, "method not allowed")
+        return
+    }
+
+    var req struct {
+        TxBytes string `json:"tx_bytes"`
+        Mode    string `json:"mode"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeGRPCError(w, http.StatusBadRequest, 3, err.Error())
+        return
+    }
+    txBytes, err := base64.StdEncoding.DecodeString(req.TxBytes)
+    if err != nil {
+        writeGRPCError(w, http.StatusBadRequest, 3, "invalid tx_bytes: "+err.Error())
+        return
+    }
+
+    n.mu.Lock()
+    if n.holding {
+        n.mempool = append(n.mempool, txBytes)
+        n.mu.Unlock()
+        hash := fmt.Sprintf("%X", tmhash.Sum(txBytes))
+        writeJSON(w, map[string]interface{}{"tx_response": txResponse{Height: "0", TxHash: hash, GasWanted: "0", GasUsed: "0"}})
+        return
+    }
+    n.mu.Unlock()
+
+    writeJSON(w, map[string]interface{}{"tx_response": newTxResponse(n.Ledger.DeliverTx(txBytes))})
+}
+
+func (n *FakeNode) handleSimulate(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost {
+        writeGRPCError(w, http.StatusMethodNotAllowed, 12, "method not allowed")
+        return
+    }
+
+    var req struct {
+        TxBytes string `json:"tx_bytes"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeGRPCError(w, http.StatusBadRequest, 3, err.Error())
+        return
+    }
+    txBytes, err := base64.StdEncoding.DecodeString(req.TxBytes)
+    if err != nil {
+        writeGRPCError(w, http.StatusBadRequest, 3, "invalid tx_bytes: "+err.Error())
+        return
+    }
+
+    // Failed simulations come back as gRPC errors, not as a result with a code
+    result := n.Ledger.Simulate(txBytes)
+    if result.Code != 0 {
+        writeGRPCError(w, http.StatusBadRequest, 2, result.Log)
+        return
+    }
+    writeJSON(w, map[string]interface{}{
+        "gas_info": map[string]string{
+            "gas_wanted": strconv.FormatUint(result.GasWanted, 10),
+            "gas_used":   strconv.FormatUint(result.GasUsed, 10),
+        },
+        "result": map[string]interface{}{"log": result.Log, "events": []interface{}{}},
+    })
+}
+
+func (n *FakeNode) handleGetTx(w http.ResponseWriter, r *http.Request) {
+    hash := strings.TrimPrefix(r.URL.Path, "/cosmos/tx/v1beta1/txs/")
+    result, exists := n.Ledger.Tx(hash)
+    if !exists {
+        n.mu.Lock()
+        result, exists = n.failed[strings.ToUpper(hash)]
+        n.mu.Unlock()
+    }
+    if !exists {
+        writeGRPCError(w, http.StatusNotFound, 5, fmt.Sprintf("tx not found: %s", hash))
+        return
+    }
+    writeJSON(w, map[string]interface{}{"tx_response": newTxResponse(result)})
+}
+
+func (n *FakeNode) handleAccount(w http.ResponseWriter, r *http.Request) {
+    address := strings.TrimPrefix(r.URL.Path, "/cosmos/auth/v1beta1/accounts/")
+    account, exists := n.Ledger.Account(address)
+    if !exists {
+        writeGRPCError(w, http.StatusNotFound, 5, fmt.Sprintf("account %s not found: key not found", address))
+        return
+    }
+    writeJSON(w, map[string]interface{}{
+        "account": map[string]interface{}{
+            "@type":          "/cosmos.auth.v1beta1.BaseAccount",
+            "address":        account.Address,
+            "pub_key":        nil,
+            "account_number": strconv.FormatUint(account.AccountNumber, 10),
+            "sequence":       strconv.FormatUint(account.Sequence, 10),
+        },
+    })
+}
+
+func (n *FakeNode) handleBalances(w http.ResponseWriter, r *http.Request) {
+    address := strings.TrimPrefix(r.URL.Path, "/cosmos/bank/v1beta1/balances/")
+    balances := make([]map[string]string, 0)
+    for _, coin := range n.Ledger.Balance(address) {
+        balances = append(balances, map[string]string{"denom": coin.Denom, "amount": coin.Amount.String()})
+    }
+    writeJSON(w, map[string]interface{}{
+        "balances":   balances,
+        "pagination": map[string]string{"next_key": "", "total": strconv.Itoa(len(balances))},
+    })
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(v)
+}
+
+// writeGRPCError mirrors the error body grpc-gateway returns.
+func writeGRPCError(w http.ResponseWriter, status int, code int, message string) {
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(status)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "code":    code,
+        "message": message,
+        "details": []interface{}{},
+    })
+}
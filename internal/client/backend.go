@@ -0,0 +1,124 @@
+package client
+
+import (
+    "flag"
+    "fmt"
+    "os"
+)
+
+// ChainBackend is the chain the client signs transactions for and
+// broadcasts them to.
+type ChainBackend interface {
+    ChainID() string
+    RPCEndpoint() string
+    RESTEndpoint() string
+
+    // AccountInfo returns the account number and next sequence of address.
+    AccountInfo(address string) (SignerAccount, error)
+    // Broadcast submits protobuf TxRaw bytes and returns the tx hash once
+    // the node accepted the transaction, before it is in a block.
+    Broadcast(txBytes []byte) (string, error)
+    // Tx returns the result of a transaction in a block, or ErrTxNotFound
+    // while it is not in one.
+    Tx(hash string) (TxResult, error)
+    // Simulate runs a signed transaction without committing it and returns
+    // the gas it used.
+    Simulate(txBytes []byte) (uint64, error)
+    // Balance returns the amount of denom held by address.
+    Balance(address string, denom string) (string, error)
+}
+
+// BackendError is returned when the chain backend could not be reached or
+// refused a request, as opposed to the request itself being invalid.
+type BackendError struct {
+    Err error
+}
+
+func (e *BackendError) Error() string {
+    return e.Err.Error()
+}
+
+func (e *BackendError) Unwrap() error {
+    return e.Err
+}
+
+// faucet is implemented by backends that can mint test funds.
+type faucet interface {
+    Fund(address string, amount int64)
+}
+
+const (
+    BackendMock = "mock"
+    BackendSim  = "sim"
+    BackendREST = "rest"
+)
+
+// Config selects and configures the chain backend.
+type Config struct {
+    Backend      string
+    ChainID      string
+    RPCEndpoint  string
+    RESTEndpoint string
+}
+
+// DefaultConfig returns the configuration used when nothing is set: the
+// in-memory mock backend.
+func DefaultConfig() Config {
+    return Config{
+        Backend:      BackendMock,
+        ChainID:      "kfchain",
+        RPCEndpoint:  "https://node0.testnet.knowfreedom.io:26657",
+        RESTEndpoint: "https://node0.testnet.knowfreedom.io:1317",
+    }
+}
+
+// ConfigFromEnv overrides DefaultConfig with BOUNTY_CHAIN_BACKEND,
+// BOUNTY_CHAIN_ID, BOUNTY_RPC_ENDPOINT and BOUNTY_REST_ENDPOINT.
+func ConfigFromEnv() Config {
+    cfg := DefaultConfig()
+    for env, field := range map[string]*string{
+        "BOUNTY_CHAIN_BACKEND": &cfg.Backend,
+        "BOUNTY_CHAIN_ID":      &cfg.ChainID,
+        "BOUNTY_RPC_ENDPOINT":  &cfg.RPCEndpoint,
+        "BOUNTY_REST_ENDPOINT": &cfg.RESTEndpoint,
+    } {
+        if value := os.Getenv(env); value != "" {
+            *field = value
+        }
+    }
+    return cfg
+}
+
+// RegisterFlags binds the config to command line flags, using the current
+// values as defaults.
+func (cfg *Config) RegisterFlags(fs *flag.FlagSet) {
+    fs.StringVar(&cfg.Backend, "backend", cfg.Backend, "chain backend: mock, sim or rest")
+    fs.StringVar(&cfg.ChainID, "chain-id", cfg.ChainID, "chain ID to sign transactions for (sim and rest)")
+    fs.StringVar(&cfg.RPCEndpoint, "rpc", cfg.RPCEndpoint, "Tendermint RPC endpoint (rest)")
+    fs.StringVar(&cfg.RESTEndpoint, "rest", cfg.RESTEndpoint, "Cosmos REST endpoint (rest)")
+}
+
+// NewBackend creates the backend selected by cfg.
+func NewBackend(cfg Config) (ChainBackend, error) {
+    switch cfg.Backend {
+    case BackendMock, "":
+        return NewMockBackend(), nil
+    case BackendSim:
+        return NewSimBackend(cfg.ChainID), nil
+    case BackendREST:
+        if cfg.RESTEndpoint == "" {
+            return nil, fmt.Errorf("rest backend requires a REST endpoint")
+        }
+        return NewRESTBackend(cfg.ChainID, cfg.RPCEndpoint, cfg.RESTEndpoint), nil
+    default:
+        return nil, fmt.Errorf("unknown chain backend %q", cfg.Backend)
+    }
+}
+
+// WithBackend makes the client sign for and broadcast to backend instead of
+// the mock chain.
+func WithBackend(backend ChainBackend) Option {
+    return func(c *BlockchainClient) {
+        c.backend = backend
+    }
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+    "fmt"
+    "log"
+    "github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+// mockGasUsed is what the mock backend simulates for any transaction, about
+// what a bank send with a task memo costs on a v0.45 chain.
+const mockGasUsed = uint64(80000)
+
+// MockBackend accepts every transaction without executing it. Every account
+// starts at sequence zero and holds a fixed balance.
+type MockBackend struct{}
+
+func NewMockBackend() *MockBackend {
+    return &MockBackend{}
+}
+
+func (b *MockBackend) ChainID() string {
+    return "mock-chain"
+}
+
+func (b *MockBackend) RPCEndpoint() string {
+    return "mock://localhost:26657"
+}
+
+func (b *MockBackend) RESTEndpoint() string {
+    return "mock://localhost:1317"
+}
+
+func (b *MockBackend) AccountInfo(address string) (SignerAccount, error) {
+    return SignerAccount{}, nil
+}
+
+func (b *MockBackend) Broadcast(txBytes []byte) (string, error) {
+    txHash := fmt.Sprintf("%X", tmhash.Sum(txBytes))
+    log.Printf("Mock: signed transaction %s (%d bytes), not broadcasting", txHash, len(txBytes))
+    return txHash, nil
+}
+
+// Tx reports every transaction as succeeded, since none is ever executed.
+func (b *MockBackend) Tx(hash string) (TxResult, error) {
+    return TxResult{}, nil
+}
+
+// Simulate assumes every transaction needs mockGasUsed gas.
+func (b *MockBackend) Simulate(txBytes []byte) (uint64, error) {
+    return mockGasUsed, nil
+}
+
+func (b *MockBackend) Balance(address string, denom string) (string, error) {
+    return "1000000", nil
+}
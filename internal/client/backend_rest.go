@@ -0,0 +1,222 @@
+package client
+
+import (
+    "bytes"
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "io/ioutil"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// RESTBackend talks to a real node through the Cosmos SDK REST gateway.
+type RESTBackend struct {
+    chainID      string
+    rpcEndpoint  string
+    restEndpoint string
+    httpClient   *http.Client
+}
+
+func NewRESTBackend(chainID string, rpcEndpoint string, restEndpoint string) *RESTBackend {
+    return &RESTBackend{
+        chainID:      chainID,
+        rpcEndpoint:  rpcEndpoint,
+        restEndpoint: strings.TrimRight(restEndpoint, "/"),
+        httpClient:   &http.Client{Timeout: 30 * time.Second},
+    }
+}
+
+func (b *RESTBackend) ChainID() string {
+    return b.chainID
+}
+
+func (b *RESTBackend) RPCEndpoint() string {
+    return b.rpcEndpoint
+}
+
+func (b *RESTBackend) RESTEndpoint() string {
+    return b.restEndpoint
+}
+
+func (b *RESTBackend) AccountInfo(address string) (SignerAccount, error) {
+    var accountInfo struct {
+        Account struct {
+            AccountNumber string `json:"account_number"`
+            Sequence      string `json:"sequence"`
+        } `json:"account"`
+    }
+    if err := b.getJSON("/cosmos/auth/v1beta1/accounts/"+address, &accountInfo); err != nil {
+        return SignerAccount{}, fmt.Errorf("failed to get account info: %v", err)
+    }
+
+    accountNumber, err := strconv.ParseUint(accountInfo.Account.AccountNumber, 10, 64)
+    if err != nil {
+        return SignerAccount{}, fmt.Errorf("invalid account number %q: %v", accountInfo.Account.AccountNumber, err)
+    }
+    sequence, err := strconv.ParseUint(accountInfo.Account.Sequence, 10, 64)
+    if err != nil {
+        return SignerAccount{}, fmt.Errorf("invalid sequence %q: %v", accountInfo.Account.Sequence, err)
+    }
+
+    return SignerAccount{AccountNumber: accountNumber, Sequence: sequence}, nil
+}
+
+func (b *RESTBackend) Broadcast(txBytes []byte) (string, error) {
+    reqBytes, err := json.Marshal(map[string]string{
+        "tx_bytes": base64.StdEncoding.EncodeToString(txBytes),
+        "mode":     "BROADCAST_MODE_SYNC",
+    })
+    if err != nil {
+        return "", fmt.Errorf("failed to marshal broadcast request: %v", err)
+    }
+
+    url := b.restEndpoint + "/cosmos/tx/v1beta1/txs"
+    log.Printf("Submitting transaction to: %s", url)
+
+    resp, err := b.httpClient.Post(url, "application/json", bytes.NewBuffer(reqBytes))
+    if err != nil {
+        return "", fmt.Errorf("failed to submit transaction: %v", err)
+    }
+    defer resp.Body.Close()
+
+    body, err := ioutil.ReadAll(resp.Body)
+    if err != nil {
+        return "", fmt.Errorf("failed to read response: %v", err)
+    }
+
+    var response struct {
+        TxResponse struct {
+            Code   int    `json:"code"`
+            RawLog string `json:"raw_log"`
+            TxHash string `json:"txhash"`
+        } `json:"tx_response"`
+    }
+    if err := json.Unmarshal(body, &response); err != nil {
+        return "", fmt.Errorf("failed to parse response (status %d): %s", resp.StatusCode, string(body))
+    }
+    if response.TxResponse.Code != 0 {
+        return "", fmt.Errorf("transaction failed with code %d: %s", response.TxResponse.Code, response.TxResponse.RawLog)
+    }
+
+    return response.TxResponse.TxHash, nil
+}
+
+// Tx looks a transaction up through the tx service. Nodes answer with
+// NotFound until the transaction is committed.
+func (b *RESTBackend) Tx(hash string) (TxResult, error) {
+    resp, err := b.httpClient.Get(b.restEndpoint + "/cosmos/tx/v1beta1/txs/" + hash)
+    if err != nil {
+        return TxResult{}, fmt.Errorf("failed to get transaction: %v", err)
+    }
+    defer resp.Body.Close()
+
+    body, err := ioutil.ReadAll(resp.Body)
+    if err != nil {
+        return TxResult{}, fmt.Errorf("failed to read response: %v", err)
+    }
+    if resp.StatusCode == http.StatusNotFound || resp.StatusCode != http.StatusOK && strings.Contains(string(body), "not found") {
+        return TxResult{}, ErrTxNotFound
+    }
+    if resp.StatusCode != http.StatusOK {
+        return TxResult{}, fmt.Errorf("failed to get transaction, status: %d, response: %s", resp.StatusCode, string(body))
+    }
+
+    var response struct {
+        TxResponse struct {
+            Height string `json:"height"`
+            Code   uint32 `json:"code"`
+            RawLog string `json:"raw_log"`
+        } `json:"tx_response"`
+    }
+    if err := json.Unmarshal(body, &response); err != nil {
+        return TxResult{}, fmt.Errorf("failed to parse response: %v", err)
+    }
+    height, err := strconv.ParseInt(response.TxResponse.Height, 10, 64)
+    if err != nil {
+        return TxResult{}, fmt.Errorf("invalid height %q: %v", response.TxResponse.Height, err)
+    }
+    return TxResult{Height: height, Code: response.TxResponse.Code, Log: response.TxResponse.RawLog}, nil
+}
+
+// Simulate runs txBytes through the tx service's simulate endpoint.
+func (b *RESTBackend) Simulate(txBytes []byte) (uint64, error) {
+    reqBytes, err := json.Marshal(map[string]string{
+        "tx_bytes": base64.StdEncoding.EncodeToString(txBytes),
+    })
+    if err != nil {
+        return 0, fmt.Errorf("failed to marshal simulate request: %v", err)
+    }
+
+    resp, err := b.httpClient.Post(b.restEndpoint+"/cosmos/tx/v1beta1/simulate", "application/json", bytes.NewBuffer(reqBytes))
+    if err != nil {
+        return 0, fmt.Errorf("failed to simulate transaction: %v", err)
+    }
+    defer resp.Body.Close()
+
+    body, err := ioutil.ReadAll(resp.Body)
+    if err != nil {
+        return 0, fmt.Errorf("failed to read response: %v", err)
+    }
+    var response struct {
+        GasInfo struct {
+            GasUsed string `json:"gas_used"`
+        } `json:"gas_info"`
+        Message string `json:"message"`
+    }
+    if err := json.Unmarshal(body, &response); err != nil {
+        return 0, fmt.Errorf("failed to parse response (status %d): %s", resp.StatusCode, string(body))
+    }
+    if resp.StatusCode != http.StatusOK {
+        return 0, fmt.Errorf("simulation failed (status %d): %s", resp.StatusCode, response.Message)
+    }
+
+    gasUsed, err := strconv.ParseUint(response.GasInfo.GasUsed, 10, 64)
+    if err != nil {
+        return 0, fmt.Errorf("invalid gas used %q: %v", response.GasInfo.GasUsed, err)
+    }
+    return gasUsed, nil
+}
+
+func (b *RESTBackend) Balance(address string, denom string) (string, error) {
+    var response struct {
+        Balances []struct {
+            Denom  string `json:"denom"`
+            Amount string `json:"amount"`
+        } `json:"balances"`
+    }
+    if err := b.getJSON("/cosmos/bank/v1beta1/balances/"+address, &response); err != nil {
+        return "", fmt.Errorf("failed to get balance: %v", err)
+    }
+
+    for _, balance := range response.Balances {
+        if balance.Denom == denom {
+            return balance.Amount, nil
+        }
+    }
+    return "0", nil
+}
+
+// getJSON GETs path from the REST endpoint and decodes the response into v.
+func (b *RESTBackend) getJSON(path string, v interface{}) error {
+    resp, err := b.httpClient.Get(b.restEndpoint + path)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+
+    body, err := ioutil.ReadAll(resp.Body)
+    if err != nil {
+        return fmt.Errorf("failed to read response: %v", err)
+    }
+    if resp.StatusCode != http.StatusOK {
+        return fmt.Errorf("status: %d, response: %s", resp.StatusCode, string(body))
+    }
+    if err := json.Unmarshal(body, v); err != nil {
+        return fmt.Errorf("failed to parse response: %v", err)
+    }
+    return nil
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+    "fmt"
+    "log"
+    "bounty-system/internal/chainsim"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// SimBackend executes transactions against an in-process simulated chain,
+// so balances, sequences and signatures are checked like on a real node.
+type SimBackend struct {
+    ledger *chainsim.Ledger
+}
+
+func NewSimBackend(chainID string) *SimBackend {
+    return &SimBackend{
+        ledger: chainsim.NewLedger(chainID, MakeEncodingConfig().TxConfig),
+    }
+}
+
+// Ledger exposes the simulated chain state, e.g. to fund accounts.
+func (b *SimBackend) Ledger() *chainsim.Ledger {
+    return b.ledger
+}
+
+func (b *SimBackend) ChainID() string {
+    return b.ledger.ChainID()
+}
+
+func (b *SimBackend) RPCEndpoint() string {
+    return "sim://local"
+}
+
+func (b *SimBackend) RESTEndpoint() string {
+    return "sim://local"
+}
+
+func (b *SimBackend) AccountInfo(address string) (SignerAccount, error) {
+    account, exists := b.ledger.Account(address)
+    if !exists {
+        return SignerAccount{}, fmt.Errorf("account %s not found", address)
+    }
+    return SignerAccount{AccountNumber: account.AccountNumber, Sequence: account.Sequence}, nil
+}
+
+func (b *SimBackend) Broadcast(txBytes []byte) (string, error) {
+    result := b.ledger.DeliverTx(txBytes)
+    if result.Code != 0 {
+        return "", fmt.Errorf("transaction failed with code %d: %s", result.Code, result.Log)
+    }
+    log.Printf("Sim: transaction %s committed at height %d", result.Hash, result.Height)
+    return result.Hash, nil
+}
+
+func (b *SimBackend) Tx(hash string) (TxResult, error) {
+    result, exists := b.ledger.Tx(hash)
+    if !exists {
+        return TxResult{}, ErrTxNotFound
+    }
+    return TxResult{Height: result.Height, Code: result.Code, Log: result.Log}, nil
+}
+
+func (b *SimBackend) Simulate(txBytes []byte) (uint64, error) {
+    result := b.ledger.Simulate(txBytes)
+    if result.Code != 0 {
+        return 0, fmt.Errorf("simulation failed with code %d: %s", result.Code, result.Log)
+    }
+    return result.GasUsed, nil
+}
+
+func (b *SimBackend) Balance(address string, denom string) (string, error) {
+    return b.ledger.Balance(address).AmountOf(denom).String(), nil
+}
+
+// Fund mints amount microSERVDR to address.
+func (b *SimBackend) Fund(address string, amount int64) {
+    b.ledger.Fund(address, sdk.NewCoins(sdk.NewInt64Coin(Denom, amount)))
+}
@@ -1,140 +1,771 @@
 package client
 
 import (
+    "encoding/hex"
+    "errors"
     "fmt"
     "log"       
+    "strings"
+    "sync"
+    "time"
     intTypes "bounty-system/internal/types"
+    "bounty-system/internal/store"
     "crypto/sha256"
     "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
     sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
+// testWalletFunds is minted to every generated wallet on backends with a faucet.
+const testWalletFunds = int64(100000000)
+
+// Lookup errors for tasks, proposals and escrows that do not exist.
+var (
+    ErrTaskNotFound     = errors.New("task not found")
+    ErrProposalNotFound = errors.New("proposal not found")
+    ErrEscrowNotFound   = errors.New("escrow not found")
+)
+
 type BlockchainClient struct {
-    tasks          map[string]intTypes.Task
-    adminWallets   map[string]bool
-    walletKeys     map[string]string
-    adminAddress   string            // Store the admin address
+    store          store.Store       // Shared task/user persistence
+    backend        ChainBackend      // Chain transactions are signed for and sent to
+    walletsMu      sync.RWMutex      // Guards walletKeys
+    walletKeys     map[string]*secp256k1.PrivKey
+    adminsMu       sync.Mutex        // Held from checking a role change until it is stored
+    adminAddress   string            // Bootstrap admin; its key is never held here
+    escrowKey      *secp256k1.PrivKey
+    escrowAddress  string            // Holds locked bounties until payout
+    testWallets    []string
+    rejectPolicy   RejectPolicy
+    approvalPolicy ApprovalPolicy
+    governance     GovernancePolicy
+    confirmation   ConfirmPolicy
+    fees           FeePolicy
+    now            func() time.Time  // Clock for governance deadlines
+    encodingConfig EncodingConfig
+    sequences      *sequenceManager
 }
 
-func NewBlockchainClient() *BlockchainClient {
+// Option configures a BlockchainClient.
+type Option func(*BlockchainClient)
+
+// WithStore makes the client persist tasks and admins in s instead of an
+// in-memory store.
+func WithStore(s store.Store) Option {
+    return func(c *BlockchainClient) {
+        c.store = s
+    }
+}
+
+// WithAdmin makes address the bootstrap admin. The client only records its
+// role; the wallet's key stays with its owner, who signs in like anyone else.
+func WithAdmin(address string) Option {
+    return func(c *BlockchainClient) {
+        c.adminAddress = address
+    }
+}
+
+// WithEscrowKey sets the key of the escrow account that locks bounties and
+// signs payouts. Without it a random key is generated, and funds left in
+// escrow are lost when the process exits.
+func WithEscrowKey(key *secp256k1.PrivKey) Option {
+    return func(c *BlockchainClient) {
+        c.escrowKey = key
+    }
+}
+
+// ParsePrivKey decodes a hex-encoded secp256k1 private key, as passed to
+// WithEscrowKey.
+func ParsePrivKey(hexKey string) (*secp256k1.PrivKey, error) {
+    key, err := hex.DecodeString(strings.TrimPrefix(hexKey, "0x"))
+    if err != nil {
+        return nil, fmt.Errorf("invalid private key: %v", err)
+    }
+    if len(key) != secp256k1.PrivKeySize {
+        return nil, fmt.Errorf("invalid private key: want %d bytes, got %d", secp256k1.PrivKeySize, len(key))
+    }
+    return &secp256k1.PrivKey{Key: key}, nil
+}
+
+// RejectPolicy decides what happens to a task whose claim is rejected.
+type RejectPolicy string
+
+const (
+    // RejectReopen puts the task back to OPEN so someone else can claim it.
+    RejectReopen RejectPolicy = "reopen"
+    // RejectClose marks the task REJECTED and refunds the bounty to its creator.
+    RejectClose RejectPolicy = "close"
+)
+
+// WithRejectPolicy sets how RejectClaim treats the task. The default is
+// RejectReopen.
+func WithRejectPolicy(policy RejectPolicy) Option {
+    return func(c *BlockchainClient) {
+        c.rejectPolicy = policy
+    }
+}
+
+// ApprovalPolicy decides how many distinct approvals a claim needs before
+// the bounty is paid out.
+type ApprovalPolicy struct {
+    Threshold string // Bounties above this amount need Required approvals; empty means never
+    Required  int
+}
+
+// requiredFor returns the number of approvals a task with bounty needs.
+func (p ApprovalPolicy) requiredFor(bounty string) int {
+    if p.Threshold == "" || p.Required <= 1 {
+        return 1
+    }
+    threshold, ok := sdk.NewIntFromString(p.Threshold)
+    amount, ok2 := sdk.NewIntFromString(bounty)
+    if !ok || !ok2 || !amount.GT(threshold) {
+        return 1
+    }
+    return p.Required
+}
+
+// WithApprovalPolicy requires several distinct approvals for large bounties.
+// By default one approval pays out.
+func WithApprovalPolicy(policy ApprovalPolicy) Option {
+    return func(c *BlockchainClient) {
+        c.approvalPolicy = policy
+    }
+}
+
+func NewBlockchainClient(opts ...Option) *BlockchainClient {
     client := &BlockchainClient{
-        tasks:          make(map[string]intTypes.Task),
-        adminWallets:   make(map[string]bool),
-        walletKeys:     make(map[string]string),
+        walletKeys:     make(map[string]*secp256k1.PrivKey),
+        rejectPolicy:   RejectReopen,
+        governance:     DefaultGovernancePolicy(),
+        confirmation:   DefaultConfirmPolicy(),
+        fees:           DefaultFeePolicy(),
+        now:            time.Now,
+        encodingConfig: MakeEncodingConfig(),
     }
+    for _, opt := range opts {
+        opt(client)
+    }
+    if client.store == nil {
+        client.store = store.NewMemoryStore()
+    }
+    if client.backend == nil {
+        client.backend = NewMockBackend()
+    }
+    client.sequences = newSequenceManager(client.backend.AccountInfo)
     
-    // Generate initial admin address
-    adminAddr := client.GenerateTestAddress("admin-1")
-    client.adminWallets[adminAddr] = true
-    client.adminAddress = adminAddr
-    
-    log.Printf("Created admin wallet: %s", adminAddr)
+    // The bootstrap admin comes from configuration, never from a seed known to the server
+    if client.adminAddress != "" {
+        client.seedAdmin()
+    } else {
+        log.Printf("Warning: no bootstrap admin configured")
+    }
+
+    // Generate a secondary non-admin wallet for testing
+    userAddr := client.GenerateTestAddress("user-1")
+    client.testWallets = []string{client.adminAddress, userAddr}
+
+    // The escrow account is keyed like any other wallet so it can sign payouts
+    if client.escrowKey == nil {
+        log.Printf("Warning: no escrow key configured, using a random one for this run")
+        client.escrowKey = secp256k1.GenPrivKey()
+    }
+    client.escrowAddress = client.addWallet(client.escrowKey)
     
     return client
 }
 
+// seedAdmin makes the bootstrap admin an admin if the store has none yet.
+// Once admins exist they are only changed through governance, so a restart
+// does not bring back a bootstrap admin that was voted out.
+func (c *BlockchainClient) seedAdmin() {
+    users, err := c.store.ListUsers()
+    if err != nil {
+        log.Printf("Warning: failed to read the admin set, not seeding %s: %v", c.adminAddress, err)
+        return
+    }
+    for _, user := range users {
+        if user.Role == intTypes.ROLE_ADMIN {
+            log.Printf("Admin set already exists, not seeding bootstrap admin %s", c.adminAddress)
+            return
+        }
+    }
+    if err := c.store.PutUser(intTypes.User{Address: c.adminAddress, Role: intTypes.ROLE_ADMIN}); err != nil {
+        log.Printf("Warning: failed to persist admin wallet: %v", err)
+        return
+    }
+    log.Printf("Bootstrap admin wallet: %s", c.adminAddress)
+}
+
 func (c *BlockchainClient) GetAdminAddress() string {
     return c.adminAddress
 }
 
+// GetTestWallets returns the configured admin and the deterministic user
+// wallet created at startup, admin first. The admin is empty when none was
+// configured, and the client never holds its key.
+func (c *BlockchainClient) GetTestWallets() []string {
+    return append([]string(nil), c.testWallets...)
+}
+
+// testKey derives the key of a test wallet from seed. Anyone who knows the
+// seed knows the key, so it is only ever used for unprivileged wallets.
+func testKey(seed string) *secp256k1.PrivKey {
+    hash := sha256.Sum256([]byte(seed))
+    return &secp256k1.PrivKey{Key: hash[:]}
+}
+
+// TestWalletAddress returns the address GenerateTestAddress derives from
+// seed, without registering its key.
+func TestWalletAddress(seed string) string {
+    return sdk.AccAddress(testKey(seed).PubKey().Address()).String()
+}
+
+// GenerateTestAddress registers a wallet keyed by seed. Addresses holding a
+// role other than USER, and the escrow account, are refused: their key would
+// be derivable by anyone who guesses the seed.
 func (c *BlockchainClient) GenerateTestAddress(seed string) string {
-    hasher := sha256.New()
-    hasher.Write([]byte(seed))
-    hash := hasher.Sum(nil)
-    
-    privKey := secp256k1.PrivKey{Key: hash}
-    addr := sdk.AccAddress(privKey.PubKey().Address())
-    bech32Addr := addr.String()
-    
-    // Store the address
-    c.walletKeys[bech32Addr] = seed
+    bech32Addr := TestWalletAddress(seed)
+    if bech32Addr == c.escrowAddress || c.GetRole(bech32Addr) != intTypes.ROLE_USER {
+        log.Printf("Warning: refusing to hold a seed-derived key for privileged wallet %s", bech32Addr)
+        return bech32Addr
+    }
+    c.addWallet(testKey(seed))
     log.Printf("Generated address from seed '%s': %s", seed, bech32Addr)
+    return bech32Addr
+}
+
+// addWallet holds key so the client can sign for its address.
+func (c *BlockchainClient) addWallet(key *secp256k1.PrivKey) string {
+    bech32Addr := sdk.AccAddress(key.PubKey().Address()).String()
+    c.walletsMu.Lock()
+    c.walletKeys[bech32Addr] = key
+    c.walletsMu.Unlock()
     
+    // On test chains, give new wallets enough funds to post bounties and pay fees
+    if f, ok := c.backend.(faucet); ok {
+        f.Fund(bech32Addr, testWalletFunds)
+    }
     return bech32Addr
 }
 
 func (c *BlockchainClient) CreateTask(task intTypes.Task) error {
     if task.ID == "" || task.Title == "" || task.Bounty == "" {
-        return fmt.Errorf("invalid task parameters")
+        return intTypes.Invalidf("invalid task parameters")
+    }
+    if err := c.Authorize(task.Creator, intTypes.ActionCreateTask); err != nil {
+        return err
+    }
+    now := time.Now().UTC()
+    if task.ClaimDeadline != nil && !task.ClaimDeadline.After(now) {
+        return intTypes.Invalidf("claim deadline must be in the future")
+    }
+    if task.SubmissionDeadline != nil && task.ClaimDeadline != nil && task.SubmissionDeadline.Before(*task.ClaimDeadline) {
+        return intTypes.Invalidf("submission deadline must not be before the claim deadline")
+    }
+    
+    // Every task enters the state machine as a fresh OPEN task
+    task.CreatedAt = now
+    task.RequiredApprovals = c.approvalPolicy.requiredFor(task.Bounty)
+    task.Approvals = nil
+    task.Status = ""
+    task.Transitions = nil
+    if _, err := task.Transition(intTypes.TaskStatusOpen, task.Creator, now); err != nil {
+        return err
+    }
+    
+    // Create is atomic, so of two tasks with one ID only the first is kept
+    if err := c.store.Create(task); errors.Is(err, store.ErrExists) {
+        return fmt.Errorf("task %s %w", task.ID, err)
+    } else if err != nil {
+        return fmt.Errorf("failed to store task: %v", err)
     }
     
-    // Store task in memory
-    c.tasks[task.ID] = task
-    log.Printf("Created task: %+v", task)
+    // Lock bounty in escrow, dropping the task again if that fails
+    _, err := c.LockTaskBounty(task, TxHooks{
+        Pending: func(txHash string) {
+            c.recordPending(task, intTypes.TaskEvent{Type: intTypes.TaskEventCreated, Actor: task.Creator, Time: now, TxHash: txHash, Detail: task.Bounty + " " + Denom})
+        },
+        Confirmed: func(txHash string) {
+            c.confirmTx(task.ID, txHash)
+            log.Printf("Created task: %+v and locked bounty", task)
+        },
+        Failed: func(txHash string, err error) {
+            if delErr := c.store.Delete(task.ID); delErr != nil {
+                log.Printf("Warning: failed to roll back task %s: %v", task.ID, delErr)
+            }
+        },
+    })
+    if err != nil {
+        return fmt.Errorf("failed to lock bounty: %w", err)
+    }
     return nil
 }
 
 func (c *BlockchainClient) ListTasks() ([]intTypes.Task, error) {
-    tasks := make([]intTypes.Task, 0, len(c.tasks))
-    for _, task := range c.tasks {
-        tasks = append(tasks, task)
+    return c.store.List()
+}
+
+// GetTask returns the task with the given ID, history included.
+func (c *BlockchainClient) GetTask(taskID string) (intTypes.Task, error) {
+    task, err := c.store.Get(taskID)
+    if errors.Is(err, store.ErrNotFound) {
+        return intTypes.Task{}, ErrTaskNotFound
     }
-    return tasks, nil
+    return task, err
+}
+
+// QueryTasks returns one filtered, sorted page of tasks.
+func (c *BlockchainClient) QueryTasks(q store.TaskQuery) (store.TaskPage, error) {
+    return c.store.Query(q)
 }
 
+// ClaimTask claims an OPEN task for claimer. The claim is taken in the store
+// before the claim transaction is sent, so of several concurrent claims
+// exactly one wins and only the winner's transaction is broadcast.
 func (c *BlockchainClient) ClaimTask(taskID string, claimer string, proof string) error {
-    task, exists := c.tasks[taskID]
-    if !exists {
-        return fmt.Errorf("task not found")
+    if err := c.Authorize(claimer, intTypes.ActionClaimTask); err != nil {
+        return err
     }
     
-    if task.Status != "OPEN" {
-        return fmt.Errorf("task is not open for claiming")
+    var previous intTypes.Task
+    claimed, err := c.store.Update(taskID, func(task *intTypes.Task) error {
+        now := time.Now()
+        if err := CheckClaimable(*task, claimer, proof, now); err != nil {
+            return err
+        }
+        previous = *task
+        if _, err := task.Transition(intTypes.TaskStatusClaimed, claimer, now); err != nil {
+            return err
+        }
+        task.Claimer = claimer
+        task.Proof = proof
+        task.Approvals = nil
+        task.Record(intTypes.TaskEvent{Type: intTypes.TaskEventClaimed, Actor: claimer, Time: now, Detail: proof})
+        return nil
+    })
+    if errors.Is(err, store.ErrNotFound) {
+        return ErrTaskNotFound
+    }
+    if err != nil {
+        return err
     }
     
-    task.Status = "CLAIMED"
-    task.Claimer = claimer
-    task.Proof = proof
-    c.tasks[taskID] = task
+    // Send the claim, giving the task up again if it does not go through
+    _, err = c.submit(claimer, func(account SignerAccount) ([]byte, error) {
+        return c.claimTaskTx(taskID, claimer, proof, account)
+    }, TxHooks{
+        Pending: func(txHash string) {
+            claimed = c.attachTxHash(claimed, txHash)
+        },
+        Confirmed: func(txHash string) {
+            c.confirmTx(taskID, txHash)
+            log.Printf("Task %s claimed by %s", taskID, claimer)
+        },
+        Failed: func(txHash string, err error) {
+            c.revert(previous, claimed, claimer, txHash, err)
+        },
+    })
+    if err != nil {
+        return fmt.Errorf("failed to submit claim transaction: %w", err)
+    }
+    return nil
+}
+
+// CheckClaimable rejects claims by claimer that the task's state or
+// deadlines rule out at now. Like the chain module, it refuses the creator
+// claiming their own task. Once the submission deadline has passed a claim
+// must come with its proof. The indexer runs it too, on claims it reads
+// from the chain.
+func CheckClaimable(task intTypes.Task, claimer string, proof string, now time.Time) error {
+    if !intTypes.CanTransition(task.Status, intTypes.TaskStatusClaimed) {
+        return &intTypes.InvalidTransitionError{TaskID: task.ID, From: task.Status, To: intTypes.TaskStatusClaimed}
+    }
+    if task.Creator == claimer {
+        return intTypes.Invalidf("creator cannot claim own task %s", task.ID)
+    }
+    if task.ClaimExpired(now) {
+        return intTypes.Invalidf("claim deadline for task %s has passed", task.ID)
+    }
+    if proof == "" && task.SubmissionDeadline != nil && !now.Before(*task.SubmissionDeadline) {
+        return intTypes.Invalidf("submission deadline for task %s has passed, a proof is required", task.ID)
+    }
+    return nil
+}
+
+// CheckSubmission rejects a proof from claimer that task does not take at
+// now: it must be claimed by claimer, still without a proof, and within its
+// submission deadline.
+func CheckSubmission(task intTypes.Task, claimer string, now time.Time) error {
+    if task.Status != intTypes.TaskStatusClaimed || task.Claimer != claimer {
+        return intTypes.Invalidf("task %s is not claimed by %s", task.ID, claimer)
+    }
+    if task.Proof != "" {
+        return intTypes.Invalidf("proof for task %s was already submitted", task.ID)
+    }
+    if task.SubmissionExpired(now) {
+        return intTypes.Invalidf("submission deadline for task %s has passed", task.ID)
+    }
+    return nil
+}
+
+// SubmitProof attaches the proof to a claim that was made without one. It
+// must arrive before the task's submission deadline.
+func (c *BlockchainClient) SubmitProof(taskID string, claimer string, proof string) error {
+    if strings.TrimSpace(proof) == "" {
+        return intTypes.Invalidf("proof is required")
+    }
     
-    log.Printf("Task %s claimed by %s", taskID, claimer)
+    var previous intTypes.Task
+    submitted, err := c.store.Update(taskID, func(task *intTypes.Task) error {
+        if err := CheckSubmission(*task, claimer, time.Now()); err != nil {
+            return err
+        }
+        previous = *task
+        task.Proof = proof
+        task.Record(intTypes.TaskEvent{Type: intTypes.TaskEventProofSubmitted, Actor: claimer, Time: time.Now(), Detail: proof})
+        return nil
+    })
+    if errors.Is(err, store.ErrNotFound) {
+        return ErrTaskNotFound
+    }
+    if err != nil {
+        return err
+    }
+    
+    _, err = c.submit(claimer, func(account SignerAccount) ([]byte, error) {
+        return c.submitProofTx(taskID, claimer, proof, account)
+    }, TxHooks{
+        Pending: func(txHash string) {
+            submitted = c.attachTxHash(submitted, txHash)
+        },
+        Confirmed: func(txHash string) {
+            c.confirmTx(taskID, txHash)
+            log.Printf("Proof for task %s submitted by %s", taskID, claimer)
+        },
+        Failed: func(txHash string, err error) {
+            c.revert(previous, submitted, claimer, txHash, err)
+        },
+    })
+    if err != nil {
+        return fmt.Errorf("failed to submit proof transaction: %w", err)
+    }
+    return nil
+}
+
+// ExpiryActor is recorded as the actor of transitions made because a
+// deadline passed.
+const ExpiryActor = "scheduler"
+
+// ExpireTask closes an OPEN task whose claim deadline has passed at now and
+// refunds its bounty to the creator.
+func (c *BlockchainClient) ExpireTask(taskID string, now time.Time) error {
+    var previous intTypes.Task
+    expired, err := c.store.Update(taskID, func(task *intTypes.Task) error {
+        if !task.ClaimExpired(now) {
+            return intTypes.Invalidf("claim deadline for task %s has not passed", taskID)
+        }
+        previous = *task
+        if _, err := task.Transition(intTypes.TaskStatusExpired, ExpiryActor, now); err != nil {
+            return err
+        }
+        task.Record(intTypes.TaskEvent{Type: intTypes.TaskEventExpired, Actor: ExpiryActor, Time: now, Detail: "claim deadline passed"})
+        return nil
+    })
+    if errors.Is(err, store.ErrNotFound) {
+        return ErrTaskNotFound
+    }
+    if err != nil {
+        return err
+    }
+    
+    // Refund the creator, reopening the task if that fails. The scheduler
+    // expires many tasks in one pass, so the refund settles in the background.
+    if err := c.refund(previous, expired, ExpiryActor, c.broadcast); err != nil {
+        return fmt.Errorf("failed to refund bounty: %w", err)
+    }
+    
+    log.Printf("Task %s expired and bounty refund sent", taskID)
+    return nil
+}
+
+// ReleaseClaim puts a task back to OPEN when its claimer missed the
+// submission deadline. The abandoned claim is kept in the rejection history.
+func (c *BlockchainClient) ReleaseClaim(taskID string, now time.Time) error {
+    _, err := c.store.Update(taskID, func(task *intTypes.Task) error {
+        if !task.SubmissionExpired(now) {
+            return intTypes.Invalidf("task %s has no overdue claim", taskID)
+        }
+        claimer := task.Claimer
+        if _, err := task.Transition(intTypes.TaskStatusOpen, ExpiryActor, now); err != nil {
+            return err
+        }
+        task.RejectedClaims = append(task.RejectedClaims, intTypes.RejectedClaim{
+            Claimer:    claimer,
+            Reason:     "submission deadline passed",
+            RejectedBy: ExpiryActor,
+            RejectedAt: now.UTC(),
+        })
+        task.Record(intTypes.TaskEvent{Type: intTypes.TaskEventRejected, Actor: ExpiryActor, Time: now, Detail: "submission deadline passed"})
+        task.Claimer = ""
+        return nil
+    })
+    if errors.Is(err, store.ErrNotFound) {
+        return ErrTaskNotFound
+    }
+    if err != nil {
+        return err
+    }
+    
+    log.Printf("Claim on task %s released after its submission deadline", taskID)
     return nil
 }
 
 func (c *BlockchainClient) ApproveTask(task intTypes.Task, approver string) error {
-    if !c.IsAdmin(approver) {
-        return fmt.Errorf("only admins can approve tasks")
+    if err := c.Authorize(approver, intTypes.ActionApproveTask); err != nil {
+        return err
+    }
+    
+    // Record the approval and, once the quorum is reached, mark the task
+    // completed so it cannot be paid out twice
+    var previous intTypes.Task
+    completed, err := c.store.Update(task.ID, func(existingTask *intTypes.Task) error {
+        if existingTask.Status == intTypes.TaskStatusClaimed && existingTask.Proof == "" {
+            return intTypes.Invalidf("task %s has no proof to approve yet", task.ID)
+        }
+        if existingTask.Status != intTypes.TaskStatusClaimed {
+            return &intTypes.InvalidTransitionError{TaskID: task.ID, From: existingTask.Status, To: intTypes.TaskStatusCompleted}
+        }
+        if approver == existingTask.Claimer {
+            return intTypes.Invalidf("%s cannot approve their own claim on task %s", approver, task.ID)
+        }
+        for _, approval := range existingTask.Approvals {
+            if approval.Approver == approver {
+                return intTypes.Invalidf("%s already approved task %s", approver, task.ID)
+            }
+        }
+        previous = *existingTask
+        
+        now := time.Now()
+        existingTask.Approvals = append(existingTask.Approvals, intTypes.Approval{Approver: approver, ApprovedAt: now.UTC()})
+        existingTask.Record(intTypes.TaskEvent{Type: intTypes.TaskEventApproved, Actor: approver, Time: now})
+        if existingTask.PendingApprovals() > 0 {
+            return nil
+        }
+        _, err := existingTask.Transition(intTypes.TaskStatusCompleted, approver, now)
+        return err
+    })
+    if errors.Is(err, store.ErrNotFound) {
+        return ErrTaskNotFound
+    }
+    if err != nil {
+        return err
+    }
+    if completed.Status != intTypes.TaskStatusCompleted {
+        log.Printf("Task %s approved by %s, %d more approval(s) needed", task.ID, approver, completed.PendingApprovals())
+        return nil
+    }
+    
+    // Distribute tokens to claimer, reverting the approval if that fails. A
+    // payout that is still pending keeps the task COMPLETED, so it cannot be
+    // approved and paid again.
+    _, err = c.DistributeTokens(completed, approver, TxHooks{
+        Pending: func(txHash string) {
+            completed = c.recordPending(completed, intTypes.TaskEvent{Type: intTypes.TaskEventPaid, Actor: approver, Time: time.Now(), TxHash: txHash, Detail: "to " + completed.Claimer})
+        },
+        Confirmed: func(txHash string) {
+            c.confirmTx(task.ID, txHash)
+            log.Printf("Task %s approved by admin %s and tokens distributed", task.ID, approver)
+        },
+        Failed: func(txHash string, err error) {
+            c.revert(previous, completed, approver, txHash, err)
+        },
+    })
+    if err != nil {
+        return fmt.Errorf("failed to distribute tokens: %w", err)
+    }
+    return nil
+}
+
+// RejectClaim turns down the current claim on a task. The claim is moved to
+// the task's rejection history and the task is reopened or closed according
+// to the client's RejectPolicy.
+func (c *BlockchainClient) RejectClaim(taskID string, rejector string, reason string) error {
+    if err := c.Authorize(rejector, intTypes.ActionRejectClaim); err != nil {
+        return err
+    }
+    if strings.TrimSpace(reason) == "" {
+        return intTypes.Invalidf("a reason is required to reject a claim")
+    }
+    
+    next := intTypes.TaskStatusOpen
+    if c.rejectPolicy == RejectClose {
+        next = intTypes.TaskStatusRejected
+    }
+    
+    var previous intTypes.Task
+    rejected, err := c.store.Update(taskID, func(task *intTypes.Task) error {
+        if task.Status != intTypes.TaskStatusClaimed {
+            return &intTypes.InvalidTransitionError{TaskID: taskID, From: task.Status, To: next}
+        }
+        previous = *task
+        if _, err := task.Transition(next, rejector, time.Now()); err != nil {
+            return err
+        }
+        task.RejectedClaims = append(task.RejectedClaims, intTypes.RejectedClaim{
+            Claimer:    previous.Claimer,
+            Proof:      previous.Proof,
+            Reason:     reason,
+            RejectedBy: rejector,
+            RejectedAt: time.Now().UTC(),
+        })
+        task.Record(intTypes.TaskEvent{Type: intTypes.TaskEventRejected, Actor: rejector, Time: time.Now(), Detail: reason})
+        task.Claimer = ""
+        task.Proof = ""
+        task.Approvals = nil
+        return nil
+    })
+    if errors.Is(err, store.ErrNotFound) {
+        return ErrTaskNotFound
+    }
+    if err != nil {
+        return err
     }
     
-    existingTask, exists := c.tasks[task.ID]
-    if !exists {
-        return fmt.Errorf("task not found")
+    // A closed task gives its bounty back, restoring the claim if that fails
+    if rejected.Status == intTypes.TaskStatusRejected {
+        if err := c.refund(previous, rejected, rejector, c.submit); err != nil {
+            return fmt.Errorf("failed to refund bounty: %w", err)
+        }
     }
     
-    if existingTask.Status != "CLAIMED" {
-        return fmt.Errorf("task must be claimed before approval")
+    log.Printf("Claim on task %s rejected by admin %s (%s), task is now %s", taskID, rejector, reason, rejected.Status)
+    return nil
+}
+
+// CancelTask withdraws an OPEN task. Only its creator or a role allowed to
+// cancel any task may cancel it; the escrowed bounty is refunded to the
+// creator.
+func (c *BlockchainClient) CancelTask(taskID string, requester string) error {
+    cancelAny := c.Authorize(requester, intTypes.ActionCancelAnyTask)
+    var previous intTypes.Task
+    cancelled, err := c.store.Update(taskID, func(task *intTypes.Task) error {
+        if task.Creator != requester && cancelAny != nil {
+            return cancelAny
+        }
+        previous = *task
+        now := time.Now()
+        if _, err := task.Transition(intTypes.TaskStatusCancelled, requester, now); err != nil {
+            return err
+        }
+        task.Record(intTypes.TaskEvent{Type: intTypes.TaskEventCancelled, Actor: requester, Time: now})
+        return nil
+    })
+    if errors.Is(err, store.ErrNotFound) {
+        return ErrTaskNotFound
+    }
+    if err != nil {
+        return err
     }
     
-    existingTask.Status = "COMPLETED"
-    c.tasks[task.ID] = existingTask
+    // Refund the creator, reopening the task if that fails
+    if err := c.refund(previous, cancelled, requester, c.submit); err != nil {
+        return fmt.Errorf("failed to refund bounty: %w", err)
+    }
     
-    log.Printf("Task %s approved by admin %s", task.ID, approver)
+    log.Printf("Task %s cancelled by %s and bounty refunded", taskID, requester)
     return nil
 }
 
+// refund returns the bounty of applied to its creator and records the
+// refund in the task history. If the refund fails the task is put back to
+// previous. send decides whether to wait for the refund's block.
+func (c *BlockchainClient) refund(previous intTypes.Task, applied intTypes.Task, requester string, send sendFunc) error {
+    _, err := c.refundTokens(applied, requester, send, TxHooks{
+        Pending: func(txHash string) {
+            applied = c.recordPending(applied, intTypes.TaskEvent{Type: intTypes.TaskEventRefunded, Actor: requester, Time: time.Now(), TxHash: txHash, Detail: "to " + applied.Creator})
+        },
+        Confirmed: func(txHash string) {
+            c.confirmTx(applied.ID, txHash)
+        },
+        Failed: func(txHash string, err error) {
+            c.revert(previous, applied, requester, txHash, err)
+        },
+    })
+    return err
+}
+
+// recordEvent appends event to the history of a task whose chain side
+// effect already happened, so a failure is only logged.
+func (c *BlockchainClient) recordEvent(taskID string, event intTypes.TaskEvent) {
+    _, err := c.store.Update(taskID, func(task *intTypes.Task) error {
+        task.Record(event)
+        return nil
+    })
+    if err != nil {
+        log.Printf("Warning: failed to record %s event for task %s: %v", event.Type, taskID, err)
+    }
+}
+
+// restoreTask undoes a transition whose on-chain side effect failed by putting
+// back the task exactly as it was, transition log included. It is a
+// compare-and-swap: if the task moved on from applied in the meantime it is
+// left alone and store.ErrConflict is returned.
+func (c *BlockchainClient) restoreTask(previous intTypes.Task, applied intTypes.Task) error {
+    _, err := c.store.Update(previous.ID, func(task *intTypes.Task) error {
+        if task.Version != applied.Version {
+            return store.ErrConflict
+        }
+        *task = previous
+        return nil
+    })
+    return err
+}
+
 func (c *BlockchainClient) IsAdmin(address string) bool {
-    return c.adminWallets[address]
+    return c.GetRole(address) == intTypes.ROLE_ADMIN
+}
+
+// Authorize checks the role of address against the policy table.
+func (c *BlockchainClient) Authorize(address string, action intTypes.Action) error {
+    role := c.GetRole(address)
+    if !intTypes.Allowed(role, action) {
+        return &intTypes.ForbiddenError{Address: address, Role: role, Action: action}
+    }
+    return nil
+}
+
+// GetRole returns the stored role of address, ROLE_USER for unknown wallets.
+func (c *BlockchainClient) GetRole(address string) string {
+    user, err := c.store.GetUser(address)
+    if err != nil || user.Role == "" {
+        return intTypes.ROLE_USER
+    }
+    return user.Role
 }
 
 func (c *BlockchainClient) GetChainID() string {
-    return "mock-chain"
+    return c.backend.ChainID()
 }
 
 func (c *BlockchainClient) GetRPCEndpoint() string {
-    return "mock://localhost:26657"
+    return c.backend.RPCEndpoint()
 }
 
 func (c *BlockchainClient) GetRESTEndpoint() string {
-    return "mock://localhost:1317"
+    return c.backend.RESTEndpoint()
 }
 
 func (c *BlockchainClient) ValidateAddress(address string) bool {
     // In mock mode, just check if we've generated this address
+    c.walletsMu.RLock()
+    defer c.walletsMu.RUnlock()
     _, exists := c.walletKeys[address]
     return exists
 }
 
 func (c *BlockchainClient) ListAddresses() []string {
+    c.walletsMu.RLock()
+    defer c.walletsMu.RUnlock()
     addresses := make([]string, 0, len(c.walletKeys))
     for addr := range c.walletKeys {
         addresses = append(addresses, addr)
@@ -143,920 +774,65 @@ func (c *BlockchainClient) ListAddresses() []string {
 }
 
 // Admin management functions
-func (c *BlockchainClient) AddAdmin(address string, requestor string) error {
-    if !c.IsAdmin(requestor) {
-        return fmt.Errorf("only admins can add new admins")
+// AssignRole gives address the role on behalf of requestor, who must be
+// allowed to manage roles. Admins are only added or removed through
+// governance proposals.
+func (c *BlockchainClient) AssignRole(address string, role string, requestor string) error {
+    if err := c.Authorize(requestor, intTypes.ActionManageRoles); err != nil {
+        return err
     }
-    c.adminWallets[address] = true
-    return nil
-}
-
-func (c *BlockchainClient) RemoveAdmin(address string, requestor string) error {
-    if !c.IsAdmin(requestor) {
-        return fmt.Errorf("only admins can remove admins")
+    if !intTypes.ValidRole(role) {
+        return intTypes.Invalidf("unknown role %q", role)
     }
-    if len(c.adminWallets) <= 1 {
-        return fmt.Errorf("cannot remove last admin")
+    if _, err := sdk.AccAddressFromBech32(address); err != nil {
+        return intTypes.Invalidf("invalid address: %v", err)
     }
-    delete(c.adminWallets, address)
+    
+    c.adminsMu.Lock()
+    defer c.adminsMu.Unlock()
+    if role == intTypes.ROLE_ADMIN || c.IsAdmin(address) {
+        return intTypes.Invalidf("admin changes require a governance proposal")
+    }
+    if err := c.store.PutUser(intTypes.User{Address: address, Role: role}); err != nil {
+        return fmt.Errorf("failed to store role: %v", err)
+    }
+    log.Printf("Role of %s set to %s by %s", address, role, requestor)
     return nil
 }
 
-func (c *BlockchainClient) ListAdmins() []string {
-    admins := make([]string, 0, len(c.adminWallets))
-    for admin := range c.adminWallets {
-        admins = append(admins, admin)
+// ListUsers returns every wallet with an assigned role. requestor must be
+// allowed to view roles.
+func (c *BlockchainClient) ListUsers(requestor string) ([]intTypes.User, error) {
+    if err := c.Authorize(requestor, intTypes.ActionViewRoles); err != nil {
+        return nil, err
     }
-    return admins
+    return c.store.ListUsers()
 }
 
+// AddAdmin opens a proposal to make address an admin.
+func (c *BlockchainClient) AddAdmin(address string, requestor string) (intTypes.Proposal, error) {
+    return c.ProposeAdminChange(intTypes.ProposalAddAdmin, address, requestor)
+}
 
+// RemoveAdmin opens a proposal to demote address to a regular user.
+func (c *BlockchainClient) RemoveAdmin(address string, requestor string) (intTypes.Proposal, error) {
+    return c.ProposeAdminChange(intTypes.ProposalRemoveAdmin, address, requestor)
+}
 
-
-
-
-
-
-// package client
-
-// import (
-//     "fmt"
-//     "encoding/json"
-//     "net/http"
-//     "bytes"
-//     "io/ioutil"
-//     "log"
-//     "time"    
-//     intTypes "bounty-system/internal/types"
-//     "encoding/base64"
-//     "crypto/sha256"
-//     "strings"
-    
-//     "github.com/cosmos/cosmos-sdk/codec"
-//     sdkTypes "github.com/cosmos/cosmos-sdk/codec/types"  // Aliased to sdkTypes
-//     sdk "github.com/cosmos/cosmos-sdk/types"
-//     banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
-//     "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
-//     cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
-// )
-
-// type Transaction struct {
-//     Body       TxBody     `json:"body"`
-//     AuthInfo   AuthInfo   `json:"auth_info"`
-//     Signatures []string   `json:"signatures"`
-// }
-
-// type TxBody struct {
-//     Messages      []json.RawMessage `json:"messages"`
-//     Memo          string           `json:"memo"`
-//     TimeoutHeight uint64           `json:"timeout_height"`
-// }
-
-// type AuthInfo struct {
-//     SignerInfos []SignerInfo `json:"signer_infos"`
-//     Fee         Fee          `json:"fee"`
-// }
-
-// type SignerInfo struct {
-//     PublicKey PublicKey `json:"public_key"`
-//     ModeInfo  ModeInfo  `json:"mode_info"`
-//     Sequence  string    `json:"sequence"`
-// }
-
-// type PublicKey struct {
-//     Type  string `json:"@type"`
-//     Key   string `json:"key"`
-// }
-
-// type ModeInfo struct {
-//     Single Single `json:"single"`
-// }
-
-// type Single struct {
-//     Mode string `json:"mode"`
-// }
-
-// type Fee struct {
-//     Amount   []Coin `json:"amount"`
-//     GasLimit uint64 `json:"gas_limit"`
-// }
-
-// type Coin struct {
-//     Denom  string `json:"denom"`
-//     Amount string `json:"amount"`
-// }
-
-// func init() {
-//     config := sdk.GetConfig()
-    
-//     // Set prefixes - the key here is that we need to use compatible charset
-//     // Cosmos SDK uses Bech32 encoding with specific charset rules
-//     // "serv" prefix should work with proper Bech32 encoding
-//     config.SetBech32PrefixForAccount("serv", "servpub")
-//     config.SetBech32PrefixForValidator("servvaloper", "servvaloperpub")
-//     config.SetBech32PrefixForConsensusNode("servvalcons", "servvalconspub")
-    
-//     // Seal the config to prevent further modifications
-//     config.Seal()
-// }
-
-// type EncodingConfig struct {
-//     InterfaceRegistry sdkTypes.InterfaceRegistry  // Changed to sdkTypes
-//     Marshaler         codec.Codec
-//     Amino            *codec.LegacyAmino
-// }
-
-// func MakeEncodingConfig() EncodingConfig {
-//     amino := codec.NewLegacyAmino()
-//     interfaceRegistry := sdkTypes.NewInterfaceRegistry()  // Changed to sdkTypes
-//     marshaler := codec.NewProtoCodec(interfaceRegistry)
-    
-//     // Register crypto interfaces
-//     cryptocodec.RegisterInterfaces(interfaceRegistry)
-    
-//     // Register your custom types
-//     banktypes.RegisterInterfaces(interfaceRegistry)
-    
-//     return EncodingConfig{
-//         InterfaceRegistry: interfaceRegistry,
-//         Marshaler:        marshaler,
-//         Amino:           amino,
-//     }
-// }
-
-// // Constants for task status
-// const (
-//     TaskStatusOpen     = "OPEN"
-//     TaskStatusClaimed  = "CLAIMED"
-//     TaskStatusApproved = "APPROVED"
-//     TaskStatusRejected = "REJECTED"
-// )
-
-// // Account info structures
-// type AccountInfo struct {
-//     Account struct {
-//         Address       string `json:"address"`
-//         AccountNumber string `json:"account_number"`
-//         Sequence     string `json:"sequence"`
-//         PubKey       struct {
-//             Type  string `json:"@type"`
-//             Key   string `json:"key"`
-//         } `json:"pub_key"`
-//     } `json:"account"`
-// }
-
-
-// // Wallet structures
-// type WalletKey struct {
-//     Address    string
-//     PubKey     string
-//     PrivKey    string
-// }
-
-// // BlockchainClient structure
-// type BlockchainClient struct {
-//     rpcEndpoint    string
-//     restEndpoint   string
-//     chainID        string
-//     adminWallets   map[string]bool
-//     walletKeys     map[string]WalletKey
-//     encodingConfig EncodingConfig
-// }
-
-// func NewBlockchainClient() *BlockchainClient {
-//     // Create encoding config
-//     encConfig := MakeEncodingConfig()
-
-//     client := &BlockchainClient{
-//         rpcEndpoint:    "https://node0.testnet.knowfreedom.io:26657",
-//         restEndpoint:   "https://node0.testnet.knowfreedom.io:1317",
-//         chainID:        "kfchain",
-//         adminWallets:   make(map[string]bool),
-//         walletKeys:     make(map[string]WalletKey),
-//         encodingConfig: encConfig,
-//     }
-    
-//     // Generate a deterministic admin address
-//     adminWallet := client.GenerateTestAddress("admin-1")
-//     client.adminWallets[adminWallet] = true
-    
-//     // Create a deterministic private key for the admin wallet
-//     adminPrivKeyBytes := make([]byte, 32)
-//     adminHasher := sha256.New()
-//     adminHasher.Write([]byte("admin-key-1"))
-//     copy(adminPrivKeyBytes, adminHasher.Sum(nil))
-    
-//     // Generate the public key
-//     privKey := secp256k1.PrivKey{Key: adminPrivKeyBytes}
-//     pubKey := privKey.PubKey()
-//     pubKeyBytes := pubKey.Bytes()
-    
-//     // Store the wallet info
-//     client.walletKeys[adminWallet] = WalletKey{
-//         Address: adminWallet,
-//         PubKey:  base64.StdEncoding.EncodeToString(pubKeyBytes),
-//         PrivKey: base64.StdEncoding.EncodeToString(adminPrivKeyBytes),
-//     }
-    
-//     // Generate a secondary non-admin wallet
-//     secondaryWallet := client.GenerateTestAddress("user-1")
-    
-//     // Create a deterministic private key for the secondary wallet
-//     secondaryPrivKeyBytes := make([]byte, 32)
-//     secondaryHasher := sha256.New()
-//     secondaryHasher.Write([]byte("user-key-1"))
-//     copy(secondaryPrivKeyBytes, secondaryHasher.Sum(nil))
-    
-//     // Generate the public key for secondary wallet
-//     secondaryPrivKey := secp256k1.PrivKey{Key: secondaryPrivKeyBytes}
-//     secondaryPubKey := secondaryPrivKey.PubKey()
-//     secondaryPubKeyBytes := secondaryPubKey.Bytes()
-    
-//     client.walletKeys[secondaryWallet] = WalletKey{
-//         Address: secondaryWallet,
-//         PubKey:  base64.StdEncoding.EncodeToString(secondaryPubKeyBytes),
-//         PrivKey: base64.StdEncoding.EncodeToString(secondaryPrivKeyBytes),
-//     }
-
-//     return client
-// }
-
-// func (c *BlockchainClient) GenerateTestAddress(seed string) string {
-//     // Use crypto/sha256 package for deterministic address generation
-//     hasher := sha256.New()
-//     hasher.Write([]byte(seed))
-//     hash := hasher.Sum(nil)
-    
-//     // Create an AccAddress from the first 20 bytes of the hash (standard for Cosmos addresses)
-//     addr := sdk.AccAddress(hash[:20])
-    
-//     // This will automatically use the "serv" prefix configured in init()
-//     bech32Addr := addr.String()
-    
-//     // Verify the generated address
-//     if !strings.HasPrefix(bech32Addr, "serv1") {
-//         log.Printf("Warning: Generated address doesn't have correct prefix: %s", bech32Addr)
-//         return ""
-//     }
-    
-//     log.Printf("Generated valid address from seed '%s': %s", seed, bech32Addr)
-//     return bech32Addr
-// }
-
-// func (c *BlockchainClient) CreateTask(task intTypes.Task) error {
-//     // Validate the task
-//     if task.ID == "" {
-//         return fmt.Errorf("task ID cannot be empty")
-//     }
-    
-//     if task.Title == "" {
-//         return fmt.Errorf("task title cannot be empty")
-//     }
-    
-//     if task.Bounty == "" {
-//         return fmt.Errorf("task bounty cannot be empty")
-//     }
-    
-//     // Validate creator address
-//     if !c.ValidateAddress(task.Creator) {
-//         return fmt.Errorf("invalid creator address: %s", task.Creator)
-//     }
-    
-//     // Convert addresses to bech32
-//     fromAddr, err := sdk.AccAddressFromBech32(task.Creator)
-//     if err != nil {
-//         return fmt.Errorf("invalid creator address: %v", err)
-//     }
-
-//     // For escrow, we can use a derived address or a pre-defined one
-//     // Here we're using a constant for simplicity
-//     escrowAddr := "serv1escrow000000000000000000000000000000"
-//     toAddr, err := sdk.AccAddressFromBech32(escrowAddr)
-//     if err != nil {
-//         // If the constant doesn't work, derive a deterministic escrow address
-//         hasher := sha256.New()
-//         hasher.Write([]byte("escrow"))
-//         hash := hasher.Sum(nil)
-//         toAddr = sdk.AccAddress(hash[:20])
-//         escrowAddr = toAddr.String()
-//     }
-
-//     // Create the message
-//     msg := map[string]interface{}{
-//         "@type":        "/cosmos.bank.v1beta1.MsgSend",
-//         "from_address": fromAddr.String(),
-//         "to_address":   escrowAddr,
-//         "amount": []map[string]string{
-//             {
-//                 "denom":  "microSERVDR",
-//                 "amount": task.Bounty,
-//             },
-//         },
-//     }
-
-//     // Add task metadata
-//     metadata := map[string]interface{}{
-//         "type":        "create_task",
-//         "title":       task.Title,
-//         "description": task.Description,
-//         "status":      "OPEN",
-//         "task_id":     task.ID,
-//     }
-
-//     metadataBytes, err := json.Marshal(metadata)
-//     if err != nil {
-//         return fmt.Errorf("failed to marshal metadata: %v", err)
-//     }
-
-//     msgBytes, err := json.Marshal(msg)
-//     if err != nil {
-//         return fmt.Errorf("failed to marshal message: %v", err)
-//     }
-
-//     // Get account sequence
-//     accountInfo, err := c.getAccountInfo(task.Creator)
-//     if err != nil {
-//         log.Printf("Warning: Failed to get account info, using default sequence: %v", err)
-//         // Continue with default sequence
-//     }
-
-//     // Create transaction
-//     tx := Transaction{
-//         Body: TxBody{
-//             Messages:      []json.RawMessage{msgBytes},
-//             Memo:          string(metadataBytes),
-//             TimeoutHeight: uint64(time.Now().Unix() + 300), // 5 minutes from now
-//         },
-//         AuthInfo: AuthInfo{
-//             SignerInfos: []SignerInfo{
-//                 {
-//                     PublicKey: PublicKey{
-//                         Type: "/cosmos.crypto.secp256k1.PubKey",
-//                         Key:  c.walletKeys[task.Creator].PubKey,
-//                     },
-//                     ModeInfo: ModeInfo{
-//                         Single: Single{
-//                             Mode: "SIGN_MODE_DIRECT",
-//                         },
-//                     },
-//                     Sequence: accountInfo.Account.Sequence,
-//                 },
-//             },
-//             Fee: Fee{
-//                 Amount: []Coin{
-//                     {
-//                         Denom:  "microSERVDR",
-//                         Amount: "1000", // Fixed gas fee
-//                     },
-//                 },
-//                 GasLimit: 200000, // Standard gas limit
-//             },
-//         },
-//         Signatures: make([]string, 0),
-//     }
-
-//     // Sign the transaction
-//     if err := c.signTransaction(&tx, task.Creator); err != nil {
-//         return fmt.Errorf("failed to sign transaction: %v", err)
-//     }
-
-//     // Marshal and submit the transaction
-//     txBytes, err := json.Marshal(tx)
-//     if err != nil {
-//         return fmt.Errorf("failed to marshal transaction: %v", err)
-//     }
-    
-//     return c.submitTransaction(txBytes)
-// }
-
-// // Helper method to get account info including sequence number
-// func (c *BlockchainClient) getAccountInfo(address string) (AccountInfo, error) {
-//     var accountInfo AccountInfo
-    
-//     url := fmt.Sprintf("%s/cosmos/auth/v1beta1/accounts/%s", c.restEndpoint, address)
-//     resp, err := http.Get(url)
-//     if err != nil {
-//         return accountInfo, fmt.Errorf("failed to get account info: %v", err)
-//     }
-//     defer resp.Body.Close()
-    
-//     body, err := ioutil.ReadAll(resp.Body)
-//     if err != nil {
-//         return accountInfo, fmt.Errorf("failed to read response: %v", err)
-//     }
-    
-//     if resp.StatusCode != http.StatusOK {
-//         return accountInfo, fmt.Errorf("failed to get account info, status: %d, response: %s", 
-//             resp.StatusCode, string(body))
-//     }
-    
-//     if err := json.Unmarshal(body, &accountInfo); err != nil {
-//         return accountInfo, fmt.Errorf("failed to parse account info: %v", err)
-//     }
-    
-//     return accountInfo, nil
-// }
-// // Update ApproveTask to use proper message format
-// func (c *BlockchainClient) ApproveTask(task intTypes.Task, approver string) error {
-//     if !c.IsAdmin(approver) {
-//         return fmt.Errorf("only admins can approve tasks")
-//     }
-
-//     msg := map[string]interface{}{
-//         "@type": "/cosmos.bank.v1beta1.MsgSend",
-//         "from_address": "serv1escrow",
-//         "to_address": task.Claimer,
-//         "amount": []map[string]string{
-//             {
-//                 "denom": "microSERVDR",
-//                 "amount": task.Bounty,
-//             },
-//         },
-//     }
-
-//     metadata := map[string]interface{}{
-//         "type": "approve_task",
-//         "task_id": task.ID,
-//         "status": TaskStatusApproved,
-//     }
-//     metadataBytes, err := json.Marshal(metadata)
-//     if err != nil {
-//         return fmt.Errorf("failed to marshal metadata: %v", err)
-//     }
-
-//     msgBytes, err := json.Marshal(msg)
-//     if err != nil {
-//         return fmt.Errorf("failed to marshal message: %v", err)
-//     }
-
-//     tx := Transaction{
-//         Body: TxBody{
-//             Messages: []json.RawMessage{msgBytes},
-//             Memo: string(metadataBytes),
-//             TimeoutHeight: uint64(time.Now().Unix() + 300),
-//         },
-//         AuthInfo: AuthInfo{
-//             SignerInfos: []SignerInfo{
-//                 {
-//                     PublicKey: PublicKey{
-//                         Type: "/cosmos.crypto.secp256k1.PubKey",
-//                         Key: c.walletKeys[approver].PubKey,
-//                     },
-//                     ModeInfo: ModeInfo{
-//                         Single: Single{
-//                             Mode: "SIGN_MODE_DIRECT",
-//                         },
-//                     },
-//                     Sequence: "0",
-//                 },
-//             },
-//             Fee: Fee{
-//                 Amount: []Coin{
-//                     {
-//                         Denom: "microSERVDR",
-//                         Amount: "1000",
-//                     },
-//                 },
-//                 GasLimit: 200000,
-//             },
-//         },
-//         Signatures: make([]string, 0),
-//     }
-
-//     txBytes, err := json.Marshal(tx)
-//     if err != nil {
-//         return fmt.Errorf("failed to marshal transaction: %v", err)
-//     }
-    
-//     return c.submitTransaction(txBytes)
-// }
-
-// func (c *BlockchainClient) submitTransaction(txBytes []byte) error {
-//     // Create broadcast request
-//     broadcastReq := map[string]interface{}{
-//         "tx_bytes": base64.StdEncoding.EncodeToString(txBytes),
-//         "mode":     "BROADCAST_MODE_SYNC",
-//     }
-
-//     reqBytes, err := json.Marshal(broadcastReq)
-//     if err != nil {
-//         return fmt.Errorf("failed to marshal broadcast request: %v", err)
-//     }
-
-//     url := fmt.Sprintf("%s/cosmos/tx/v1beta1/txs", c.restEndpoint)
-//     log.Printf("Submitting transaction to: %s", url)
-//     log.Printf("Transaction payload: %s", string(reqBytes))
-
-//     resp, err := http.Post(
-//         url,
-//         "application/json",
-//         bytes.NewBuffer(reqBytes),
-//     )
-//     if err != nil {
-//         return fmt.Errorf("failed to submit transaction: %v", err)
-//     }
-//     defer resp.Body.Close()
-
-//     body, err := ioutil.ReadAll(resp.Body)
-//     if err != nil {
-//         return fmt.Errorf("failed to read response: %v", err)
-//     }
-
-//     log.Printf("Response status: %d", resp.StatusCode)
-//     log.Printf("Response body: %s", string(body))
-
-//     var response struct {
-//         TxResponse struct {
-//             Code      int    `json:"code"`
-//             Data      string `json:"data"`
-//             RawLog    string `json:"raw_log"`
-//             TxHash    string `json:"txhash"`
-//             GasWanted string `json:"gas_wanted"`
-//             GasUsed   string `json:"gas_used"`
-//         } `json:"tx_response"`
-//     }
-
-//     if err := json.Unmarshal(body, &response); err != nil {
-//         return fmt.Errorf("failed to parse response: %v", err)
-//     }
-
-//     if response.TxResponse.Code != 0 {
-//         return fmt.Errorf(response.TxResponse.RawLog)
-//     }
-
-//     return nil
-// }
-
-// func (c *BlockchainClient) ClaimTask(taskID string, claimer string, proof string) error {
-//     msg := map[string]interface{}{
-//         "@type":       "/cosmos.bank.v1beta1.MsgSend",
-//         "from_address": claimer,
-//         "proof":       proof,
-//         "status":      TaskStatusClaimed,
-//     }
-
-//     msgBytes, err := json.Marshal(msg)
-//     if err != nil {
-//         return fmt.Errorf("failed to marshal message: %v", err)
-//     }
-
-//     tx := Transaction{
-//         Body: TxBody{
-//             Messages:      []json.RawMessage{msgBytes},
-//             Memo:         fmt.Sprintf("claim-%s", taskID),
-//             TimeoutHeight: uint64(time.Now().Unix() + 300),
-//         },
-//         AuthInfo: AuthInfo{
-//             SignerInfos: []SignerInfo{
-//                 {
-//                     PublicKey: PublicKey{
-//                         Type: "/cosmos.crypto.secp256k1.PubKey",
-//                         Key:  c.walletKeys[claimer].PubKey,
-//                     },
-//                     ModeInfo: ModeInfo{
-//                         Single: Single{
-//                             Mode: "SIGN_MODE_DIRECT",
-//                         },
-//                     },
-//                     Sequence: "0",
-//                 },
-//             },
-//             Fee: Fee{
-//                 Amount: []Coin{
-//                     {
-//                         Denom:  "microSERVDR",
-//                         Amount: "1000",
-//                     },
-//                 },
-//                 GasLimit: 200000,
-//             },
-//         },
-//         Signatures: make([]string, 0),
-//     }
-
-//     txBytes, err := json.Marshal(tx)
-//     if err != nil {
-//         return fmt.Errorf("failed to marshal transaction: %v", err)
-//     }
-    
-//     return c.submitTransaction(txBytes)
-// }
-
-// // Improved transaction signing method
-
-// func (c *BlockchainClient) signTransaction(tx *Transaction, signer string) error {
-//     wallet, exists := c.walletKeys[signer]
-//     if !exists {
-//         return fmt.Errorf("wallet key not found for %s", signer)
-//     }
-
-//     // In a production environment, you would use a secure way to handle private keys
-//     // For this example, we'll create a deterministic key from the private key string
-//     // WARNING: This is for development only and is NOT secure for production!
-    
-//     // Create a SHA-256 hash of the private key string to get consistent bytes
-//     hasher := sha256.New()
-//     hasher.Write([]byte(wallet.PrivKey))
-//     privKeyBytes := hasher.Sum(nil)
-    
-//     // Create the private key
-//     privKey := secp256k1.PrivKey{Key: privKeyBytes}
-
-//     // Sign documents need to be created according to the Cosmos SDK standards
-//     // For SIGN_MODE_DIRECT, we need to sign the serialized transaction
-    
-//     // For a real implementation, you would use the proper proto encoding
-//     // This is a simplified example
-//     signBytes, err := json.Marshal(struct {
-//         Body       TxBody   `json:"body"`
-//         AuthInfo   AuthInfo `json:"auth_info"`
-//         ChainID    string   `json:"chain_id"`
-//     }{
-//         Body:     tx.Body,
-//         AuthInfo: tx.AuthInfo,
-//         ChainID:  c.chainID,
-//     })
-    
-//     if err != nil {
-//         return fmt.Errorf("failed to marshal sign docs: %v", err)
-//     }
-
-//     // Create signature
-//     signature, err := privKey.Sign(signBytes)
-//     if err != nil {
-//         return fmt.Errorf("failed to sign transaction: %v", err)
-//     }
-
-//     // Add signature to transaction
-//     tx.Signatures = append(tx.Signatures, base64.StdEncoding.EncodeToString(signature))
-
-//     return nil
-// }
-
-
-// // Admin management functions
-// func (c *BlockchainClient) IsAdmin(address string) bool {
-//     return c.adminWallets[address]
-// }
-
-// func (c *BlockchainClient) GetTestWallets() []string {
-//     return []string{
-//         "cosmos1jv65s3grqf6v6jl3dp4t6c9t9rk99cd80udgsy", // Valid Cosmos SDK address
-//         "cosmos1xyxs3skf3f4jfqeuv89yyaqvjc6lffavxqhc8g",  // Valid Cosmos SDK address
-//     }
-// }
-
-
-// func (c *BlockchainClient) GetChainID() string {
-//     return c.chainID
-// }
-
-// func (c *BlockchainClient) GetRPCEndpoint() string {
-//     return c.rpcEndpoint
-// }
-
-// func (c *BlockchainClient) GetRESTEndpoint() string {
-//     return c.restEndpoint
-// }
-
-// // Improved implementation for the ListTasks method in BlockchainClient
-
-// func (c *BlockchainClient) ListTasks() ([]intTypes.Task, error) {
-//     // In a real blockchain implementation, we need to query transactions
-//     // with our task metadata in the memo field
-//     url := fmt.Sprintf("%s/cosmos/tx/v1beta1/txs", c.restEndpoint)
-    
-//     // For a real implementation, we would need pagination and filtering
-//     // Here we're keeping it simple but would need to be expanded for production
-//     requestBody := map[string]interface{}{
-//         "pagination": map[string]interface{}{
-//             "limit": "100",  // Limit to latest 100 transactions
-//         },
-//         // You might add custom events or filters specific to your chain
-//         "events": []string{
-//             "message.action='/cosmos.bank.v1beta1.MsgSend'",
-//             // You could add other filters here
-//         },
-//     }
-    
-//     requestBytes, err := json.Marshal(requestBody)
-//     if err != nil {
-//         return nil, fmt.Errorf("failed to marshal request: %v", err)
-//     }
-    
-//     // Make the HTTP POST request to get transactions
-//     resp, err := http.Post(url, "application/json", bytes.NewBuffer(requestBytes))
-//     if err != nil {
-//         return nil, fmt.Errorf("failed to fetch transactions: %v", err)
-//     }
-//     defer resp.Body.Close()
-    
-//     if resp.StatusCode != http.StatusOK {
-//         body, _ := ioutil.ReadAll(resp.Body)
-//         return nil, fmt.Errorf("failed to query transactions, status: %d, response: %s", 
-//             resp.StatusCode, string(body))
-//     }
-    
-//     // Parse the response
-//     body, err := ioutil.ReadAll(resp.Body)
-//     if err != nil {
-//         return nil, fmt.Errorf("failed to read response: %v", err)
-//     }
-    
-//     var response struct {
-//         TxResponses []struct {
-//             TxHash    string          `json:"txhash"`
-//             Height    string          `json:"height"`
-//             Timestamp string          `json:"timestamp"`
-//             Code      int             `json:"code"`
-//             RawLog    string          `json:"raw_log"`
-//             Tx        json.RawMessage `json:"tx"`
-//         } `json:"tx_responses"`
-//     }
-    
-//     if err := json.Unmarshal(body, &response); err != nil {
-//         return nil, fmt.Errorf("failed to parse response: %v", err)
-//     }
-    
-//     // Process transactions to extract tasks
-//     var tasks []intTypes.Task
-    
-//     for _, txResp := range response.TxResponses {
-//         // Skip failed transactions
-//         if txResp.Code != 0 {
-//             continue
-//         }
-        
-//         // Parse the transaction
-//         var tx Transaction
-//         if err := json.Unmarshal(txResp.Tx, &tx); err != nil {
-//             log.Printf("Warning: Failed to parse transaction %s: %v", txResp.TxHash, err)
-//             continue
-//         }
-        
-//         // Check if the memo contains task metadata
-//         if tx.Body.Memo == "" {
-//             continue
-//         }
-        
-//         // Try to parse the memo as task metadata
-//         var metadata map[string]interface{}
-//         if err := json.Unmarshal([]byte(tx.Body.Memo), &metadata); err != nil {
-//             // Not a task metadata, skip
-//             continue
-//         }
-        
-//         // Check if it's a task-related transaction
-//         taskType, ok := metadata["type"].(string)
-//         if !ok {
-//             continue
-//         }
-        
-//         // Process based on task type
-//         switch taskType {
-//         case "create_task":
-//             taskID, _ := metadata["task_id"].(string)
-//             title, _ := metadata["title"].(string)
-//             description, _ := metadata["description"].(string)
-//             status, _ := metadata["status"].(string)
-            
-//             // Extract the sender and amount from the message
-//             if len(tx.Body.Messages) == 0 {
-//                 continue
-//             }
-            
-//             var msg map[string]interface{}
-//             if err := json.Unmarshal(tx.Body.Messages[0], &msg); err != nil {
-//                 log.Printf("Warning: Failed to parse message in tx %s: %v", txResp.TxHash, err)
-//                 continue
-//             }
-            
-//             creator, _ := msg["from_address"].(string)
-            
-//             // Get the bounty amount
-//             bounty := "0"
-//             if amounts, ok := msg["amount"].([]interface{}); ok && len(amounts) > 0 {
-//                 if amount, ok := amounts[0].(map[string]interface{}); ok {
-//                     bounty, _ = amount["amount"].(string)
-//                 }
-//             }
-            
-//             task := intTypes.Task{
-//                 ID:          taskID,
-//                 Title:       title,
-//                 Description: description,
-//                 Creator:     creator,
-//                 Bounty:      bounty,
-//                 Status:      status,
-//             }
-            
-//             tasks = append(tasks, task)
-            
-//         case "claim_task":
-//             taskID, _ := metadata["task_id"].(string)
-            
-//             // Find the existing task
-//             var existingTask *intTypes.Task
-//             for i, t := range tasks {
-//                 if t.ID == taskID {
-//                     existingTask = &tasks[i]
-//                     break
-//                 }
-//             }
-            
-//             if existingTask == nil {
-//                 // Task not found, could be a task created before our query window
-//                 continue
-//             }
-            
-//             // Update the task status
-//             existingTask.Status = "CLAIMED"
-            
-//             // Extract claimer from message
-//             if len(tx.Body.Messages) > 0 {
-//                 var msg map[string]interface{}
-//                 if err := json.Unmarshal(tx.Body.Messages[0], &msg); err != nil {
-//                     continue
-//                 }
-                
-//                 claimer, _ := msg["from_address"].(string)
-//                 proof, _ := msg["proof"].(string)
-                
-//                 existingTask.Claimer = claimer
-//                 existingTask.Proof = proof
-//             }
-            
-//         case "approve_task":
-//             taskID, _ := metadata["task_id"].(string)
-            
-//             // Find the existing task
-//             var existingTask *intTypes.Task
-//             for i, t := range tasks {
-//                 if t.ID == taskID {
-//                     existingTask = &tasks[i]
-//                     break
-//                 }
-//             }
-            
-//             if existingTask == nil {
-//                 // Task not found, could be a task created before our query window
-//                 continue
-//             }
-            
-//             // Update the task status
-//             existingTask.Status = "APPROVED"
-//         }
-//     }
-    
-//     // If we couldn't find any tasks from the blockchain, return mock data for testing
-//     if len(tasks) == 0 {
-//         log.Printf("Warning: No tasks found on blockchain, returning mock tasks for testing")
-//         tasks = []intTypes.Task{
-//             {
-//                 ID:          "task-1",
-//                 Title:       "Test Task 1",
-//                 Description: "This is a test task from blockchain",
-//                 Creator:     "serv1ph3trl8q0mtc6dfz5xsqq6pksl7w7rxx0hk3q",
-//                 Bounty:      "1000",
-//                 Status:      "OPEN",
-//             },
-//             {
-//                 ID:          "task-2",
-//                 Title:       "Test Task 2",
-//                 Description: "Another test task from blockchain",
-//                 Creator:     "serv1mj9k8h7l6n5m4p3q2r1s0t9v8w7x6y5z4a3b",
-//                 Bounty:      "2000",
-//                 Status:      "CLAIMED",
-//                 Claimer:     "serv1ph3trl8q0mtc6dfz5xsqq6pksl7w7rxx0hk3q",
-//                 Proof:       "Test proof of work",
-//             },
-//         }
-//     }
-    
-//     return tasks, nil
-// }
-
-// func (c *BlockchainClient) ValidateAddress(address string) bool {
-//     // Check if the address is empty
-//     if address == "" {
-//         return false
-//     }
-    
-//     // Check if the address has the correct prefix - allow both serv1 format and cosmos1 format for testing
-//     if !strings.HasPrefix(address, "serv1") && !strings.HasPrefix(address, "cosmos1") {
-//         log.Printf("Address validation failed for %s: incorrect prefix", address)
-//         return false
-//     }
-    
-//     // Try to decode the address
-//     _, err := sdk.AccAddressFromBech32(address)
-//     if err != nil {
-//         log.Printf("Address validation failed for %s: %v", address, err)
-//         return false
-//     }
-    
-//     return true
-// }
+func (c *BlockchainClient) ListAdmins() []string {
+    users, err := c.store.ListUsers()
+    if err != nil {
+        log.Printf("Warning: failed to list admins: %v", err)
+        return []string{}
+    }
+    admins := make([]string, 0, len(users))
+    for _, user := range users {
+        if user.Role == intTypes.ROLE_ADMIN {
+            admins = append(admins, user.Address)
+        }
+    }
+    return admins
+}
 
 // Token distribution related functions
 type TokenDistribution struct {
@@ -1067,193 +843,129 @@ type TokenDistribution struct {
     TxHash      string
 }
 
-func (c *BlockchainClient) DistributeTokens(task intTypes.Task) error {
-    // Mock version
-    log.Printf("Mock: Distributing %s microSERVDR tokens to %s for task %s", 
-        task.Bounty, task.Claimer, task.ID)
-    return nil
-
-    /* Real blockchain version (commented out)
-    // Create token transfer message
-    msg := map[string]interface{}{
-        "@type": "/cosmos.bank.v1beta1.MsgSend",
-        "from_address": c.GetEscrowAddress(),  // Task bounty is held in escrow
-        "to_address": task.Claimer,
-        "amount": []map[string]string{
-            {
-                "denom": "microSERVDR",
-                "amount": task.Bounty,
-            },
-        },
+// DistributeTokens pays a task's escrowed bounty out to its claimer and
+// returns the payout transaction hash. The escrow entry is only marked
+// released once the transaction succeeded in a block, before hooks'
+// Confirmed hook runs.
+func (c *BlockchainClient) DistributeTokens(task intTypes.Task, approver string, hooks TxHooks) (string, error) {
+    escrow, err := c.store.GetEscrow(task.ID)
+    if err != nil {
+        return "", fmt.Errorf("no escrow found for task %s: %v", task.ID, err)
     }
-
-    // Add distribution metadata
-    metadata := map[string]interface{}{
-        "type": "token_distribution",
-        "task_id": task.ID,
-        "distribution_type": "task_completion",
+    if escrow.Status != intTypes.ESCROW_LOCKED {
+        return "", fmt.Errorf("escrow for task %s is %s, not locked", task.ID, escrow.Status)
     }
+    
+    return c.submit(c.GetEscrowAddress(), func(account SignerAccount) ([]byte, error) {
+        return c.approveTaskTx(task, approver, account)
+    }, hooks.confirmedFirst(func(txHash string) {
+        escrow.Status = intTypes.ESCROW_RELEASED
+        escrow.Recipient = task.Claimer
+        escrow.ReleaseTxHash = txHash
+        if err := c.store.PutEscrow(escrow); err != nil {
+            // The payout is already on chain; only the local ledger is stale
+            log.Printf("Warning: failed to record escrow release for task %s: %v", task.ID, err)
+        }
+        log.Printf("Distributed %s %s to %s for task %s (tx %s)", task.Bounty, Denom, task.Claimer, task.ID, txHash)
+    }))
+}
+
+// RefundTokens returns a task's escrowed bounty to its creator and returns
+// the refund transaction hash. Like DistributeTokens it marks the escrow
+// entry refunded once the transaction succeeded.
+func (c *BlockchainClient) RefundTokens(task intTypes.Task, requester string, hooks TxHooks) (string, error) {
+    return c.refundTokens(task, requester, c.submit, hooks)
+}
 
-    metadataBytes, err := json.Marshal(metadata)
+func (c *BlockchainClient) refundTokens(task intTypes.Task, requester string, send sendFunc, hooks TxHooks) (string, error) {
+    escrow, err := c.store.GetEscrow(task.ID)
     if err != nil {
-        return fmt.Errorf("failed to marshal metadata: %v", err)
+        return "", fmt.Errorf("no escrow found for task %s: %v", task.ID, err)
     }
-
-    // Create and sign transaction
-    tx := Transaction{
-        Body: TxBody{
-            Messages: []json.RawMessage{msgBytes},
-            Memo: string(metadataBytes),
-            TimeoutHeight: uint64(time.Now().Unix() + 300),
-        },
-        AuthInfo: AuthInfo{
-            SignerInfos: []SignerInfo{
-                {
-                    PublicKey: PublicKey{
-                        Type: "/cosmos.crypto.secp256k1.PubKey",
-                        Key: c.walletKeys[c.GetEscrowAddress()].PubKey,
-                    },
-                    ModeInfo: ModeInfo{
-                        Single: Single{
-                            Mode: "SIGN_MODE_DIRECT",
-                        },
-                    },
-                    Sequence: "0",
-                },
-            },
-            Fee: Fee{
-                Amount: []Coin{
-                    {
-                        Denom: "microSERVDR",
-                        Amount: "1000",
-                    },
-                },
-                GasLimit: 200000,
-            },
-        },
-        Signatures: make([]string, 0),
+    if escrow.Status != intTypes.ESCROW_LOCKED {
+        return "", fmt.Errorf("escrow for task %s is %s, not locked", task.ID, escrow.Status)
     }
-
-    return c.submitTransaction(txBytes)
-    */
+    
+    return send(c.GetEscrowAddress(), func(account SignerAccount) ([]byte, error) {
+        return c.refundTaskTx(task, requester, account)
+    }, hooks.confirmedFirst(func(txHash string) {
+        escrow.Status = intTypes.ESCROW_REFUNDED
+        escrow.Recipient = task.Creator
+        escrow.ReleaseTxHash = txHash
+        if err := c.store.PutEscrow(escrow); err != nil {
+            log.Printf("Warning: failed to record escrow refund for task %s: %v", task.ID, err)
+        }
+        log.Printf("Refunded %s %s to %s for task %s (tx %s)", task.Bounty, Denom, task.Creator, task.ID, txHash)
+    }))
 }
 
 func (c *BlockchainClient) GetEscrowAddress() string {
-    // For mock mode, return a constant address
-    return "serv1escrow000000000000000000000000000000"
-
-    /* Real blockchain version (commented out)
-    // Generate deterministic escrow address
-    hasher := sha256.New()
-    hasher.Write([]byte("escrow"))
-    hash := hasher.Sum(nil)
-    addr := sdk.AccAddress(hash[:20])
-    return addr.String()
-    */
+    return c.escrowAddress
 }
 
-func (c *BlockchainClient) LockTaskBounty(task intTypes.Task) error {
-    // Mock version
-    log.Printf("Mock: Locking %s microSERVDR tokens from %s in escrow for task %s", 
-        task.Bounty, task.Creator, task.ID)
-    return nil
-
-    /* Real blockchain version (commented out)
-    // Transfer tokens to escrow
-    msg := map[string]interface{}{
-        "@type": "/cosmos.bank.v1beta1.MsgSend",
-        "from_address": task.Creator,
-        "to_address": c.GetEscrowAddress(),
-        "amount": []map[string]string{
-            {
-                "denom": "microSERVDR",
-                "amount": task.Bounty,
-            },
-        },
-    }
+// LockTaskBounty moves a task's bounty from its creator into escrow and
+// returns the lock transaction hash. Like DistributeTokens it records the
+// locked escrow entry once the transaction succeeded.
+func (c *BlockchainClient) LockTaskBounty(task intTypes.Task, hooks TxHooks) (string, error) {
+    return c.submit(task.Creator, func(account SignerAccount) ([]byte, error) {
+        return c.createTaskTx(task, account)
+    }, hooks.confirmedFirst(func(txHash string) {
+        escrow := intTypes.Escrow{
+            TaskID:     task.ID,
+            Depositor:  task.Creator,
+            Amount:     task.Bounty,
+            Status:     intTypes.ESCROW_LOCKED,
+            LockTxHash: txHash,
+        }
+        if err := c.store.PutEscrow(escrow); err != nil {
+            log.Printf("Warning: failed to record escrow lock for task %s: %v", task.ID, err)
+        }
+        log.Printf("Locked %s %s from %s in escrow for task %s (tx %s)", task.Bounty, Denom, task.Creator, task.ID, txHash)
+    }))
+}
 
-    metadata := map[string]interface{}{
-        "type": "lock_bounty",
-        "task_id": task.ID,
+// GetTaskEscrow returns the escrow entry of a task.
+func (c *BlockchainClient) GetTaskEscrow(taskID string) (intTypes.Escrow, error) {
+    escrow, err := c.store.GetEscrow(taskID)
+    if errors.Is(err, store.ErrNotFound) {
+        return intTypes.Escrow{}, fmt.Errorf("%w for task %s", ErrEscrowNotFound, taskID)
     }
-
-    // Create and submit transaction...
-    */
+    return escrow, err
 }
 
-func (c *BlockchainClient) GetTokenBalance(address string) (string, error) {
-    // Mock version
-    return "1000000", nil
+// ListEscrows returns every escrow entry.
+func (c *BlockchainClient) ListEscrows() ([]intTypes.Escrow, error) {
+    return c.store.ListEscrows()
+}
 
-    /* Real blockchain version (commented out)
-    url := fmt.Sprintf("%s/cosmos/bank/v1beta1/balances/%s", c.restEndpoint, address)
-    resp, err := http.Get(url)
+// GetEscrowTotal returns the sum of all bounties currently locked in escrow.
+func (c *BlockchainClient) GetEscrowTotal() (string, error) {
+    escrows, err := c.store.ListEscrows()
     if err != nil {
-        return "", fmt.Errorf("failed to get balance: %v", err)
-    }
-    defer resp.Body.Close()
-
-    var response struct {
-        Balances []struct {
-            Denom  string `json:"denom"`
-            Amount string `json:"amount"`
-        } `json:"balances"`
-    }
-
-    if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-        return "", fmt.Errorf("failed to decode response: %v", err)
+        return "", err
     }
-
-    for _, balance := range response.Balances {
-        if balance.Denom == "microSERVDR" {
-            return balance.Amount, nil
+    total := sdk.ZeroInt()
+    for _, escrow := range escrows {
+        if escrow.Status != intTypes.ESCROW_LOCKED {
+            continue
+        }
+        amount, ok := sdk.NewIntFromString(escrow.Amount)
+        if !ok {
+            return "", fmt.Errorf("invalid escrow amount %q for task %s", escrow.Amount, escrow.TaskID)
         }
+        total = total.Add(amount)
     }
+    return total.String(), nil
+}
 
-    return "0", nil
-    */
+func (c *BlockchainClient) GetTokenBalance(address string) (string, error) {
+    balance, err := c.backend.Balance(address, Denom)
+    if err != nil {
+        return "", &BackendError{Err: err}
+    }
+    return balance, nil
 }
 
-// Update ApproveTask to include token distribution
-// func (c *BlockchainClient) ApproveTask(task intTypes.Task, approver string) error {
-//     if !c.IsAdmin(approver) {
-//         return fmt.Errorf("only admins can approve tasks")
-//     }
-    
-//     existingTask, exists := c.tasks[task.ID]
-//     if !exists {
-//         return fmt.Errorf("task not found")
-//     }
-    
-//     if existingTask.Status != "CLAIMED" {
-//         return fmt.Errorf("task must be claimed before approval")
-//     }
-
-//     // Distribute tokens to claimer
-//     if err := c.DistributeTokens(existingTask); err != nil {
-//         return fmt.Errorf("failed to distribute tokens: %v", err)
-//     }
-    
-//     existingTask.Status = "COMPLETED"
-//     c.tasks[task.ID] = existingTask
-    
-//     log.Printf("Task %s approved by admin %s and tokens distributed", task.ID, approver)
-//     return nil
-// }
-
-// // Update CreateTask to include bounty locking
-// func (c *BlockchainClient) CreateTask(task intTypes.Task) error {
-//     if task.ID == "" || task.Title == "" || task.Bounty == "" {
-//         return fmt.Errorf("invalid task parameters")
-//     }
-
-//     // Lock bounty in escrow
-//     if err := c.LockTaskBounty(task); err != nil {
-//         return fmt.Errorf("failed to lock bounty: %v", err)
-//     }
-    
-//     // Store task in memory
-//     c.tasks[task.ID] = task
-//     log.Printf("Created task: %+v and locked bounty", task)
-//     return nil
-// }
\ No newline at end of file
+func (c *BlockchainClient) GetBalance(address string) (string, error) {
+    return c.GetTokenBalance(address)
+}
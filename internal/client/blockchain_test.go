@@ -1,12 +1,28 @@
 package client
 
 import (
+    "errors"
     "testing"
+    "time"
     "log"
+    "bounty-system/internal/auth"
+    intTypes "bounty-system/internal/types"
+    "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
 )
 
+// newTestClient builds a client whose bootstrap admin is the admin-1 test
+// wallet. The server never derives that key itself; tests hand it over so
+// the admin can sign.
+func newTestClient(opts ...Option) *BlockchainClient {
+    client := NewBlockchainClient(append([]Option{WithAdmin(TestWalletAddress("admin-1"))}, opts...)...)
+    client.addWallet(testKey("admin-1"))
+    return client
+}
+
 func TestBlockchainClient(t *testing.T) {
-    client := NewBlockchainClient()
+    client := newTestClient()
     
     // Test wallet validation
     testWallets := client.GetTestWallets()
@@ -19,3 +35,571 @@ func TestBlockchainClient(t *testing.T) {
         }
     }
 }
+
+func TestSignedTransactionsVerify(t *testing.T) {
+    client := newTestClient()
+    wallets := client.GetTestWallets()
+    creator, claimer := wallets[1], client.GenerateTestAddress("claimer-1")
+
+    task := intTypes.Task{ID: "task-1", Title: "Test Task", Creator: creator, Bounty: "1000000", Status: "OPEN", Claimer: claimer}
+    account := SignerAccount{AccountNumber: 7, Sequence: 3}
+
+    createTx, err := client.createTaskTx(task, account)
+    if err != nil {
+        t.Fatalf("failed to build create tx: %v", err)
+    }
+    claimTx, err := client.claimTaskTx(task.ID, claimer, "https://github.com/proof", account)
+    if err != nil {
+        t.Fatalf("failed to build claim tx: %v", err)
+    }
+    approveTx, err := client.approveTaskTx(task, wallets[0], account)
+    if err != nil {
+        t.Fatalf("failed to build approve tx: %v", err)
+    }
+
+    for signer, txBytes := range map[string][]byte{creator: createTx, claimer: claimTx, client.GetEscrowAddress(): approveTx} {
+        verifyDirectSignature(t, client, txBytes, signer, account)
+    }
+}
+
+// verifyDirectSignature decodes txBytes and checks its single SIGN_MODE_DIRECT
+// signature against the pubkey in AuthInfo, which must belong to signer.
+func verifyDirectSignature(t *testing.T, client *BlockchainClient, txBytes []byte, signer string, account SignerAccount) {
+    t.Helper()
+    txConfig := client.encodingConfig.TxConfig
+
+    decoded, err := txConfig.TxDecoder()(txBytes)
+    if err != nil {
+        t.Fatalf("failed to decode tx: %v", err)
+    }
+    sigTx, ok := decoded.(authsigning.Tx)
+    if !ok {
+        t.Fatalf("decoded tx does not carry signatures")
+    }
+
+    sigs, err := sigTx.GetSignaturesV2()
+    if err != nil || len(sigs) != 1 {
+        t.Fatalf("expected exactly one signature, got %d (%v)", len(sigs), err)
+    }
+    sig := sigs[0]
+    if sig.Sequence != account.Sequence {
+        t.Fatalf("expected sequence %d, got %d", account.Sequence, sig.Sequence)
+    }
+    if addr := sdk.AccAddress(sig.PubKey.Address()).String(); addr != signer {
+        t.Fatalf("tx signed by %s, expected %s", addr, signer)
+    }
+
+    signerData := authsigning.SignerData{
+        ChainID:       client.GetChainID(),
+        AccountNumber: account.AccountNumber,
+        Sequence:      account.Sequence,
+    }
+    if err := authsigning.VerifySignature(sig.PubKey, signerData, sig.Data, txConfig.SignModeHandler(), sigTx); err != nil {
+        t.Fatalf("signature does not verify: %v", err)
+    }
+
+    // The same signature must not verify for a different account number
+    signerData.AccountNumber++
+    if err := authsigning.VerifySignature(sig.PubKey, signerData, sig.Data, txConfig.SignModeHandler(), sigTx); err == nil {
+        t.Fatalf("signature verified against the wrong account number")
+    }
+}
+
+func TestNewBackendSelection(t *testing.T) {
+    for backend, want := range map[string]string{BackendMock: "mock-chain", BackendSim: "sim-chain", BackendREST: "sim-chain"} {
+        b, err := NewBackend(Config{Backend: backend, ChainID: "sim-chain", RESTEndpoint: "http://localhost:1317"})
+        if err != nil {
+            t.Fatalf("failed to create %s backend: %v", backend, err)
+        }
+        if b.ChainID() != want {
+            t.Fatalf("%s backend: expected chain ID %s, got %s", backend, want, b.ChainID())
+        }
+    }
+
+    if _, err := NewBackend(Config{Backend: "grpc"}); err == nil {
+        t.Fatalf("expected error for unknown backend")
+    }
+}
+
+func TestSimBackendTaskFlow(t *testing.T) {
+    backend := NewSimBackend("sim-chain")
+    client := newTestClient(WithBackend(backend), fixedFees())
+    wallets := client.GetTestWallets()
+    admin, creator := wallets[0], wallets[1]
+    claimer := client.GenerateTestAddress("claimer-1")
+
+    task := intTypes.Task{ID: "task-1", Title: "Test Task", Creator: creator, Bounty: "1000000", Status: "OPEN"}
+    if err := client.CreateTask(task); err != nil {
+        t.Fatalf("create failed: %v", err)
+    }
+    if err := client.ClaimTask(task.ID, claimer, "https://github.com/proof"); err != nil {
+        t.Fatalf("claim failed: %v", err)
+    }
+    if err := client.ApproveTask(task, admin); err != nil {
+        t.Fatalf("approve failed: %v", err)
+    }
+
+    // Claimer received the bounty minus the claim fee (the claim self-send nets to zero)
+    balance, _ := client.GetTokenBalance(claimer)
+    if balance != "100999000" {
+        t.Fatalf("unexpected claimer balance %s", balance)
+    }
+    if backend.Ledger().Height() != 3 {
+        t.Fatalf("expected 3 committed txs, got height %d", backend.Ledger().Height())
+    }
+
+    // Bounties larger than the creator's balance are rejected by the chain
+    task = intTypes.Task{ID: "task-2", Title: "Too Big", Creator: creator, Bounty: "1000000000", Status: "OPEN"}
+    if err := client.CreateTask(task); err == nil {
+        t.Fatalf("expected insufficient funds error")
+    }
+}
+
+func TestCancelTaskRefundsCreator(t *testing.T) {
+    client := newTestClient(WithBackend(NewSimBackend("sim-chain")), fixedFees())
+    wallets := client.GetTestWallets()
+    admin, creator := wallets[0], wallets[1]
+    other := client.GenerateTestAddress("other-1")
+
+    for _, id := range []string{"task-1", "task-2", "task-3"} {
+        task := intTypes.Task{ID: id, Title: "Test Task", Creator: creator, Bounty: "1000000", Status: "OPEN"}
+        if err := client.CreateTask(task); err != nil {
+            t.Fatalf("create failed: %v", err)
+        }
+    }
+
+    if err := client.CancelTask("task-1", other); err == nil {
+        t.Fatalf("expected error when a stranger cancels a task")
+    }
+    if err := client.CancelTask("task-1", creator); err != nil {
+        t.Fatalf("creator cancel failed: %v", err)
+    }
+    if err := client.CancelTask("task-2", admin); err != nil {
+        t.Fatalf("admin cancel failed: %v", err)
+    }
+    if err := client.CancelTask("task-1", creator); err == nil {
+        t.Fatalf("expected error when cancelling twice")
+    }
+
+    if err := client.ClaimTask("task-3", other, "https://github.com/proof"); err != nil {
+        t.Fatalf("claim failed: %v", err)
+    }
+    var invalid *intTypes.InvalidTransitionError
+    if err := client.CancelTask("task-3", creator); !errors.As(err, &invalid) {
+        t.Fatalf("expected InvalidTransitionError when cancelling a claimed task, got %v", err)
+    }
+
+    task, _ := client.store.Get("task-1")
+    escrow, _ := client.GetTaskEscrow("task-1")
+    if task.Status != "CANCELLED" || escrow.Status != intTypes.ESCROW_REFUNDED || escrow.Recipient != creator {
+        t.Fatalf("unexpected state after cancel: %+v %+v", task, escrow)
+    }
+    if len(task.Transitions) != 2 || task.Transitions[1].From != intTypes.TaskStatusOpen || task.Transitions[1].Actor != creator {
+        t.Fatalf("unexpected transition log: %+v", task.Transitions)
+    }
+
+    // Two bounties refunded, one still locked; only the three create fees are lost
+    balance, _ := client.GetTokenBalance(creator)
+    if balance != "98997000" {
+        t.Fatalf("unexpected creator balance %s", balance)
+    }
+    if total, _ := client.GetEscrowTotal(); total != "1000000" {
+        t.Fatalf("expected 1000000 still in escrow, got %s", total)
+    }
+}
+
+func TestRejectClaimPolicies(t *testing.T) {
+    for _, policy := range []RejectPolicy{RejectReopen, RejectClose} {
+        client := newTestClient(WithBackend(NewSimBackend("sim-chain")), WithRejectPolicy(policy))
+        wallets := client.GetTestWallets()
+        admin, creator := wallets[0], wallets[1]
+        claimer := client.GenerateTestAddress("claimer-1")
+
+        task := intTypes.Task{ID: "task-1", Title: "Test Task", Creator: creator, Bounty: "1000000", Status: "OPEN"}
+        if err := client.CreateTask(task); err != nil {
+            t.Fatalf("create failed: %v", err)
+        }
+        if err := client.ClaimTask(task.ID, claimer, "bad proof"); err != nil {
+            t.Fatalf("claim failed: %v", err)
+        }
+
+        if err := client.RejectClaim(task.ID, admin, " "); err == nil {
+            t.Fatalf("expected error for missing reason")
+        }
+        if err := client.RejectClaim(task.ID, claimer, "not good enough"); err == nil {
+            t.Fatalf("expected error for non-admin rejection")
+        }
+        if err := client.RejectClaim(task.ID, admin, "proof link is broken"); err != nil {
+            t.Fatalf("%s: reject failed: %v", policy, err)
+        }
+
+        rejected, _ := client.store.Get(task.ID)
+        if rejected.Claimer != "" || rejected.Proof != "" || len(rejected.RejectedClaims) != 1 {
+            t.Fatalf("%s: claim not moved to history: %+v", policy, rejected)
+        }
+        history := rejected.RejectedClaims[0]
+        if history.Claimer != claimer || history.Proof != "bad proof" || history.Reason != "proof link is broken" || history.RejectedBy != admin {
+            t.Fatalf("%s: unexpected rejected claim %+v", policy, history)
+        }
+
+        escrow, _ := client.GetTaskEscrow(task.ID)
+        switch policy {
+        case RejectReopen:
+            if rejected.Status != "OPEN" || escrow.Status != intTypes.ESCROW_LOCKED {
+                t.Fatalf("reopen: unexpected state %s / %s", rejected.Status, escrow.Status)
+            }
+            if err := client.ClaimTask(task.ID, claimer, "good proof"); err != nil {
+                t.Fatalf("reopen: task cannot be claimed again: %v", err)
+            }
+        case RejectClose:
+            if rejected.Status != "REJECTED" || escrow.Status != intTypes.ESCROW_REFUNDED {
+                t.Fatalf("close: unexpected state %s / %s", rejected.Status, escrow.Status)
+            }
+        }
+    }
+}
+
+func TestDeadlinesAndProofSubmission(t *testing.T) {
+    client := newTestClient(WithBackend(NewSimBackend("sim-chain")))
+    wallets := client.GetTestWallets()
+    admin, creator := wallets[0], wallets[1]
+    claimer := client.GenerateTestAddress("claimer-1")
+
+    past := time.Now().Add(-time.Minute)
+    if err := client.CreateTask(intTypes.Task{ID: "late", Title: "Late", Creator: creator, Bounty: "1000", ClaimDeadline: &past}); err == nil {
+        t.Fatalf("expected error for a claim deadline in the past")
+    }
+
+    task := intTypes.Task{ID: "task-1", Title: "Test Task", Creator: creator, Bounty: "1000000"}
+    if err := client.CreateTask(task); err != nil {
+        t.Fatalf("create failed: %v", err)
+    }
+    var invalid *intTypes.ValidationError
+    if err := client.ClaimTask("task-1", creator, "https://github.com/proof"); !errors.As(err, &invalid) {
+        t.Fatalf("expected a ValidationError when the creator claims their own task, got %v", err)
+    }
+    if err := client.ClaimTask("task-1", claimer, ""); err != nil {
+        t.Fatalf("claim without proof failed: %v", err)
+    }
+    if err := client.ApproveTask(task, admin); err == nil {
+        t.Fatalf("expected error approving a claim without proof")
+    }
+    if err := client.SubmitProof("task-1", admin, "https://github.com/proof"); err == nil {
+        t.Fatalf("expected error when someone else submits the proof")
+    }
+    if err := client.SubmitProof("task-1", claimer, "https://github.com/proof"); err != nil {
+        t.Fatalf("submit proof failed: %v", err)
+    }
+    if err := client.SubmitProof("task-1", claimer, "https://github.com/other"); err == nil {
+        t.Fatalf("expected error submitting proof twice")
+    }
+    if err := client.ApproveTask(task, admin); err != nil {
+        t.Fatalf("approve failed: %v", err)
+    }
+
+    // Deadlines are only enforced by ExpireTask and ReleaseClaim once they pass
+    if err := client.ExpireTask("task-1", time.Now().Add(time.Hour)); err == nil {
+        t.Fatalf("expected error expiring a task without a claim deadline")
+    }
+}
+
+func TestSignChallengeVerifies(t *testing.T) {
+    client := newTestClient()
+    authenticator := auth.NewAuthenticator(time.Minute)
+    user := client.GetTestWallets()[1]
+
+    challenge, err := authenticator.Challenge(user)
+    if err != nil {
+        t.Fatalf("challenge failed: %v", err)
+    }
+    signed, err := client.SignChallenge(challenge)
+    if err != nil {
+        t.Fatalf("sign failed: %v", err)
+    }
+    if address, err := authenticator.Verify(signed); err != nil || address != user {
+        t.Fatalf("verify failed: %q %v", address, err)
+    }
+
+    // Wallets the client holds no key for cannot be signed for
+    challenge, _ = authenticator.Challenge(sdk.AccAddress(make([]byte, 20)).String())
+    if _, err := client.SignChallenge(challenge); err == nil {
+        t.Fatalf("expected error signing for an unknown wallet")
+    }
+}
+
+func TestPrivilegedWalletKeysAreNotHeld(t *testing.T) {
+    admin := TestWalletAddress("admin-1")
+    client := NewBlockchainClient(WithAdmin(admin))
+    if !client.IsAdmin(admin) || client.GetTestWallets()[0] != admin {
+        t.Fatalf("expected %s to be the bootstrap admin", admin)
+    }
+
+    // Neither the bootstrap admin's seed nor a promoted wallet's yields a key
+    reviewer := TestWalletAddress("reviewer-1")
+    if err := client.AssignRole(reviewer, intTypes.ROLE_REVIEWER, admin); err != nil {
+        t.Fatalf("assign failed: %v", err)
+    }
+    authenticator := auth.NewAuthenticator(time.Minute)
+    for _, seed := range []string{"admin-1", "reviewer-1"} {
+        address := client.GenerateTestAddress(seed)
+        if client.ValidateAddress(address) {
+            t.Fatalf("expected no key to be held for privileged wallet %s", address)
+        }
+        challenge, _ := authenticator.Challenge(address)
+        if _, err := client.SignChallenge(challenge); err == nil {
+            t.Fatalf("expected error signing for privileged wallet %s", address)
+        }
+    }
+
+    // The escrow key is random unless configured
+    other := NewBlockchainClient(WithAdmin(admin))
+    if client.GetEscrowAddress() == other.GetEscrowAddress() {
+        t.Fatalf("expected a random escrow key per client")
+    }
+    key := secp256k1.GenPrivKey()
+    configured := NewBlockchainClient(WithAdmin(admin), WithEscrowKey(key))
+    if configured.GetEscrowAddress() != sdk.AccAddress(key.PubKey().Address()).String() {
+        t.Fatalf("expected the configured escrow key to be used")
+    }
+    if _, err := ParsePrivKey("abcd"); err == nil {
+        t.Fatalf("expected error parsing a short key")
+    }
+}
+
+func TestRolePermissions(t *testing.T) {
+    client := newTestClient()
+    admin, user := client.GetTestWallets()[0], client.GetTestWallets()[1]
+    reviewer := client.GenerateTestAddress("reviewer-1")
+    creator := client.GenerateTestAddress("creator-1")
+    auditor := client.GenerateTestAddress("auditor-1")
+
+    for address, role := range map[string]string{reviewer: intTypes.ROLE_REVIEWER, creator: intTypes.ROLE_CREATOR, auditor: intTypes.ROLE_AUDITOR} {
+        if err := client.AssignRole(address, role, user); err == nil {
+            t.Fatalf("expected error when a user assigns roles")
+        }
+        if err := client.AssignRole(address, role, admin); err != nil {
+            t.Fatalf("assign %s failed: %v", role, err)
+        }
+    }
+    if err := client.AssignRole(user, "OWNER", admin); err == nil {
+        t.Fatalf("expected error for an unknown role")
+    }
+
+    task := intTypes.Task{ID: "task-1", Title: "Test Task", Creator: creator, Bounty: "1000"}
+    if err := client.CreateTask(task); err != nil {
+        t.Fatalf("creator create failed: %v", err)
+    }
+    var forbidden *intTypes.ForbiddenError
+    if err := client.CreateTask(intTypes.Task{ID: "task-2", Title: "Test Task", Creator: auditor, Bounty: "1000"}); !errors.As(err, &forbidden) {
+        t.Fatalf("expected ForbiddenError for an auditor creating a task, got %v", err)
+    }
+    if err := client.ClaimTask("task-1", creator, "https://github.com/proof"); !errors.As(err, &forbidden) {
+        t.Fatalf("expected ForbiddenError for a creator claiming, got %v", err)
+    }
+    if err := client.ClaimTask("task-1", user, "https://github.com/proof"); err != nil {
+        t.Fatalf("claim failed: %v", err)
+    }
+    if err := client.ApproveTask(task, auditor); !errors.As(err, &forbidden) {
+        t.Fatalf("expected ForbiddenError for an auditor approving, got %v", err)
+    }
+    if err := client.ApproveTask(task, reviewer); err != nil {
+        t.Fatalf("reviewer approve failed: %v", err)
+    }
+
+    if _, err := client.ListUsers(reviewer); err == nil {
+        t.Fatalf("expected error when a reviewer lists roles")
+    }
+    users, err := client.ListUsers(auditor)
+    if err != nil || len(users) != 4 {
+        t.Fatalf("auditor list failed: %v %v", users, err)
+    }
+
+    // The last admin stays an admin
+    if err := client.AssignRole(admin, intTypes.ROLE_USER, admin); err == nil {
+        t.Fatalf("expected error demoting the last admin")
+    }
+    if client.GetRole(user) != intTypes.ROLE_USER || !client.IsAdmin(admin) {
+        t.Fatalf("unexpected roles: %s %s", client.GetRole(user), client.GetRole(admin))
+    }
+}
+
+func TestApprovalQuorum(t *testing.T) {
+    client := newTestClient(
+        WithBackend(NewSimBackend("sim-chain")),
+        WithApprovalPolicy(ApprovalPolicy{Threshold: "1000000", Required: 2}),
+    )
+    admin, creator := client.GetTestWallets()[0], client.GetTestWallets()[1]
+    reviewer := client.GenerateTestAddress("reviewer-1")
+    claimer := client.GenerateTestAddress("claimer-1")
+    if err := client.AssignRole(reviewer, intTypes.ROLE_REVIEWER, admin); err != nil {
+        t.Fatalf("assign failed: %v", err)
+    }
+
+    small := intTypes.Task{ID: "small", Title: "Small", Creator: creator, Bounty: "1000000"}
+    large := intTypes.Task{ID: "large", Title: "Large", Creator: creator, Bounty: "5000000"}
+    for _, task := range []intTypes.Task{small, large} {
+        if err := client.CreateTask(task); err != nil {
+            t.Fatalf("create failed: %v", err)
+        }
+        if err := client.ClaimTask(task.ID, claimer, "https://github.com/proof"); err != nil {
+            t.Fatalf("claim failed: %v", err)
+        }
+    }
+
+    // At the threshold a single approval still pays out
+    if err := client.ApproveTask(small, admin); err != nil {
+        t.Fatalf("approve failed: %v", err)
+    }
+    if task, _ := client.store.Get("small"); task.Status != intTypes.TaskStatusCompleted || task.RequiredApprovals != 1 {
+        t.Fatalf("unexpected small task: %+v", task)
+    }
+
+    if err := client.ApproveTask(large, admin); err != nil {
+        t.Fatalf("first approval failed: %v", err)
+    }
+    if err := client.ApproveTask(large, admin); err == nil {
+        t.Fatalf("expected error when the same admin approves twice")
+    }
+    task, _ := client.store.Get("large")
+    escrow, _ := client.GetTaskEscrow("large")
+    if task.Status != intTypes.TaskStatusClaimed || task.PendingApprovals() != 1 || escrow.Status != intTypes.ESCROW_LOCKED {
+        t.Fatalf("expected a pending approval, got %+v %+v", task, escrow)
+    }
+    if task.Approvals[0].Approver != admin || task.Approvals[0].ApprovedAt.IsZero() {
+        t.Fatalf("unexpected approval record: %+v", task.Approvals[0])
+    }
+
+    if err := client.ApproveTask(large, reviewer); err != nil {
+        t.Fatalf("second approval failed: %v", err)
+    }
+    task, _ = client.store.Get("large")
+    escrow, _ = client.GetTaskEscrow("large")
+    if task.Status != intTypes.TaskStatusCompleted || len(task.Approvals) != 2 || escrow.Status != intTypes.ESCROW_RELEASED {
+        t.Fatalf("expected payout after quorum, got %+v %+v", task, escrow)
+    }
+    if last := task.Transitions[len(task.Transitions)-1]; last.Actor != reviewer {
+        t.Fatalf("completion should be attributed to the final approver: %+v", last)
+    }
+}
+
+func TestClaimerCannotApproveOwnClaim(t *testing.T) {
+    client := newTestClient(WithApprovalPolicy(ApprovalPolicy{Threshold: "1000000", Required: 2}))
+    admin, creator := client.GetTestWallets()[0], client.GetTestWallets()[1]
+    reviewer := client.GenerateTestAddress("reviewer-1")
+    other := client.GenerateTestAddress("reviewer-2")
+    for _, address := range []string{reviewer, other} {
+        if err := client.AssignRole(address, intTypes.ROLE_REVIEWER, admin); err != nil {
+            t.Fatalf("assign failed: %v", err)
+        }
+    }
+
+    // Admins may claim tasks, but not sign off on their own work
+    task := intTypes.Task{ID: "task-1", Title: "Large", Creator: creator, Bounty: "5000000"}
+    if err := client.CreateTask(task); err != nil {
+        t.Fatalf("create failed: %v", err)
+    }
+    if err := client.ClaimTask(task.ID, admin, "https://github.com/proof"); err != nil {
+        t.Fatalf("claim failed: %v", err)
+    }
+    if err := client.ApproveTask(task, admin); err == nil {
+        t.Fatalf("expected error when the claimer approves their own claim")
+    }
+    if err := client.ApproveTask(task, reviewer); err != nil {
+        t.Fatalf("approve failed: %v", err)
+    }
+    stored, _ := client.store.Get(task.ID)
+    if stored.Status != intTypes.TaskStatusClaimed || len(stored.Approvals) != 1 || stored.PendingApprovals() != 1 {
+        t.Fatalf("expected one more approval to be needed, got %+v", stored)
+    }
+    if err := client.ApproveTask(task, other); err != nil {
+        t.Fatalf("approve failed: %v", err)
+    }
+    if stored, _ = client.store.Get(task.ID); stored.Status != intTypes.TaskStatusCompleted {
+        t.Fatalf("expected payout after two other approvers, got %s", stored.Status)
+    }
+
+    // An approval recorded by the claimer does not count towards the quorum
+    stored.Approvals = append(stored.Approvals[:1], intTypes.Approval{Approver: admin})
+    if pending := stored.PendingApprovals(); pending != 1 {
+        t.Fatalf("expected the claimer's approval to be ignored, got %d pending", pending)
+    }
+}
+
+func TestTaskHistory(t *testing.T) {
+    client := newTestClient(WithBackend(NewSimBackend("sim-chain")))
+    wallets := client.GetTestWallets()
+    admin, creator, claimer := wallets[0], wallets[1], client.GenerateTestAddress("claimer-1")
+
+    if _, err := client.GetTask("task-1"); !errors.Is(err, ErrTaskNotFound) {
+        t.Fatalf("expected ErrTaskNotFound, got %v", err)
+    }
+
+    paid := intTypes.Task{ID: "task-1", Title: "Paid", Creator: creator, Bounty: "1000"}
+    if err := client.CreateTask(paid); err != nil {
+        t.Fatalf("create failed: %v", err)
+    }
+    if err := client.ClaimTask(paid.ID, claimer, ""); err != nil {
+        t.Fatalf("claim failed: %v", err)
+    }
+    if err := client.SubmitProof(paid.ID, claimer, "https://github.com/proof"); err != nil {
+        t.Fatalf("submit proof failed: %v", err)
+    }
+    if err := client.ApproveTask(paid, admin); err != nil {
+        t.Fatalf("approve failed: %v", err)
+    }
+
+    refunded := intTypes.Task{ID: "task-2", Title: "Refunded", Creator: creator, Bounty: "1000"}
+    if err := client.CreateTask(refunded); err != nil {
+        t.Fatalf("create failed: %v", err)
+    }
+    if err := client.ClaimTask(refunded.ID, claimer, "https://github.com/wrong"); err != nil {
+        t.Fatalf("claim failed: %v", err)
+    }
+    if err := client.RejectClaim(refunded.ID, admin, "wrong repository"); err != nil {
+        t.Fatalf("reject failed: %v", err)
+    }
+    if err := client.CancelTask(refunded.ID, creator); err != nil {
+        t.Fatalf("cancel failed: %v", err)
+    }
+
+    escrow, _ := client.GetTaskEscrow(paid.ID)
+    type entry struct {
+        kind  intTypes.TaskEventType
+        actor string
+        tx    bool
+    }
+    for taskID, want := range map[string][]entry{
+        paid.ID: {
+            {intTypes.TaskEventCreated, creator, true},
+            {intTypes.TaskEventClaimed, claimer, true},
+            {intTypes.TaskEventProofSubmitted, claimer, true},
+            {intTypes.TaskEventApproved, admin, false},
+            {intTypes.TaskEventPaid, admin, true},
+        },
+        refunded.ID: {
+            {intTypes.TaskEventCreated, creator, true},
+            {intTypes.TaskEventClaimed, claimer, true},
+            {intTypes.TaskEventRejected, admin, false},
+            {intTypes.TaskEventCancelled, creator, false},
+            {intTypes.TaskEventRefunded, creator, true},
+        },
+    } {
+        task, err := client.GetTask(taskID)
+        if err != nil {
+            t.Fatalf("get %s failed: %v", taskID, err)
+        }
+        if len(task.History) != len(want) {
+            t.Fatalf("%s: expected %d events, got %+v", taskID, len(want), task.History)
+        }
+        for i, event := range task.History {
+            if event.Type != want[i].kind || event.Actor != want[i].actor || (event.TxHash != "") != want[i].tx || event.Time.IsZero() {
+                t.Errorf("%s: event %d is %+v, expected %+v", taskID, i, event, want[i])
+            }
+        }
+        if taskID == paid.ID {
+            if task.History[0].TxHash != escrow.LockTxHash || task.History[4].TxHash != escrow.ReleaseTxHash {
+                t.Errorf("history tx hashes do not match the escrow entry %+v", escrow)
+            }
+        }
+    }
+}
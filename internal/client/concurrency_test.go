@@ -0,0 +1,173 @@
+package client
+
+import (
+    "errors"
+    "fmt"
+    "sync"
+    "testing"
+    intTypes "bounty-system/internal/types"
+)
+
+// These tests are meant to be run with -race. Each starts its goroutines
+// together on a closed channel so the operations really overlap.
+
+// race runs fn(i) for i in [0, n) concurrently and returns the errors.
+func race(n int, fn func(i int) error) []error {
+    errs := make([]error, n)
+    start := make(chan struct{})
+    var wg sync.WaitGroup
+    for i := 0; i < n; i++ {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            <-start
+            errs[i] = fn(i)
+        }(i)
+    }
+    close(start)
+    wg.Wait()
+    return errs
+}
+
+func succeeded(errs []error) int {
+    n := 0
+    for _, err := range errs {
+        if err == nil {
+            n++
+        }
+    }
+    return n
+}
+
+func TestConcurrentClaimsHaveOneWinner(t *testing.T) {
+    client := newTestClient(WithBackend(NewSimBackend("sim-chain")))
+    creator := client.GetTestWallets()[1]
+
+    const claimers = 16
+    addresses := make([]string, claimers)
+    race(claimers, func(i int) error {
+        addresses[i] = client.GenerateTestAddress(fmt.Sprintf("claimer-%d", i))
+        return nil
+    })
+
+    task := intTypes.Task{ID: "task-1", Title: "Contested", Creator: creator, Bounty: "1000"}
+    if err := client.CreateTask(task); err != nil {
+        t.Fatalf("create failed: %v", err)
+    }
+
+    errs := race(claimers, func(i int) error {
+        return client.ClaimTask(task.ID, addresses[i], "https://github.com/proof")
+    })
+    if n := succeeded(errs); n != 1 {
+        t.Fatalf("expected exactly one winning claim, got %d: %v", n, errs)
+    }
+
+    var winner string
+    for i, err := range errs {
+        var transition *intTypes.InvalidTransitionError
+        switch {
+        case err == nil:
+            winner = addresses[i]
+        case !errors.As(err, &transition):
+            t.Errorf("losing claim failed with %v, expected an InvalidTransitionError", err)
+        }
+    }
+
+    claimed, _ := client.GetTask(task.ID)
+    if claimed.Claimer != winner || claimed.Status != intTypes.TaskStatusClaimed {
+        t.Fatalf("task claimed by %s (%s), winner was %s", claimed.Claimer, claimed.Status, winner)
+    }
+    claims := 0
+    for _, event := range claimed.History {
+        if event.Type == intTypes.TaskEventClaimed {
+            claims++
+            if event.Actor != winner || event.TxHash == "" {
+                t.Errorf("unexpected claim event %+v", event)
+            }
+        }
+    }
+    if claims != 1 {
+        t.Errorf("expected one claim event, got %d", claims)
+    }
+}
+
+func TestConcurrentCreatesKeepOneTask(t *testing.T) {
+    client := newTestClient(WithBackend(NewSimBackend("sim-chain")))
+    creator := client.GetTestWallets()[1]
+
+    errs := race(8, func(i int) error {
+        return client.CreateTask(intTypes.Task{ID: "task-1", Title: fmt.Sprintf("Attempt %d", i), Creator: creator, Bounty: "1000"})
+    })
+    if n := succeeded(errs); n != 1 {
+        t.Fatalf("expected exactly one create to succeed, got %d: %v", n, errs)
+    }
+    if escrows, _ := client.ListEscrows(); len(escrows) != 1 {
+        t.Fatalf("expected one escrow entry, got %+v", escrows)
+    }
+}
+
+func TestConcurrentApprovalsPayOnce(t *testing.T) {
+    client := newTestClient(
+        WithBackend(NewSimBackend("sim-chain")),
+        WithApprovalPolicy(ApprovalPolicy{Threshold: "1", Required: 2}),
+    )
+    admin, creator := client.GetTestWallets()[0], client.GetTestWallets()[1]
+    claimer := client.GenerateTestAddress("claimer-1")
+
+    const reviewers = 8
+    addresses := make([]string, reviewers)
+    for i := range addresses {
+        addresses[i] = client.GenerateTestAddress(fmt.Sprintf("reviewer-%d", i))
+        if err := client.AssignRole(addresses[i], intTypes.ROLE_REVIEWER, admin); err != nil {
+            t.Fatalf("assign failed: %v", err)
+        }
+    }
+
+    task := intTypes.Task{ID: "task-1", Title: "Reviewed", Creator: creator, Bounty: "1000"}
+    if err := client.CreateTask(task); err != nil {
+        t.Fatalf("create failed: %v", err)
+    }
+    if err := client.ClaimTask(task.ID, claimer, "https://github.com/proof"); err != nil {
+        t.Fatalf("claim failed: %v", err)
+    }
+
+    errs := race(reviewers, func(i int) error {
+        return client.ApproveTask(task, addresses[i])
+    })
+    if n := succeeded(errs); n != 2 {
+        t.Fatalf("expected exactly the quorum of 2 approvals to count, got %d: %v", n, errs)
+    }
+
+    paid, _ := client.GetTask(task.ID)
+    payouts := 0
+    for _, event := range paid.History {
+        if event.Type == intTypes.TaskEventPaid {
+            payouts++
+        }
+    }
+    if paid.Status != intTypes.TaskStatusCompleted || payouts != 1 {
+        t.Fatalf("expected one payout of a completed task, got %d (%s)", payouts, paid.Status)
+    }
+}
+
+func TestConcurrentWalletAccess(t *testing.T) {
+    client := newTestClient()
+    errs := race(16, func(i int) error {
+        address := client.GenerateTestAddress(fmt.Sprintf("wallet-%d", i%4))
+        if !client.ValidateAddress(address) {
+            return fmt.Errorf("address %s not valid right after generating it", address)
+        }
+        client.ListAddresses()
+        client.GetTestWallets()
+        return nil
+    })
+    for _, err := range errs {
+        if err != nil {
+            t.Error(err)
+        }
+    }
+    // Four seeds plus the admin, user and escrow wallets
+    if n := len(client.ListAddresses()); n != 7 {
+        t.Fatalf("expected 7 addresses, got %d", n)
+    }
+}
@@ -0,0 +1,248 @@
+package client
+
+import (
+    "errors"
+    "fmt"
+    "log"
+    "time"
+    "bounty-system/internal/store"
+    intTypes "bounty-system/internal/types"
+)
+
+// ErrTxNotFound is returned by ChainBackend.Tx for transactions that are
+// not in a block (yet).
+var ErrTxNotFound = errors.New("transaction not found")
+
+// TxResult is the DeliverTx outcome of a transaction in a block.
+type TxResult struct {
+    Height int64
+    Code   uint32 // Non-zero when the transaction failed
+    Log    string
+}
+
+// TxError reports a broadcast transaction that did not succeed: it failed
+// in its block, or it was not in a block before the confirmation timeout.
+// A transaction that timed out may still be included later, so its change
+// is kept pending rather than rolled back.
+type TxError struct {
+    Hash     string
+    Code     uint32
+    Log      string
+    TimedOut bool
+}
+
+func (e *TxError) Error() string {
+    if e.TimedOut {
+        return fmt.Sprintf("transaction %s was not included in a block in time, the change stays pending until it is", e.Hash)
+    }
+    return fmt.Sprintf("transaction %s failed in its block with code %d: %s", e.Hash, e.Code, e.Log)
+}
+
+// ConfirmPolicy controls how long the client waits for a broadcast
+// transaction to be included in a block. A request gives up waiting after
+// Timeout; the transaction is then looked up in the background for up to
+// Reconcile before its change is left pending for the indexer.
+type ConfirmPolicy struct {
+    Timeout   time.Duration
+    Interval  time.Duration // Pause between lookups
+    Reconcile time.Duration
+}
+
+// DefaultConfirmPolicy waits a minute, looking the transaction up every
+// second, and keeps looking for an hour after that.
+func DefaultConfirmPolicy() ConfirmPolicy {
+    return ConfirmPolicy{Timeout: time.Minute, Interval: time.Second, Reconcile: time.Hour}
+}
+
+// WithConfirmPolicy sets how the client waits for its transactions.
+func WithConfirmPolicy(policy ConfirmPolicy) Option {
+    return func(c *BlockchainClient) {
+        c.confirmation = policy
+    }
+}
+
+// TxHooks follow a transaction through submit. Any of them may be nil.
+type TxHooks struct {
+    // Pending is called once the node accepted the transaction.
+    Pending func(txHash string)
+    // Confirmed is called once the transaction succeeded in a block.
+    Confirmed func(txHash string)
+    // Failed is called if the transaction could not be sent (txHash is
+    // empty) or failed in its block.
+    Failed func(txHash string, err error)
+}
+
+// confirmedFirst returns h with confirmed run ahead of its Confirmed hook.
+func (h TxHooks) confirmedFirst(confirmed func(txHash string)) TxHooks {
+    next := h.Confirmed
+    h.Confirmed = func(txHash string) {
+        confirmed(txHash)
+        if next != nil {
+            next(txHash)
+        }
+    }
+    return h
+}
+
+// submit broadcasts a transaction, waits until it is in a block and calls
+// the matching hooks. A transaction that was broadcast but failed or timed
+// out is returned with its hash and a *TxError. One that timed out is
+// neither confirmed nor failed yet: it is looked up in the background and
+// its hooks run once its outcome is known.
+func (c *BlockchainClient) submit(signer string, build func(account SignerAccount) ([]byte, error), hooks TxHooks) (string, error) {
+    txHash, err := c.signAndSubmit(signer, build)
+    if err != nil {
+        if hooks.Failed != nil {
+            hooks.Failed("", err)
+        }
+        return "", err
+    }
+    if hooks.Pending != nil {
+        hooks.Pending(txHash)
+    }
+
+    err = c.confirm(txHash, c.confirmation.Timeout)
+    var txErr *TxError
+    if errors.As(err, &txErr) && txErr.TimedOut {
+        go c.reconcile(signer, txHash, hooks)
+        return txHash, err
+    }
+    c.settle(signer, txHash, err, hooks)
+    return txHash, err
+}
+
+// sendFunc sends a transaction: submit or broadcast.
+type sendFunc func(signer string, build func(account SignerAccount) ([]byte, error), hooks TxHooks) (string, error)
+
+// broadcast sends a transaction like submit but does not wait for its
+// block: it returns once the node accepted it, and the hooks run from the
+// background once reconcile knows the outcome.
+func (c *BlockchainClient) broadcast(signer string, build func(account SignerAccount) ([]byte, error), hooks TxHooks) (string, error) {
+    txHash, err := c.signAndSubmit(signer, build)
+    if err != nil {
+        if hooks.Failed != nil {
+            hooks.Failed("", err)
+        }
+        return "", err
+    }
+    if hooks.Pending != nil {
+        hooks.Pending(txHash)
+    }
+    go c.reconcile(signer, txHash, hooks)
+    return txHash, nil
+}
+
+// reconcile keeps looking up a transaction that timed out. If it never
+// shows up, its change stays pending; the indexer confirms it should it be
+// included after all.
+func (c *BlockchainClient) reconcile(signer string, txHash string, hooks TxHooks) {
+    err := c.confirm(txHash, c.confirmation.Reconcile)
+    var txErr *TxError
+    if errors.As(err, &txErr) && txErr.TimedOut {
+        log.Printf("Warning: tx %s is still not in a block, leaving its change pending", txHash)
+        return
+    }
+    log.Printf("Pending tx %s settled: %v", txHash, err)
+    c.settle(signer, txHash, err, hooks)
+}
+
+// settle runs the hooks for a transaction whose outcome is known.
+func (c *BlockchainClient) settle(signer string, txHash string, err error, hooks TxHooks) {
+    if err != nil {
+        // A failed transaction may or may not have used its sequence
+        c.sequences.reset(signer)
+        if hooks.Failed != nil {
+            hooks.Failed(txHash, err)
+        }
+        return
+    }
+    if hooks.Confirmed != nil {
+        hooks.Confirmed(txHash)
+    }
+}
+
+// confirm polls the backend until txHash is in a block. Lookup errors are
+// retried until the timeout, like a transaction that is not found yet.
+func (c *BlockchainClient) confirm(txHash string, timeout time.Duration) error {
+    deadline := time.Now().Add(timeout)
+    for {
+        result, err := c.backend.Tx(txHash)
+        switch {
+        case err == nil && result.Code == 0:
+            return nil
+        case err == nil:
+            return &TxError{Hash: txHash, Code: result.Code, Log: result.Log}
+        case !errors.Is(err, ErrTxNotFound):
+            log.Printf("Warning: failed to look up tx %s: %v", txHash, err)
+        }
+        if !time.Now().Before(deadline) {
+            return &TxError{Hash: txHash, TimedOut: true}
+        }
+        time.Sleep(c.confirmation.Interval)
+    }
+}
+
+// attachTxHash sets the transaction hash of the last event of applied once
+// its transaction was accepted, marking it pending. Events recorded since
+// are left as they are. It returns the task as stored, so the change can
+// still be rolled back.
+func (c *BlockchainClient) attachTxHash(applied intTypes.Task, txHash string) intTypes.Task {
+    last := len(applied.History) - 1
+    updated, err := c.store.Update(applied.ID, func(task *intTypes.Task) error {
+        if last < 0 || last >= len(task.History) || task.History[last].Type != applied.History[last].Type {
+            return store.ErrConflict
+        }
+        task.History[last].TxHash = txHash
+        task.History[last].TxStatus = intTypes.TxPending
+        return nil
+    })
+    if err != nil {
+        log.Printf("Warning: failed to record tx %s for task %s: %v", txHash, applied.ID, err)
+        return applied
+    }
+    return updated
+}
+
+// recordPending appends event for a transaction that was accepted but is
+// not confirmed yet, and returns the task as stored like attachTxHash.
+func (c *BlockchainClient) recordPending(applied intTypes.Task, event intTypes.TaskEvent) intTypes.Task {
+    event.TxStatus = intTypes.TxPending
+    updated, err := c.store.Update(applied.ID, func(task *intTypes.Task) error {
+        task.Record(event)
+        return nil
+    })
+    if err != nil {
+        log.Printf("Warning: failed to record %s event for task %s: %v", event.Type, applied.ID, err)
+        return applied
+    }
+    return updated
+}
+
+// confirmTx marks the events of txHash confirmed once it succeeded.
+func (c *BlockchainClient) confirmTx(taskID string, txHash string) {
+    _, err := c.store.Update(taskID, func(task *intTypes.Task) error {
+        for i := range task.History {
+            if task.History[i].TxHash == txHash {
+                task.History[i].TxStatus = intTypes.TxConfirmed
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        log.Printf("Warning: failed to confirm tx %s for task %s: %v", txHash, taskID, err)
+    }
+}
+
+// revert undoes applied after the transaction behind it failed, putting the
+// task back to previous. If the transaction had been broadcast the failure
+// stays in the history as a TX_FAILED event. It is only called for
+// transactions known to have failed, never for ones that timed out.
+func (c *BlockchainClient) revert(previous intTypes.Task, applied intTypes.Task, actor string, txHash string, cause error) {
+    if err := c.restoreTask(previous, applied); err != nil {
+        log.Printf("Warning: failed to roll back task %s: %v", previous.ID, err)
+        return
+    }
+    if txHash != "" {
+        c.recordEvent(previous.ID, intTypes.TaskEvent{Type: intTypes.TaskEventTxFailed, Actor: actor, Time: time.Now(), TxHash: txHash, TxStatus: intTypes.TxFailed, Detail: cause.Error()})
+    }
+}
@@ -0,0 +1,448 @@
+package client
+
+import (
+    "errors"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "testing"
+    "time"
+    "bounty-system/internal/chainsim"
+    "bounty-system/internal/store"
+    intTypes "bounty-system/internal/types"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// fixedFees turns gas simulation off, so every tx pays DefaultFeeAmount and
+// balances can be checked exactly.
+func fixedFees() Option {
+    return WithFeePolicy(FeePolicy{GasPrice: DefaultGasPrice})
+}
+
+// newFakeNodeClient starts a fake node for chainID and returns a client
+// talking to it over REST with its test wallets and escrow funded.
+func newFakeNodeClient(t *testing.T, chainID string) (*BlockchainClient, *chainsim.FakeNode) {
+    t.Helper()
+    node := chainsim.NewFakeNode(chainsim.NewLedger("fake-chain", MakeEncodingConfig().TxConfig))
+    t.Cleanup(node.Close)
+
+    client := newTestClient(WithBackend(NewRESTBackend(chainID, node.URL, node.URL)), fixedFees())
+    for _, addr := range append(client.GetTestWallets(), client.GetEscrowAddress()) {
+        node.Ledger.Fund(addr, sdk.NewCoins(sdk.NewInt64Coin(Denom, 10000000)))
+    }
+    return client, node
+}
+
+func TestFakeNodeEscrowLockClaimAndPayout(t *testing.T) {
+    client, node := newFakeNodeClient(t, "fake-chain")
+    wallets := client.GetTestWallets()
+    admin, creator := wallets[0], wallets[1]
+    claimer := client.GenerateTestAddress("claimer-1")
+    node.Ledger.Fund(claimer, sdk.NewCoins(sdk.NewInt64Coin(Denom, 5000)))
+
+    task := intTypes.Task{ID: "task-1", Title: "Test Task", Creator: creator, Bounty: "2500000", Status: "OPEN"}
+    if err := client.CreateTask(task); err != nil {
+        t.Fatalf("create failed: %v", err)
+    }
+    assertBalance(t, client, creator, "7499000")
+    assertBalance(t, client, client.GetEscrowAddress(), "12500000")
+
+    if err := client.ClaimTask(task.ID, claimer, "https://github.com/proof"); err != nil {
+        t.Fatalf("claim failed: %v", err)
+    }
+    if err := client.ApproveTask(task, admin); err != nil {
+        t.Fatalf("approve failed: %v", err)
+    }
+    assertBalance(t, client, claimer, "2504000")
+    assertBalance(t, client, client.GetEscrowAddress(), "9999000")
+
+    // Sequences advanced once per broadcast and are served by the auth endpoint
+    for addr, want := range map[string]uint64{creator: 1, claimer: 1, client.GetEscrowAddress(): 1} {
+        account, err := client.backend.AccountInfo(addr)
+        if err != nil || account.Sequence != want {
+            t.Fatalf("expected sequence %d for %s, got %+v (%v)", want, addr, account, err)
+        }
+    }
+    if node.Ledger.Height() != 3 {
+        t.Fatalf("expected 3 blocks, got %d", node.Ledger.Height())
+    }
+}
+
+func TestFakeNodeRejectsWrongChainSignature(t *testing.T) {
+    client, node := newFakeNodeClient(t, "other-chain")
+    creator := client.GetTestWallets()[1]
+
+    task := intTypes.Task{ID: "task-1", Title: "Test Task", Creator: creator, Bounty: "1000", Status: "OPEN"}
+    err := client.CreateTask(task)
+    if err == nil || !strings.Contains(err.Error(), "signature verification failed") {
+        t.Fatalf("expected signature verification failure, got %v", err)
+    }
+    if _, err := client.store.Get(task.ID); err == nil {
+        t.Fatalf("task must not be stored when its transaction is rejected")
+    }
+    assertBalance(t, client, creator, "10000000")
+    if node.Ledger.Height() != 0 {
+        t.Fatalf("expected no blocks, got %d", node.Ledger.Height())
+    }
+}
+
+func TestFakeNodeSequenceResync(t *testing.T) {
+    client, node := newFakeNodeClient(t, "fake-chain")
+    creator := client.GetTestWallets()[1]
+
+    if err := client.CreateTask(intTypes.Task{ID: "task-1", Title: "First", Creator: creator, Bounty: "1000", Status: "OPEN"}); err != nil {
+        t.Fatalf("create failed: %v", err)
+    }
+
+    // A second process signing with the same wallet moves the sequence on
+    other := newTestClient(WithBackend(NewRESTBackend("fake-chain", node.URL, node.URL)))
+    if err := other.CreateTask(intTypes.Task{ID: "task-2", Title: "Second", Creator: creator, Bounty: "1000", Status: "OPEN"}); err != nil {
+        t.Fatalf("create from second client failed: %v", err)
+    }
+
+    // Estimates simulate with the tracked sequence and resync the same way
+    client.fees = DefaultFeePolicy()
+    if _, err := client.EstimateCreateTask(intTypes.Task{ID: "task-3", Title: "Third", Creator: creator, Bounty: "1000"}); err != nil {
+        t.Fatalf("expected the estimate to resync after sequence mismatch, got %v", err)
+    }
+    if node.Ledger.Height() != 2 {
+        t.Fatalf("estimating must not broadcast anything")
+    }
+
+    if err := client.CreateTask(intTypes.Task{ID: "task-3", Title: "Third", Creator: creator, Bounty: "1000", Status: "OPEN"}); err != nil {
+        t.Fatalf("expected resync after sequence mismatch, got %v", err)
+    }
+    if account, _ := node.Ledger.Account(creator); account.Sequence != 3 {
+        t.Fatalf("expected creator sequence 3, got %d", account.Sequence)
+    }
+}
+
+func TestFakeNodeSimulatedFees(t *testing.T) {
+    node := chainsim.NewFakeNode(chainsim.NewLedger("fake-chain", MakeEncodingConfig().TxConfig))
+    defer node.Close()
+    client := newTestClient(WithBackend(NewRESTBackend("fake-chain", node.URL, node.URL)))
+    creator := client.GetTestWallets()[1]
+    node.Ledger.Fund(creator, sdk.NewCoins(sdk.NewInt64Coin(Denom, 10000000)))
+
+    task := intTypes.Task{ID: "task-1", Title: "Test Task", Creator: creator, Bounty: "2500000", Status: "OPEN"}
+    estimate, err := client.EstimateCreateTask(task)
+    if err != nil {
+        t.Fatalf("estimate failed: %v", err)
+    }
+    if estimate.GasUsed == 0 || estimate.GasLimit != client.fees.gasLimit(estimate.GasUsed) || estimate.Amount != "2500000" {
+        t.Fatalf("unexpected estimate %+v", estimate)
+    }
+    if fee, _ := strconv.Atoi(estimate.Fee); fee <= 0 || int64(fee) >= DefaultFeeAmount {
+        t.Fatalf("expected a simulated fee below the fixed %d, got %s", DefaultFeeAmount, estimate.Fee)
+    }
+    if _, err := client.store.Get(task.ID); err == nil || node.Ledger.Height() != 0 {
+        t.Fatalf("estimating must not store or broadcast anything")
+    }
+
+    // The task then costs exactly what was estimated
+    if err := client.CreateTask(task); err != nil {
+        t.Fatalf("create failed: %v", err)
+    }
+    total, _ := strconv.Atoi(estimate.Total)
+    assertBalance(t, client, creator, strconv.Itoa(10000000-total))
+
+    // A bounty the creator cannot pay fails simulation
+    task.ID, task.Bounty = "task-2", "20000000"
+    if _, err := client.EstimateCreateTask(task); err == nil || !strings.Contains(err.Error(), "insufficient funds") {
+        t.Fatalf("expected the simulation to fail on insufficient funds, got %v", err)
+    }
+}
+
+func assertBalance(t *testing.T, client *BlockchainClient, address string, want string) {
+    t.Helper()
+    balance, err := client.GetTokenBalance(address)
+    if err != nil {
+        t.Fatalf("failed to get balance of %s: %v", address, err)
+    }
+    if balance != want {
+        t.Fatalf("expected balance %s for %s, got %s", want, address, balance)
+    }
+}
+
+func TestEscrowLedgerAndApprovalRollback(t *testing.T) {
+    node := chainsim.NewFakeNode(chainsim.NewLedger("fake-chain", MakeEncodingConfig().TxConfig))
+    defer node.Close()
+
+    // The escrow account is left unfunded, so it cannot pay payout fees
+    client := newTestClient(WithBackend(NewRESTBackend("fake-chain", node.URL, node.URL)))
+    wallets := client.GetTestWallets()
+    admin, creator, claimer := wallets[0], wallets[1], client.GenerateTestAddress("claimer-1")
+    node.Ledger.Fund(creator, sdk.NewCoins(sdk.NewInt64Coin(Denom, 10000000)))
+    node.Ledger.Fund(claimer, sdk.NewCoins(sdk.NewInt64Coin(Denom, 10000)))
+
+    createTask := func(id string, bounty string) {
+        task := intTypes.Task{ID: id, Title: "Test Task", Creator: creator, Bounty: bounty, Status: "OPEN"}
+        if err := client.CreateTask(task); err != nil {
+            t.Fatalf("create failed: %v", err)
+        }
+    }
+    createTask("task-0", "1000000")
+
+    if err := client.ClaimTask("task-0", claimer, "https://github.com/proof"); err != nil {
+        t.Fatalf("claim failed: %v", err)
+    }
+    err := client.ApproveTask(intTypes.Task{ID: "task-0"}, admin)
+    if err == nil || !strings.Contains(err.Error(), "insufficient funds") {
+        t.Fatalf("expected payout to fail with insufficient funds, got %v", err)
+    }
+
+    // The failed payout leaves the task claimed and its bounty locked
+    task, _ := client.store.Get("task-0")
+    if task.Status != "CLAIMED" {
+        t.Fatalf("expected task to be rolled back to CLAIMED, got %s", task.Status)
+    }
+    escrow, err := client.GetTaskEscrow("task-0")
+    if err != nil || escrow.Status != intTypes.ESCROW_LOCKED || escrow.LockTxHash == "" {
+        t.Fatalf("unexpected escrow after failed payout: %+v (%v)", escrow, err)
+    }
+
+    // A second locked bounty gives the escrow account enough to pay the fee
+    createTask("task-1", "250000")
+    if total, _ := client.GetEscrowTotal(); total != "1250000" {
+        t.Fatalf("expected 1250000 in escrow, got %s", total)
+    }
+    if err := client.ApproveTask(intTypes.Task{ID: "task-0"}, admin); err != nil {
+        t.Fatalf("approve failed: %v", err)
+    }
+    escrow, _ = client.GetTaskEscrow("task-0")
+    if escrow.Status != intTypes.ESCROW_RELEASED || escrow.Recipient != claimer || escrow.ReleaseTxHash == "" {
+        t.Fatalf("unexpected escrow after payout: %+v", escrow)
+    }
+    if total, _ := client.GetEscrowTotal(); total != "250000" {
+        t.Fatalf("expected 250000 left in escrow, got %s", total)
+    }
+}
+
+// eventually polls cond until it holds or a few seconds passed.
+func eventually(t *testing.T, what string, cond func() bool) {
+    t.Helper()
+    for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+        if cond() {
+            return
+        }
+    }
+    t.Fatalf("timed out waiting for %s", what)
+}
+
+// lastEvent reports whether the newest history entry of task id has type and txStatus.
+func lastEvent(client *BlockchainClient, id string, eventType intTypes.TaskEventType, txStatus intTypes.TxStatus) func() bool {
+    return func() bool {
+        task, err := client.store.Get(id)
+        if err != nil || len(task.History) == 0 {
+            return false
+        }
+        last := task.History[len(task.History)-1]
+        return last.Type == eventType && last.TxStatus == txStatus && last.TxHash != ""
+    }
+}
+
+func TestFakeNodeConfirmsBeforeCommitting(t *testing.T) {
+    node := chainsim.NewFakeNode(chainsim.NewLedger("fake-chain", MakeEncodingConfig().TxConfig))
+    defer node.Close()
+
+    // The escrow account is left unfunded, so its payout fails in the block.
+    // Simulation would reject it before broadcasting, so fees are fixed.
+    client := newTestClient(
+        WithBackend(NewRESTBackend("fake-chain", node.URL, node.URL)),
+        WithConfirmPolicy(ConfirmPolicy{Timeout: 5 * time.Second, Interval: 5 * time.Millisecond}),
+        fixedFees(),
+    )
+    wallets := client.GetTestWallets()
+    admin, creator, claimer := wallets[0], wallets[1], client.GenerateTestAddress("claimer-1")
+    node.Ledger.Fund(creator, sdk.NewCoins(sdk.NewInt64Coin(Denom, 10000000)))
+    node.Ledger.Fund(claimer, sdk.NewCoins(sdk.NewInt64Coin(Denom, 10000)))
+
+    if err := client.CreateTask(intTypes.Task{ID: "task-0", Title: "Test Task", Creator: creator, Bounty: "1000000"}); err != nil {
+        t.Fatalf("create failed: %v", err)
+    }
+    if !lastEvent(client, "task-0", intTypes.TaskEventCreated, intTypes.TxConfirmed)() {
+        t.Fatalf("expected a confirmed CREATED event")
+    }
+
+    // From now on transactions wait in the mempool until the next block
+    node.Hold()
+    done := make(chan error, 1)
+    go func() { done <- client.ClaimTask("task-0", claimer, "https://github.com/proof") }()
+    eventually(t, "pending claim", lastEvent(client, "task-0", intTypes.TaskEventClaimed, intTypes.TxPending))
+    node.Commit()
+    if err := <-done; err != nil {
+        t.Fatalf("claim failed: %v", err)
+    }
+    if !lastEvent(client, "task-0", intTypes.TaskEventClaimed, intTypes.TxConfirmed)() {
+        t.Fatalf("expected the claim to be confirmed")
+    }
+
+    // The task shows COMPLETED only while its payout is pending
+    go func() { done <- client.ApproveTask(intTypes.Task{ID: "task-0"}, admin) }()
+    eventually(t, "pending payout", lastEvent(client, "task-0", intTypes.TaskEventPaid, intTypes.TxPending))
+    if task, _ := client.store.Get("task-0"); task.Status != intTypes.TaskStatusCompleted {
+        t.Fatalf("expected COMPLETED while the payout is pending, got %s", task.Status)
+    }
+    results := node.Commit()
+    if len(results) != 1 || results[0].Code == 0 {
+        t.Fatalf("expected the payout to fail in its block, got %+v", results)
+    }
+    if err := <-done; err == nil || !strings.Contains(err.Error(), "failed in its block") {
+        t.Fatalf("expected the approval to fail with the payout, got %v", err)
+    }
+    task, _ := client.store.Get("task-0")
+    if task.Status != intTypes.TaskStatusClaimed || len(task.Approvals) != 0 {
+        t.Fatalf("expected the approval to be rolled back, got %+v", task)
+    }
+    if last := task.History[len(task.History)-1]; last.Type != intTypes.TaskEventTxFailed || last.TxStatus != intTypes.TxFailed || last.TxHash != results[0].Hash {
+        t.Fatalf("expected the failed payout in the history, got %+v", last)
+    }
+    if escrow, _ := client.GetTaskEscrow("task-0"); escrow.Status != intTypes.ESCROW_LOCKED {
+        t.Fatalf("expected the bounty to stay locked, got %+v", escrow)
+    }
+
+    // A transaction that is not in a block in time stays pending, since it
+    // may still be included
+    client.confirmation = ConfirmPolicy{Timeout: 20 * time.Millisecond, Interval: 5 * time.Millisecond, Reconcile: 5 * time.Second}
+    err := client.CreateTask(intTypes.Task{ID: "task-1", Title: "Test Task", Creator: creator, Bounty: "2000"})
+    var txErr *TxError
+    if !errors.As(err, &txErr) || !txErr.TimedOut {
+        t.Fatalf("expected a confirmation timeout, got %v", err)
+    }
+    if !lastEvent(client, "task-1", intTypes.TaskEventCreated, intTypes.TxPending)() {
+        t.Fatalf("expected task-1 to be kept with a pending lock")
+    }
+    node.Commit()
+    eventually(t, "late lock", lastEvent(client, "task-1", intTypes.TaskEventCreated, intTypes.TxConfirmed))
+    eventually(t, "late escrow", func() bool {
+        escrow, err := client.GetTaskEscrow("task-1")
+        return err == nil && escrow.Status == intTypes.ESCROW_LOCKED
+    })
+
+    // The escrow account now holds enough for the payout and its fee, after
+    // paying the fee of the failed one. While it is
+    // pending the task stays COMPLETED and cannot be paid a second time.
+    err = client.ApproveTask(intTypes.Task{ID: "task-0"}, admin)
+    if err == nil || !strings.Contains(err.Error(), "not included in a block in time") {
+        t.Fatalf("expected the payout to time out, got %v", err)
+    }
+    if task, _ := client.store.Get("task-0"); task.Status != intTypes.TaskStatusCompleted || !lastEvent(client, "task-0", intTypes.TaskEventPaid, intTypes.TxPending)() {
+        t.Fatalf("expected a COMPLETED task with a pending payout, got %+v", task)
+    }
+    var transition *intTypes.InvalidTransitionError
+    if err := client.ApproveTask(intTypes.Task{ID: "task-0"}, admin); !errors.As(err, &transition) {
+        t.Fatalf("expected a second approval to be refused, got %v", err)
+    }
+    if node.Mempool() != 1 {
+        t.Fatalf("expected one payout in the mempool, got %d", node.Mempool())
+    }
+    if results := node.Commit(); len(results) != 1 || results[0].Code != 0 {
+        t.Fatalf("expected the payout to succeed, got %+v", results)
+    }
+    eventually(t, "late payout", lastEvent(client, "task-0", intTypes.TaskEventPaid, intTypes.TxConfirmed))
+    eventually(t, "released escrow", func() bool {
+        escrow, _ := client.GetTaskEscrow("task-0")
+        return escrow.Status == intTypes.ESCROW_RELEASED
+    })
+}
+
+// openStores returns constructors for every store the client runs on.
+func openStores(t *testing.T) map[string]func() store.Store {
+    return map[string]func() store.Store{
+        "memory": func() store.Store { return store.NewMemoryStore() },
+        "bolt": func() store.Store {
+            st, err := store.OpenBoltStore(filepath.Join(t.TempDir(), "bounty.db"))
+            if err != nil {
+                t.Fatalf("failed to open bolt store: %v", err)
+            }
+            t.Cleanup(func() { st.Close() })
+            return st
+        },
+    }
+}
+
+func TestFailedTransactionsRollBack(t *testing.T) {
+    // The claimer cannot pay the claim fee, and the escrow account only
+    // holds the bounty, so it cannot pay the payout or refund fee either
+    cases := []struct {
+        name   string
+        setup  func(client *BlockchainClient, node *chainsim.FakeNode, claimer string) error
+        fail   func(client *BlockchainClient, claimer string) error
+        status intTypes.TaskStatus
+    }{
+        {
+            name: "claim",
+            fail: func(client *BlockchainClient, claimer string) error {
+                return client.ClaimTask("task-0", claimer, "https://github.com/proof")
+            },
+            status: intTypes.TaskStatusOpen,
+        },
+        {
+            name: "payout",
+            setup: func(client *BlockchainClient, node *chainsim.FakeNode, claimer string) error {
+                node.Ledger.Fund(claimer, sdk.NewCoins(sdk.NewInt64Coin(Denom, 10000)))
+                return client.ClaimTask("task-0", claimer, "https://github.com/proof")
+            },
+            fail: func(client *BlockchainClient, claimer string) error {
+                return client.ApproveTask(intTypes.Task{ID: "task-0"}, client.GetAdminAddress())
+            },
+            status: intTypes.TaskStatusClaimed,
+        },
+        {
+            name: "refund",
+            fail: func(client *BlockchainClient, claimer string) error {
+                return client.CancelTask("task-0", client.GetTestWallets()[1])
+            },
+            status: intTypes.TaskStatusOpen,
+        },
+    }
+
+    for name, open := range openStores(t) {
+        for _, tc := range cases {
+            t.Run(name+"/"+tc.name, func(t *testing.T) {
+                node := chainsim.NewFakeNode(chainsim.NewLedger("fake-chain", MakeEncodingConfig().TxConfig))
+                defer node.Close()
+                client := newTestClient(
+                    WithStore(open()),
+                    WithBackend(NewRESTBackend("fake-chain", node.URL, node.URL)),
+                    WithConfirmPolicy(ConfirmPolicy{Timeout: 5 * time.Second, Interval: 5 * time.Millisecond}),
+                    fixedFees(),
+                )
+                creator, claimer := client.GetTestWallets()[1], client.GenerateTestAddress("claimer-1")
+                node.Ledger.Fund(creator, sdk.NewCoins(sdk.NewInt64Coin(Denom, 10000000)))
+                node.Ledger.Fund(claimer, sdk.NewCoins(sdk.NewInt64Coin(Denom, 500)))
+
+                if err := client.CreateTask(intTypes.Task{ID: "task-0", Title: "Test Task", Creator: creator, Bounty: "1000000"}); err != nil {
+                    t.Fatalf("create failed: %v", err)
+                }
+                if tc.setup != nil {
+                    if err := tc.setup(client, node, claimer); err != nil {
+                        t.Fatalf("setup failed: %v", err)
+                    }
+                }
+                before, _ := client.store.Get("task-0")
+
+                // Hold the transaction so it fails in a block after it was recorded as pending
+                node.Hold()
+                done := make(chan error, 1)
+                go func() { done <- tc.fail(client, claimer) }()
+                eventually(t, "broadcast", func() bool { return node.Mempool() == 1 })
+                node.Commit()
+                if err := <-done; err == nil || !strings.Contains(err.Error(), "failed in its block") {
+                    t.Fatalf("expected the transaction to fail in its block, got %v", err)
+                }
+
+                task, _ := client.store.Get("task-0")
+                if task.Status != tc.status || task.Claimer != before.Claimer || len(task.Transitions) != len(before.Transitions) {
+                    t.Fatalf("expected the task to be rolled back to %s, got %+v", tc.status, task)
+                }
+                if !lastEvent(client, "task-0", intTypes.TaskEventTxFailed, intTypes.TxFailed)() {
+                    t.Fatalf("expected the failed transaction in the history, got %+v", task.History)
+                }
+                if escrow, _ := client.GetTaskEscrow("task-0"); escrow.Status != intTypes.ESCROW_LOCKED {
+                    t.Fatalf("expected the bounty to stay locked, got %+v", escrow)
+                }
+            })
+        }
+    }
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+    "fmt"
+    "math"
+    intTypes "bounty-system/internal/types"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+// DefaultGasAdjustment leaves room for state that changes between the
+// simulation and the block, the way the SDK CLI's --gas-adjustment does.
+const DefaultGasAdjustment = 1.3
+
+// DefaultGasPrice prices DefaultGasLimit gas at DefaultFeeAmount.
+var DefaultGasPrice = sdk.NewDecCoinFromDec(Denom, sdk.NewDec(DefaultFeeAmount).QuoInt64(int64(DefaultGasLimit)))
+
+// FeePolicy decides the gas limit and fee of the client's transactions.
+type FeePolicy struct {
+    Simulate      bool        // Estimate gas through the backend; otherwise every tx gets DefaultGasLimit
+    GasAdjustment float64     // Multiplies the simulated gas
+    GasPrice      sdk.DecCoin // Fee per unit of gas limit
+}
+
+// DefaultFeePolicy simulates every transaction.
+func DefaultFeePolicy() FeePolicy {
+    return FeePolicy{Simulate: true, GasAdjustment: DefaultGasAdjustment, GasPrice: DefaultGasPrice}
+}
+
+// WithFeePolicy sets how gas and fees are chosen.
+func WithFeePolicy(policy FeePolicy) Option {
+    return func(c *BlockchainClient) {
+        c.fees = policy
+    }
+}
+
+// ParseGasPrice parses a gas price like "0.005microSERVDR".
+func ParseGasPrice(price string) (sdk.DecCoin, error) {
+    coin, err := sdk.ParseDecCoin(price)
+    if err != nil {
+        return sdk.DecCoin{}, fmt.Errorf("invalid gas price %q: %v", price, err)
+    }
+    if coin.Denom != Denom {
+        return sdk.DecCoin{}, fmt.Errorf("gas price must be in %s, got %s", Denom, coin.Denom)
+    }
+    return coin, nil
+}
+
+// fee returns the fee for a gas limit, rounded up to whole units.
+func (p FeePolicy) fee(gasLimit uint64) sdk.Coin {
+    return sdk.NewCoin(p.GasPrice.Denom, p.GasPrice.Amount.MulInt64(int64(gasLimit)).Ceil().TruncateInt())
+}
+
+// gasLimit adjusts simulated gas.
+func (p FeePolicy) gasLimit(gasUsed uint64) uint64 {
+    return uint64(math.Ceil(float64(gasUsed) * p.GasAdjustment))
+}
+
+// FeeEstimate is what a transaction is expected to cost its signer.
+type FeeEstimate struct {
+    GasUsed  uint64 `json:"gas_used"` // Simulated, 0 when the client does not simulate
+    GasLimit uint64 `json:"gas_limit"`
+    Fee      string `json:"fee"`
+    Amount   string `json:"amount"` // Sent along with the fee, e.g. the bounty
+    Total    string `json:"total"`  // Amount plus Fee
+    Denom    string `json:"denom"`
+}
+
+// estimate works out the gas limit and fee of a transaction for signer. With
+// simulation on, the transaction is signed once with DefaultGasLimit and run
+// through the backend's simulation.
+func (c *BlockchainClient) estimate(signer string, msgs []sdk.Msg, memo string, account SignerAccount) (FeeEstimate, error) {
+    estimate := FeeEstimate{GasLimit: DefaultGasLimit, Denom: Denom}
+    if c.fees.Simulate {
+        txBytes, err := c.signTx(signer, msgs, memo, account, DefaultGasLimit, c.fees.fee(DefaultGasLimit))
+        if err != nil {
+            return FeeEstimate{}, err
+        }
+        gasUsed, err := c.backend.Simulate(txBytes)
+        if err != nil {
+            return FeeEstimate{}, &BackendError{Err: fmt.Errorf("failed to simulate transaction: %v", err)}
+        }
+        estimate.GasUsed = gasUsed
+        estimate.GasLimit = c.fees.gasLimit(gasUsed)
+    }
+
+    fee := c.fees.fee(estimate.GasLimit)
+    amount := sdk.ZeroInt()
+    for _, msg := range msgs {
+        if send, ok := msg.(*banktypes.MsgSend); ok {
+            amount = amount.Add(send.Amount.AmountOf(Denom))
+        }
+    }
+    estimate.Fee = fee.Amount.String()
+    estimate.Amount = amount.String()
+    estimate.Total = amount.Add(fee.Amount).String()
+    return estimate, nil
+}
+
+// EstimateCreateTask simulates the transaction CreateTask would send for
+// task without storing or broadcasting anything, so its creator can see
+// what posting the bounty costs.
+func (c *BlockchainClient) EstimateCreateTask(task intTypes.Task) (FeeEstimate, error) {
+    if task.Title == "" || task.Bounty == "" {
+        return FeeEstimate{}, intTypes.Invalidf("invalid task parameters")
+    }
+    if err := c.Authorize(task.Creator, intTypes.ActionCreateTask); err != nil {
+        return FeeEstimate{}, err
+    }
+    msgs, memo, err := c.createTaskMsgs(task)
+    if err != nil {
+        return FeeEstimate{}, err
+    }
+
+    // Simulate with the sequence the creator's next transaction will use
+    var estimate FeeEstimate
+    err = c.sequences.peek(task.Creator, func(account SignerAccount) error {
+        estimate, err = c.estimate(task.Creator, msgs, memo, account)
+        return err
+    })
+    return estimate, err
+}
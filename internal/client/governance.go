@@ -0,0 +1,237 @@
+package client
+
+import (
+    "errors"
+    "fmt"
+    "log"
+    "sort"
+    "time"
+
+    "bounty-system/internal/ids"
+    "bounty-system/internal/store"
+    intTypes "bounty-system/internal/types"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// GovernancePolicy decides how proposals to change the admin set are voted on.
+type GovernancePolicy struct {
+    Quorum       int           // Yes votes needed; 0 means a majority of the admins
+    VotingPeriod time.Duration // How long a proposal is open for votes
+    Timelock     time.Duration // Delay between passing and execution
+}
+
+// DefaultGovernancePolicy gives admins three days to vote and one day to
+// react before a passed change takes effect.
+func DefaultGovernancePolicy() GovernancePolicy {
+    return GovernancePolicy{VotingPeriod: 72 * time.Hour, Timelock: 24 * time.Hour}
+}
+
+// WithGovernancePolicy sets how admin-set proposals are decided.
+func WithGovernancePolicy(policy GovernancePolicy) Option {
+    return func(c *BlockchainClient) {
+        c.governance = policy
+    }
+}
+
+// requiredVotes returns the quorum for an electorate of admins.
+func (p GovernancePolicy) requiredVotes(electorate int) int {
+    required := electorate/2 + 1
+    if p.Quorum > 0 {
+        required = p.Quorum
+    }
+    if required > electorate {
+        required = electorate
+    }
+    return required
+}
+
+// ProposeAdminChange opens a vote on adding or removing an admin. The
+// proposer's own yes vote is counted right away.
+func (c *BlockchainClient) ProposeAdminChange(kind intTypes.ProposalKind, target string, proposer string) (intTypes.Proposal, error) {
+    if err := c.Authorize(proposer, intTypes.ActionGovernAdmins); err != nil {
+        return intTypes.Proposal{}, err
+    }
+    if _, err := sdk.AccAddressFromBech32(target); err != nil {
+        return intTypes.Proposal{}, intTypes.Invalidf("invalid address: %v", err)
+    }
+    if err := c.checkAdminChange(kind, target); err != nil {
+        return intTypes.Proposal{}, err
+    }
+
+    now := c.now()
+    electorate := len(c.ListAdmins())
+    proposal := intTypes.Proposal{
+        ID:            ids.New("proposal"),
+        Kind:          kind,
+        Target:        target,
+        Proposer:      proposer,
+        Status:        intTypes.ProposalPending,
+        Electorate:    electorate,
+        RequiredVotes: c.governance.requiredVotes(electorate),
+        CreatedAt:     now.UTC(),
+        VotingEndsAt:  now.Add(c.governance.VotingPeriod).UTC(),
+        Votes:         []intTypes.Vote{{Voter: proposer, Approve: true, VotedAt: now.UTC()}},
+    }
+    proposal.Record("created", proposer, now, fmt.Sprintf("%s %s", kind, target))
+    proposal.Record("voted", proposer, now, "yes")
+    proposal.Settle(now, c.governance.Timelock)
+
+    if err := c.store.PutProposal(proposal); err != nil {
+        return intTypes.Proposal{}, fmt.Errorf("failed to store proposal: %v", err)
+    }
+    log.Printf("Proposal %s (%s %s) opened by %s", proposal.ID, kind, target, proposer)
+    return proposal, nil
+}
+
+// VoteProposal casts voter's ballot on a pending proposal.
+func (c *BlockchainClient) VoteProposal(id string, voter string, approve bool) (intTypes.Proposal, error) {
+    if err := c.Authorize(voter, intTypes.ActionGovernAdmins); err != nil {
+        return intTypes.Proposal{}, err
+    }
+
+    now := c.now()
+    voted := false
+    proposal, err := c.store.UpdateProposal(id, func(p *intTypes.Proposal) error {
+        // Persist an outcome the clock already decided, the vote comes too late
+        if p.Settle(now, c.governance.Timelock) {
+            return nil
+        }
+        if p.Status != intTypes.ProposalPending {
+            return intTypes.Invalidf("proposal %s is %s", id, p.Status)
+        }
+        for _, vote := range p.Votes {
+            if vote.Voter == voter {
+                return intTypes.Invalidf("%s already voted on proposal %s", voter, id)
+            }
+        }
+
+        p.Votes = append(p.Votes, intTypes.Vote{Voter: voter, Approve: approve, VotedAt: now.UTC()})
+        ballot := "no"
+        if approve {
+            ballot = "yes"
+        }
+        p.Record("voted", voter, now, ballot)
+        p.Settle(now, c.governance.Timelock)
+        voted = true
+        return nil
+    })
+    if errors.Is(err, store.ErrNotFound) {
+        return intTypes.Proposal{}, ErrProposalNotFound
+    }
+    if err != nil {
+        return intTypes.Proposal{}, err
+    }
+    if !voted {
+        return proposal, intTypes.Invalidf("proposal %s is %s", id, proposal.Status)
+    }
+    return proposal, nil
+}
+
+// ExecuteProposal applies a passed proposal once its time-lock has run out.
+// The admin set is checked and changed under adminsMu, so two proposals
+// executed at once cannot, say, both remove one of the last two admins.
+func (c *BlockchainClient) ExecuteProposal(id string, executor string) (intTypes.Proposal, error) {
+    c.adminsMu.Lock()
+    defer c.adminsMu.Unlock()
+
+    if err := c.Authorize(executor, intTypes.ActionGovernAdmins); err != nil {
+        return intTypes.Proposal{}, err
+    }
+    current, err := c.GetProposal(id)
+    if err != nil {
+        return intTypes.Proposal{}, err
+    }
+    // The admin set may have changed since the vote
+    applyErr := c.checkAdminChange(current.Kind, current.Target)
+
+    now := c.now()
+    proposal, err := c.store.UpdateProposal(id, func(p *intTypes.Proposal) error {
+        if p.Status != intTypes.ProposalPassed {
+            return intTypes.Invalidf("proposal %s is %s", id, p.Status)
+        }
+        if p.ExecutableAt != nil && now.Before(*p.ExecutableAt) {
+            return intTypes.Invalidf("proposal %s is time-locked until %s", id, p.ExecutableAt.Format(time.RFC3339))
+        }
+        if applyErr != nil {
+            p.Status = intTypes.ProposalFailed
+            p.Record("failed", executor, now, applyErr.Error())
+            return nil
+        }
+        p.Status = intTypes.ProposalExecuted
+        p.Record("executed", executor, now, "")
+        return nil
+    })
+    if err != nil {
+        return intTypes.Proposal{}, err
+    }
+    if applyErr != nil {
+        return proposal, intTypes.Invalidf("proposal %s can no longer be applied: %v", id, applyErr)
+    }
+
+    role := intTypes.ROLE_ADMIN
+    if proposal.Kind == intTypes.ProposalRemoveAdmin {
+        role = intTypes.ROLE_USER
+    }
+    if err := c.store.PutUser(intTypes.User{Address: proposal.Target, Role: role}); err != nil {
+        return proposal, fmt.Errorf("failed to store role: %v", err)
+    }
+    log.Printf("Proposal %s executed by %s: %s is now %s", id, executor, proposal.Target, role)
+    return proposal, nil
+}
+
+// GetProposal returns a proposal, settling it first if its voting window
+// has closed.
+func (c *BlockchainClient) GetProposal(id string) (intTypes.Proposal, error) {
+    now := c.now()
+    proposal, err := c.store.UpdateProposal(id, func(p *intTypes.Proposal) error {
+        p.Settle(now, c.governance.Timelock)
+        return nil
+    })
+    if errors.Is(err, store.ErrNotFound) {
+        return intTypes.Proposal{}, ErrProposalNotFound
+    }
+    return proposal, err
+}
+
+// ListProposals returns all proposals, newest first. requestor must be
+// allowed to view proposals.
+func (c *BlockchainClient) ListProposals(requestor string) ([]intTypes.Proposal, error) {
+    if err := c.Authorize(requestor, intTypes.ActionViewProposals); err != nil {
+        return nil, err
+    }
+    proposals, err := c.store.ListProposals()
+    if err != nil {
+        return nil, err
+    }
+    for i, proposal := range proposals {
+        if proposal.Status == intTypes.ProposalPending {
+            if settled, err := c.GetProposal(proposal.ID); err == nil {
+                proposals[i] = settled
+            }
+        }
+    }
+    sort.Slice(proposals, func(i, j int) bool {
+        return proposals[i].CreatedAt.After(proposals[j].CreatedAt)
+    })
+    return proposals, nil
+}
+
+// checkAdminChange reports whether kind can be applied to target now.
+func (c *BlockchainClient) checkAdminChange(kind intTypes.ProposalKind, target string) error {
+    switch kind {
+    case intTypes.ProposalAddAdmin:
+        if c.IsAdmin(target) {
+            return intTypes.Invalidf("%s is already an admin", target)
+        }
+    case intTypes.ProposalRemoveAdmin:
+        if !c.IsAdmin(target) {
+            return intTypes.Invalidf("%s is not an admin", target)
+        }
+        if len(c.ListAdmins()) <= 1 {
+            return intTypes.Invalidf("cannot remove last admin")
+        }
+    default:
+        return intTypes.Invalidf("unknown proposal kind %q", kind)
+    }
+    return nil
+}
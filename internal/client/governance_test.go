@@ -0,0 +1,182 @@
+package client
+
+import (
+    "sync"
+    "testing"
+    "time"
+
+    "bounty-system/internal/store"
+    intTypes "bounty-system/internal/types"
+)
+
+func TestAdminGovernance(t *testing.T) {
+    client := newTestClient(WithGovernancePolicy(GovernancePolicy{VotingPeriod: time.Hour, Timelock: 10 * time.Minute}))
+    now := time.Now()
+    client.now = func() time.Time { return now }
+
+    admin, user := client.GetTestWallets()[0], client.GetTestWallets()[1]
+    second := client.GenerateTestAddress("admin-2")
+    third := client.GenerateTestAddress("admin-3")
+
+    if _, err := client.AddAdmin(second, user); err == nil {
+        t.Fatalf("expected error when a user proposes an admin")
+    }
+    if err := client.AssignRole(second, intTypes.ROLE_ADMIN, admin); err == nil {
+        t.Fatalf("expected admins to be added only through proposals")
+    }
+
+    // A lone admin is its own quorum, but still has to wait for the time-lock
+    proposal, err := client.AddAdmin(second, admin)
+    if err != nil || proposal.Status != intTypes.ProposalPassed {
+        t.Fatalf("unexpected proposal: %+v %v", proposal, err)
+    }
+    if _, err := client.ExecuteProposal(proposal.ID, admin); err == nil || client.IsAdmin(second) {
+        t.Fatalf("expected the time-lock to hold back execution")
+    }
+    now = now.Add(11 * time.Minute)
+    if proposal, err = client.ExecuteProposal(proposal.ID, admin); err != nil || proposal.Status != intTypes.ProposalExecuted || !client.IsAdmin(second) {
+        t.Fatalf("execute failed: %+v %v", proposal, err)
+    }
+    if _, err := client.ExecuteProposal(proposal.ID, admin); err == nil {
+        t.Fatalf("expected error executing twice")
+    }
+
+    // With two admins a majority needs both votes
+    proposal, err = client.AddAdmin(third, admin)
+    if err != nil || proposal.Status != intTypes.ProposalPending || proposal.RequiredVotes != 2 {
+        t.Fatalf("unexpected proposal: %+v %v", proposal, err)
+    }
+    if _, err := client.VoteProposal(proposal.ID, admin, true); err == nil {
+        t.Fatalf("expected error voting twice")
+    }
+    if _, err := client.VoteProposal(proposal.ID, user, true); err == nil {
+        t.Fatalf("expected error when a user votes")
+    }
+    if proposal, err = client.VoteProposal(proposal.ID, second, true); err != nil || proposal.Status != intTypes.ProposalPassed {
+        t.Fatalf("vote failed: %+v %v", proposal, err)
+    }
+    now = now.Add(11 * time.Minute)
+    if _, err := client.ExecuteProposal(proposal.ID, second); err != nil || !client.IsAdmin(third) {
+        t.Fatalf("execute failed: %v", err)
+    }
+
+    // A proposal nobody else votes on expires with its window
+    proposal, err = client.RemoveAdmin(third, second)
+    if err != nil || proposal.Status != intTypes.ProposalPending {
+        t.Fatalf("unexpected proposal: %+v %v", proposal, err)
+    }
+    now = now.Add(2 * time.Hour)
+    if _, err := client.VoteProposal(proposal.ID, admin, true); err == nil {
+        t.Fatalf("expected error voting after the window")
+    }
+    if proposal, _ = client.GetProposal(proposal.ID); proposal.Status != intTypes.ProposalExpired || !client.IsAdmin(third) {
+        t.Fatalf("expected expired proposal, got %+v", proposal)
+    }
+
+    // Two of three admins voting no rejects the proposal
+    proposal, _ = client.RemoveAdmin(third, second)
+    if _, err := client.VoteProposal(proposal.ID, admin, false); err != nil {
+        t.Fatalf("vote failed: %v", err)
+    }
+    if proposal, _ = client.VoteProposal(proposal.ID, third, false); proposal.Status != intTypes.ProposalRejected {
+        t.Fatalf("expected rejected proposal, got %+v", proposal)
+    }
+
+    var actions []string
+    for _, event := range proposal.Audit {
+        actions = append(actions, event.Action)
+    }
+    if len(actions) != 5 || actions[0] != "created" || actions[4] != "rejected" {
+        t.Fatalf("unexpected audit trail: %v", actions)
+    }
+
+    proposals, err := client.ListProposals(admin)
+    if err != nil || len(proposals) != 4 {
+        t.Fatalf("unexpected proposal list: %d %v", len(proposals), err)
+    }
+    if _, err := client.ListProposals(user); err == nil {
+        t.Fatalf("expected error when a user lists proposals")
+    }
+}
+
+func TestBootstrapAdminOnlySeedsEmptyStore(t *testing.T) {
+    st := store.NewMemoryStore()
+    admin, successor := TestWalletAddress("admin-1"), TestWalletAddress("admin-2")
+    client := NewBlockchainClient(WithStore(st), WithAdmin(admin))
+    if admins := client.ListAdmins(); len(admins) != 1 || admins[0] != admin {
+        t.Fatalf("expected the bootstrap admin to be seeded, got %v", admins)
+    }
+
+    // The admin set was changed, as governance would
+    if err := st.PutUser(intTypes.User{Address: successor, Role: intTypes.ROLE_ADMIN}); err != nil {
+        t.Fatalf("put failed: %v", err)
+    }
+    if err := st.PutUser(intTypes.User{Address: admin, Role: intTypes.ROLE_USER}); err != nil {
+        t.Fatalf("put failed: %v", err)
+    }
+
+    // Restarting with the same configuration does not reinstate the old admin
+    client = NewBlockchainClient(WithStore(st), WithAdmin(admin))
+    if client.IsAdmin(admin) {
+        t.Fatalf("expected the removed bootstrap admin to stay removed")
+    }
+    if admins := client.ListAdmins(); len(admins) != 1 || admins[0] != successor {
+        t.Fatalf("expected only %s to be admin, got %v", successor, admins)
+    }
+}
+
+// slowProposals widens the gap between a proposal update and whatever the
+// client does next, so concurrent executions overlap.
+type slowProposals struct {
+    store.Store
+}
+
+func (s slowProposals) UpdateProposal(id string, fn func(proposal *intTypes.Proposal) error) (intTypes.Proposal, error) {
+    proposal, err := s.Store.UpdateProposal(id, fn)
+    time.Sleep(5 * time.Millisecond)
+    return proposal, err
+}
+
+func TestConcurrentAdminRemovalsKeepLastAdmin(t *testing.T) {
+    for round := 0; round < 5; round++ {
+        client := newTestClient(
+            WithStore(slowProposals{store.NewMemoryStore()}),
+            WithGovernancePolicy(GovernancePolicy{VotingPeriod: time.Hour, Timelock: time.Minute}),
+        )
+        now := time.Now()
+        client.now = func() time.Time { return now }
+
+        admin := client.GetTestWallets()[0]
+        second := client.GenerateTestAddress("admin-2")
+        proposal, _ := client.AddAdmin(second, admin)
+        now = now.Add(2 * time.Minute)
+        if _, err := client.ExecuteProposal(proposal.ID, admin); err != nil {
+            t.Fatalf("execute failed: %v", err)
+        }
+
+        // Both admins vote each other out; each proposal passes on its own
+        first, _ := client.RemoveAdmin(second, admin)
+        if _, err := client.VoteProposal(first.ID, second, true); err != nil {
+            t.Fatalf("vote failed: %v", err)
+        }
+        other, _ := client.RemoveAdmin(admin, second)
+        if _, err := client.VoteProposal(other.ID, admin, true); err != nil {
+            t.Fatalf("vote failed: %v", err)
+        }
+        now = now.Add(2 * time.Minute)
+
+        var wg sync.WaitGroup
+        for _, execution := range []struct{ id, executor string }{{first.ID, admin}, {other.ID, second}} {
+            wg.Add(1)
+            go func(id, executor string) {
+                defer wg.Done()
+                client.ExecuteProposal(id, executor)
+            }(execution.id, execution.executor)
+        }
+        wg.Wait()
+
+        if admins := client.ListAdmins(); len(admins) != 1 {
+            t.Fatalf("expected exactly one admin to remain, got %v", admins)
+        }
+    }
+}
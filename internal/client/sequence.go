@@ -0,0 +1,135 @@
+package client
+
+import (
+    "fmt"
+    "log"
+    "strings"
+    "sync"
+)
+
+// maxSequenceRetries bounds how often a tx is rebuilt after the node reports
+// an account sequence mismatch.
+const maxSequenceRetries = 3
+
+// signerState caches the account number and next sequence of one signer. Its
+// mutex is held for the whole build-sign-broadcast cycle so two transactions
+// from the same wallet never share a sequence.
+type signerState struct {
+    mu      sync.Mutex
+    loaded  bool
+    account SignerAccount
+}
+
+// sequenceManager tracks account numbers and sequences per signer.
+type sequenceManager struct {
+    mu      sync.Mutex
+    signers map[string]*signerState
+    fetch   func(address string) (SignerAccount, error)
+}
+
+func newSequenceManager(fetch func(address string) (SignerAccount, error)) *sequenceManager {
+    return &sequenceManager{
+        signers: make(map[string]*signerState),
+        fetch:   fetch,
+    }
+}
+
+func (m *sequenceManager) signer(address string) *signerState {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    state, exists := m.signers[address]
+    if !exists {
+        state = &signerState{}
+        m.signers[address] = state
+    }
+    return state
+}
+
+// do runs send with the signer's current account state. On success the
+// cached sequence is incremented locally. On a sequence mismatch the state
+// is refetched from the node and send is retried.
+func (m *sequenceManager) do(address string, send func(account SignerAccount) (string, error)) (string, error) {
+    var txHash string
+    err := m.run(address, true, func(account SignerAccount) error {
+        var err error
+        txHash, err = send(account)
+        return err
+    })
+    if err != nil {
+        return "", err
+    }
+    return txHash, nil
+}
+
+// peek runs fn with the signer's current account state like do, but leaves
+// the sequence unused, e.g. to simulate the signer's next transaction.
+func (m *sequenceManager) peek(address string, fn func(account SignerAccount) error) error {
+    return m.run(address, false, fn)
+}
+
+// run calls fn with the signer's account state, refetching it and retrying
+// on a sequence mismatch. consume marks the sequence used once fn succeeded.
+func (m *sequenceManager) run(address string, consume bool, fn func(account SignerAccount) error) error {
+    state := m.signer(address)
+    state.mu.Lock()
+    defer state.mu.Unlock()
+
+    var lastErr error
+    for attempt := 0; attempt <= maxSequenceRetries; attempt++ {
+        if !state.loaded {
+            account, err := m.fetch(address)
+            if err != nil {
+                return &BackendError{Err: fmt.Errorf("failed to get account info for %s: %v", address, err)}
+            }
+            state.account = account
+            state.loaded = true
+        }
+
+        err := fn(state.account)
+        if err == nil {
+            if consume {
+                state.account.Sequence++
+            }
+            return nil
+        }
+
+        // Whatever happened, the cached sequence can no longer be trusted
+        state.loaded = false
+        lastErr = err
+        if !isSequenceMismatch(err) {
+            return err
+        }
+        log.Printf("Account sequence mismatch for %s (sequence %d), resyncing", address, state.account.Sequence)
+    }
+
+    return fmt.Errorf("giving up after %d sequence retries: %w", maxSequenceRetries, lastErr)
+}
+
+// reset makes the next transaction of address refetch its account state.
+func (m *sequenceManager) reset(address string) {
+    state := m.signer(address)
+    state.mu.Lock()
+    defer state.mu.Unlock()
+    state.loaded = false
+}
+
+func isSequenceMismatch(err error) bool {
+    return strings.Contains(err.Error(), "account sequence mismatch")
+}
+
+// signAndSubmit builds a transaction for signer with its tracked account
+// state and broadcasts it, retrying on sequence mismatches.
+func (c *BlockchainClient) signAndSubmit(signer string, build func(account SignerAccount) ([]byte, error)) (string, error) {
+    return c.sequences.do(signer, func(account SignerAccount) (string, error) {
+        txBytes, err := build(account)
+        if err != nil {
+            return "", err
+        }
+        txHash, err := c.backend.Broadcast(txBytes)
+        if err != nil {
+            return "", &BackendError{Err: err}
+        }
+        return txHash, nil
+    })
+}
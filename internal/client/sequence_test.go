@@ -0,0 +1,86 @@
+package client
+
+import (
+    "fmt"
+    "sync"
+    "testing"
+)
+
+// fakeAccountNode mimics the node's ante handler: it accepts a tx only if it
+// carries the account's current sequence.
+type fakeAccountNode struct {
+    mu       sync.Mutex
+    sequence uint64
+    fetches  int
+    accepted []uint64
+}
+
+func (n *fakeAccountNode) fetch(address string) (SignerAccount, error) {
+    n.mu.Lock()
+    defer n.mu.Unlock()
+    n.fetches++
+    return SignerAccount{AccountNumber: 9, Sequence: n.sequence}, nil
+}
+
+func (n *fakeAccountNode) broadcast(account SignerAccount) (string, error) {
+    n.mu.Lock()
+    defer n.mu.Unlock()
+    if account.Sequence != n.sequence {
+        return "", fmt.Errorf("account sequence mismatch, expected %d, got %d: incorrect account sequence", n.sequence, account.Sequence)
+    }
+    n.accepted = append(n.accepted, account.Sequence)
+    n.sequence++
+    return fmt.Sprintf("TX%d", account.Sequence), nil
+}
+
+func TestSequenceManagerConcurrentSigner(t *testing.T) {
+    node := &fakeAccountNode{sequence: 5}
+    manager := newSequenceManager(node.fetch)
+
+    var wg sync.WaitGroup
+    for i := 0; i < 20; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            if _, err := manager.do("admin", node.broadcast); err != nil {
+                t.Errorf("broadcast failed: %v", err)
+            }
+        }()
+    }
+    wg.Wait()
+
+    if len(node.accepted) != 20 || node.sequence != 25 {
+        t.Fatalf("expected 20 accepted txs ending at sequence 25, got %d ending at %d", len(node.accepted), node.sequence)
+    }
+    if node.fetches != 1 {
+        t.Fatalf("expected a single account fetch, got %d", node.fetches)
+    }
+}
+
+func TestSequenceManagerResyncsOnMismatch(t *testing.T) {
+    node := &fakeAccountNode{sequence: 0}
+    manager := newSequenceManager(node.fetch)
+
+    if _, err := manager.do("admin", node.broadcast); err != nil {
+        t.Fatalf("first broadcast failed: %v", err)
+    }
+
+    // Another process spends from the same wallet behind our back
+    node.sequence += 3
+
+    txHash, err := manager.do("admin", node.broadcast)
+    if err != nil {
+        t.Fatalf("expected retry to succeed, got %v", err)
+    }
+    if txHash != "TX4" || node.fetches != 2 {
+        t.Fatalf("expected resync and tx at sequence 4, got %s after %d fetches", txHash, node.fetches)
+    }
+
+    // Non-sequence errors are returned without retrying
+    _, err = manager.do("admin", func(account SignerAccount) (string, error) {
+        return "", fmt.Errorf("insufficient funds")
+    })
+    if err == nil || err.Error() != "insufficient funds" {
+        t.Fatalf("expected insufficient funds error, got %v", err)
+    }
+}
@@ -0,0 +1,281 @@
+package client
+
+import (
+    "encoding/json"
+    "fmt"
+    "encoding/base64"
+    "strings"
+    "bounty-system/internal/auth"
+    "bounty-system/internal/types"
+    bountytypes "bounty-system/x/bounty/types"
+
+    sdkclient "github.com/cosmos/cosmos-sdk/client"
+    clienttx "github.com/cosmos/cosmos-sdk/client/tx"
+    "github.com/cosmos/cosmos-sdk/codec"
+    codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+    "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+    "github.com/cosmos/cosmos-sdk/std"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    "github.com/cosmos/cosmos-sdk/types/tx/signing"
+    authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+    authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+    authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+    banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+const (
+    Denom            = "microSERVDR"
+    DefaultGasLimit  = uint64(200000)
+    DefaultFeeAmount = int64(1000)
+)
+
+// EncodingConfig bundles the codecs needed to build, sign and decode
+// protobuf transactions.
+type EncodingConfig struct {
+    InterfaceRegistry codectypes.InterfaceRegistry
+    Marshaler         codec.Codec
+    TxConfig          sdkclient.TxConfig
+    Amino             *codec.LegacyAmino
+}
+
+func MakeEncodingConfig() EncodingConfig {
+    amino := codec.NewLegacyAmino()
+    interfaceRegistry := codectypes.NewInterfaceRegistry()
+    marshaler := codec.NewProtoCodec(interfaceRegistry)
+
+    // Register crypto, auth, bank and bounty types so txs and accounts can be decoded
+    std.RegisterInterfaces(interfaceRegistry)
+    authtypes.RegisterInterfaces(interfaceRegistry)
+    banktypes.RegisterInterfaces(interfaceRegistry)
+    bountytypes.RegisterInterfaces(interfaceRegistry)
+
+    return EncodingConfig{
+        InterfaceRegistry: interfaceRegistry,
+        Marshaler:         marshaler,
+        TxConfig:          authtx.NewTxConfig(marshaler, authtx.DefaultSignModes),
+        Amino:             amino,
+    }
+}
+
+// SignerAccount carries the on-chain account number and sequence a
+// transaction is signed for.
+type SignerAccount struct {
+    AccountNumber uint64
+    Sequence      uint64
+}
+
+// privKeyFor returns the key the client holds for address.
+func (c *BlockchainClient) privKeyFor(address string) (*secp256k1.PrivKey, error) {
+    c.walletsMu.RLock()
+    key, exists := c.walletKeys[address]
+    c.walletsMu.RUnlock()
+    if !exists {
+        return nil, types.Invalidf("wallet key not found for %s", address)
+    }
+    return key, nil
+}
+
+// SignChallenge answers an auth challenge with the key of one of the
+// client's own wallets, the way a browser wallet would.
+func (c *BlockchainClient) SignChallenge(challenge auth.Challenge) (auth.SignedChallenge, error) {
+    privKey, err := c.privKeyFor(challenge.Address)
+    if err != nil {
+        return auth.SignedChallenge{}, err
+    }
+    signature, err := privKey.Sign(auth.ADR036SignBytes(challenge.Address, []byte(challenge.Message)))
+    if err != nil {
+        return auth.SignedChallenge{}, fmt.Errorf("failed to sign challenge: %v", err)
+    }
+    return auth.SignedChallenge{
+        Address:   challenge.Address,
+        Nonce:     challenge.Nonce,
+        PubKey:    base64.StdEncoding.EncodeToString(privKey.PubKey().Bytes()),
+        Signature: base64.StdEncoding.EncodeToString(signature),
+    }, nil
+}
+
+// buildSignedTx builds a protobuf transaction for msgs, signs it with
+// SIGN_MODE_DIRECT on behalf of signer and returns the encoded TxRaw bytes.
+// Its gas limit and fee follow the client's FeePolicy.
+func (c *BlockchainClient) buildSignedTx(signer string, msgs []sdk.Msg, memo string, account SignerAccount) ([]byte, error) {
+    estimate, err := c.estimate(signer, msgs, memo, account)
+    if err != nil {
+        return nil, err
+    }
+    return c.signTx(signer, msgs, memo, account, estimate.GasLimit, c.fees.fee(estimate.GasLimit))
+}
+
+// signTx builds and signs a transaction with the given gas limit and fee.
+func (c *BlockchainClient) signTx(signer string, msgs []sdk.Msg, memo string, account SignerAccount, gasLimit uint64, fee sdk.Coin) ([]byte, error) {
+    privKey, err := c.privKeyFor(signer)
+    if err != nil {
+        return nil, err
+    }
+
+    txConfig := c.encodingConfig.TxConfig
+    txBuilder := txConfig.NewTxBuilder()
+    if err := txBuilder.SetMsgs(msgs...); err != nil {
+        return nil, fmt.Errorf("failed to set messages: %v", err)
+    }
+    txBuilder.SetMemo(memo)
+    txBuilder.SetGasLimit(gasLimit)
+    txBuilder.SetFeeAmount(sdk.NewCoins(fee))
+
+    // The signer info (pubkey, mode, sequence) is part of AuthInfo and so
+    // part of the sign bytes: set it with an empty signature first.
+    signMode := signing.SignMode_SIGN_MODE_DIRECT
+    emptySig := signing.SignatureV2{
+        PubKey:   privKey.PubKey(),
+        Data:     &signing.SingleSignatureData{SignMode: signMode},
+        Sequence: account.Sequence,
+    }
+    if err := txBuilder.SetSignatures(emptySig); err != nil {
+        return nil, fmt.Errorf("failed to set signer info: %v", err)
+    }
+
+    signerData := authsigning.SignerData{
+        ChainID:       c.GetChainID(),
+        AccountNumber: account.AccountNumber,
+        Sequence:      account.Sequence,
+    }
+    sig, err := clienttx.SignWithPrivKey(signMode, signerData, txBuilder, privKey, txConfig, account.Sequence)
+    if err != nil {
+        return nil, fmt.Errorf("failed to sign transaction: %v", err)
+    }
+    if err := txBuilder.SetSignatures(sig); err != nil {
+        return nil, fmt.Errorf("failed to set signature: %v", err)
+    }
+
+    txBytes, err := txConfig.TxEncoder()(txBuilder.GetTx())
+    if err != nil {
+        return nil, fmt.Errorf("failed to encode transaction: %v", err)
+    }
+    return txBytes, nil
+}
+
+// sendMsg builds a bank MsgSend of amount microSERVDR.
+func sendMsg(from string, to string, amount string) (sdk.Msg, error) {
+    fromAddr, err := sdk.AccAddressFromBech32(from)
+    if err != nil {
+        return nil, types.Invalidf("invalid sender address %s: %v", from, err)
+    }
+    toAddr, err := sdk.AccAddressFromBech32(to)
+    if err != nil {
+        return nil, types.Invalidf("invalid recipient address %s: %v", to, err)
+    }
+    amt, ok := sdk.NewIntFromString(strings.TrimSpace(amount))
+    if !ok || !amt.IsPositive() {
+        return nil, types.Invalidf("invalid amount: %s", amount)
+    }
+    return banktypes.NewMsgSend(fromAddr, toAddr, sdk.NewCoins(sdk.NewCoin(Denom, amt))), nil
+}
+
+// taskMemo encodes task metadata into the tx memo so indexers can rebuild
+// task state from chain history.
+func taskMemo(metadata map[string]interface{}) (string, error) {
+    metadataBytes, err := json.Marshal(metadata)
+    if err != nil {
+        return "", fmt.Errorf("failed to marshal metadata: %v", err)
+    }
+    return string(metadataBytes), nil
+}
+
+// createTaskTx locks the bounty by sending it from the creator to escrow.
+func (c *BlockchainClient) createTaskTx(task types.Task, account SignerAccount) ([]byte, error) {
+    msgs, memo, err := c.createTaskMsgs(task)
+    if err != nil {
+        return nil, err
+    }
+    return c.buildSignedTx(task.Creator, msgs, memo, account)
+}
+
+// createTaskMsgs returns the messages and memo of createTaskTx.
+func (c *BlockchainClient) createTaskMsgs(task types.Task) ([]sdk.Msg, string, error) {
+    msg, err := sendMsg(task.Creator, c.GetEscrowAddress(), task.Bounty)
+    if err != nil {
+        return nil, "", err
+    }
+    memo, err := taskMemo(map[string]interface{}{
+        "type":        "create_task",
+        "task_id":     task.ID,
+        "title":       task.Title,
+        "description": task.Description,
+        "status":      types.TaskStatusOpen,
+    })
+    if err != nil {
+        return nil, "", err
+    }
+    return []sdk.Msg{msg}, memo, nil
+}
+
+// claimTaskTx records a claim on chain. The proof may be empty and follow
+// later in a submitProofTx.
+func (c *BlockchainClient) claimTaskTx(taskID string, claimer string, proof string, account SignerAccount) ([]byte, error) {
+    return c.recordTx(claimer, map[string]interface{}{
+        "type":    "claim_task",
+        "task_id": taskID,
+        "proof":   proof,
+    }, account)
+}
+
+// submitProofTx records the proof for an earlier claim on chain.
+func (c *BlockchainClient) submitProofTx(taskID string, claimer string, proof string, account SignerAccount) ([]byte, error) {
+    return c.recordTx(claimer, map[string]interface{}{
+        "type":    "submit_proof",
+        "task_id": taskID,
+        "proof":   proof,
+    }, account)
+}
+
+// recordTx puts a task memo on chain without moving funds. Bank has no
+// message without a transfer, so the signer sends the smallest unit to
+// themselves.
+func (c *BlockchainClient) recordTx(signer string, fields map[string]interface{}, account SignerAccount) ([]byte, error) {
+    msg, err := sendMsg(signer, signer, "1")
+    if err != nil {
+        return nil, err
+    }
+    memo, err := taskMemo(fields)
+    if err != nil {
+        return nil, err
+    }
+    return c.buildSignedTx(signer, []sdk.Msg{msg}, memo, account)
+}
+
+// approveTaskTx pays the bounty out of escrow to the claimer. It is signed
+// by the escrow account; the approving admin is recorded in the memo.
+func (c *BlockchainClient) approveTaskTx(task types.Task, approver string, account SignerAccount) ([]byte, error) {
+    msg, err := sendMsg(c.GetEscrowAddress(), task.Claimer, task.Bounty)
+    if err != nil {
+        return nil, err
+    }
+    memo, err := taskMemo(map[string]interface{}{
+        "type":     "approve_task",
+        "task_id":  task.ID,
+        "approver": approver,
+        "status":   types.TaskStatusCompleted,
+    })
+    if err != nil {
+        return nil, err
+    }
+    return c.buildSignedTx(c.GetEscrowAddress(), []sdk.Msg{msg}, memo, account)
+}
+
+// refundTaskTx returns the bounty from escrow to the task creator. The memo
+// records who closed the task and its final status.
+func (c *BlockchainClient) refundTaskTx(task types.Task, requester string, account SignerAccount) ([]byte, error) {
+    msg, err := sendMsg(c.GetEscrowAddress(), task.Creator, task.Bounty)
+    if err != nil {
+        return nil, err
+    }
+    memo, err := taskMemo(map[string]interface{}{
+        "type":      "refund_task",
+        "task_id":   task.ID,
+        "closed_by": requester,
+        "status":    task.Status,
+    })
+    if err != nil {
+        return nil, err
+    }
+    return c.buildSignedTx(c.GetEscrowAddress(), []sdk.Msg{msg}, memo, account)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+    "errors"
+    "net/http"
+    "github.com/gin-gonic/gin"
+    "bounty-system/internal/auth"
+    "bounty-system/internal/client"
+    "bounty-system/internal/store"
+    "bounty-system/internal/types"
+)
+
+// errorStatus maps an error returned by the client to the HTTP status it is
+// reported with. Only validation errors are the caller's fault; a failing
+// chain is a bad gateway and anything not recognised, such as a store
+// failure, an internal error.
+func errorStatus(err error) int {
+    var forbidden *types.ForbiddenError
+    var transition *types.InvalidTransitionError
+    var invalid *types.ValidationError
+    var txErr *client.TxError
+    var backendErr *client.BackendError
+    switch {
+    case errors.As(err, &forbidden):
+        return http.StatusForbidden
+    case errors.As(err, &transition), errors.Is(err, store.ErrConflict), errors.Is(err, store.ErrExists):
+        return http.StatusConflict
+    case errors.Is(err, client.ErrTaskNotFound), errors.Is(err, client.ErrProposalNotFound),
+        errors.Is(err, client.ErrEscrowNotFound):
+        return http.StatusNotFound
+    case errors.Is(err, auth.ErrNoCredentials), errors.Is(err, auth.ErrInvalidToken),
+        errors.Is(err, auth.ErrExpiredToken), errors.Is(err, auth.ErrRevokedToken):
+        return http.StatusUnauthorized
+    case errors.As(err, &invalid), errors.Is(err, store.ErrInvalidCursor):
+        return http.StatusBadRequest
+    case errors.Is(err, auth.ErrTooManyChallenges):
+        return http.StatusTooManyRequests
+    case errors.As(err, &txErr) && txErr.TimedOut:
+        return http.StatusGatewayTimeout
+    case errors.As(err, &txErr), errors.As(err, &backendErr):
+        return http.StatusBadGateway
+    default:
+        return http.StatusInternalServerError
+    }
+}
+
+// respondBadRequest aborts a request whose body or parameters could not be
+// read.
+func respondBadRequest(c *gin.Context, err error) {
+    abortWithError(c, http.StatusBadRequest, err.Error())
+}
+
+// respondError aborts the request with err as the JSON error body and the
+// status errorStatus picks for it.
+func respondError(c *gin.Context, err error) {
+    abortWithError(c, errorStatus(err), err.Error())
+}
+
+// abortWithError aborts the request with a {"error": message} body. Every
+// failed request uses this shape.
+func abortWithError(c *gin.Context, status int, message string) {
+    c.AbortWithStatusJSON(status, gin.H{"error": message})
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+    "net/http"
+    "github.com/gin-gonic/gin"
+    "bounty-system/internal/types"
+)
+
+// NewRouter mounts every API operation of h on a gin engine. Writes require
+// a session token or signed wallet challenge; admin routes additionally
+// check the caller's role for the action.
+func NewRouter(h *TaskHandler) *gin.Engine {
+    r := gin.New()
+    r.Use(gin.Logger(), gin.Recovery())
+    r.HandleMethodNotAllowed = true
+    r.NoRoute(func(c *gin.Context) {
+        abortWithError(c, http.StatusNotFound, "no route for "+c.Request.Method+" "+c.Request.URL.Path)
+    })
+    r.NoMethod(func(c *gin.Context) {
+        abortWithError(c, http.StatusMethodNotAllowed, "method "+c.Request.Method+" not allowed on "+c.Request.URL.Path)
+    })
+
+    signed := h.WalletAuth()
+
+    r.POST("/auth/challenge", h.Challenge)
+    r.POST("/auth/login", h.Login)
+    r.POST("/auth/refresh", h.Refresh)
+    r.POST("/auth/logout", signed, h.Logout)
+
+    r.GET("/addresses", h.ListAddresses)
+    r.POST("/generate-address", signed, h.GenerateAddress)
+    r.GET("/escrow", h.GetEscrow)
+
+    tasks := r.Group("/tasks")
+    tasks.GET("", h.ListTasks)
+    tasks.POST("", signed, h.CreateTask)
+    tasks.POST("/estimate", signed, h.EstimateTask)
+    tasks.GET("/status/:status", h.GetTasksByStatus)
+    tasks.GET("/:id", h.GetTask)
+    tasks.GET("/:id/escrow", h.GetTaskEscrow)
+    tasks.PUT("/:id/claim", signed, h.ClaimTask)
+    tasks.PUT("/:id/proof", signed, h.SubmitProof)
+    tasks.PUT("/:id/cancel", signed, h.CancelTask)
+
+    admin := r.Group("/admin", signed)
+    admin.PUT("/tasks/:id", h.Authorized(types.ActionApproveTask), h.ApproveTask)
+    admin.PUT("/tasks/:id/reject", h.Authorized(types.ActionRejectClaim), h.RejectClaim)
+    admin.POST("/admins", h.Authorized(types.ActionGovernAdmins), h.AddAdmin)
+    admin.DELETE("/admins/:address", h.Authorized(types.ActionGovernAdmins), h.RemoveAdmin)
+    admin.GET("/roles", h.Authorized(types.ActionViewRoles), h.ListRoles)
+    admin.PUT("/roles", h.Authorized(types.ActionManageRoles), h.AssignRole)
+    admin.GET("/proposals", h.Authorized(types.ActionViewProposals), h.ListProposals)
+    admin.POST("/proposals", h.Authorized(types.ActionGovernAdmins), h.CreateProposal)
+    admin.GET("/proposals/:id", h.Authorized(types.ActionViewProposals), h.GetProposal)
+    admin.POST("/proposals/:id/vote", h.Authorized(types.ActionGovernAdmins), h.VoteProposal)
+    admin.POST("/proposals/:id/execute", h.Authorized(types.ActionGovernAdmins), h.ExecuteProposal)
+
+    return r
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "sync"
+    "testing"
+    "github.com/gin-gonic/gin"
+    "bounty-system/internal/auth"
+    "bounty-system/internal/client"
+    "bounty-system/internal/store"
+    "bounty-system/internal/types"
+)
+
+type apiClient struct {
+    t        *testing.T
+    router   *gin.Engine
+    sessions *auth.SessionManager
+}
+
+// do sends a request as address (anonymous if empty) and decodes the JSON
+// response into out when given.
+func (a apiClient) do(method, path, address string, body interface{}, out interface{}) int {
+    a.t.Helper()
+    var payload bytes.Buffer
+    if body != nil {
+        json.NewEncoder(&payload).Encode(body)
+    }
+    req := httptest.NewRequest(method, path, &payload)
+    req.Header.Set("Content-Type", "application/json")
+    if address != "" {
+        session, err := a.sessions.Issue(address)
+        if err != nil {
+            a.t.Fatalf("Issue: %v", err)
+        }
+        req.Header.Set("Authorization", "Bearer "+session.AccessToken)
+    }
+
+    rec := httptest.NewRecorder()
+    a.router.ServeHTTP(rec, req)
+    if out != nil {
+        if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+            a.t.Fatalf("%s %s: invalid JSON body %q: %v", method, path, rec.Body.String(), err)
+        }
+    }
+    return rec.Code
+}
+
+func TestRouter(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    bc := client.NewBlockchainClient(client.WithAdmin(client.TestWalletAddress("admin-1")))
+    sessions := auth.NewSessionManager(auth.RandomSecret(), auth.DefaultSessionTTL, auth.DefaultRefreshTTL, bc.GetRole)
+    api := apiClient{t: t, router: NewRouter(NewTaskHandler(bc, auth.NewAuthenticator(auth.DefaultChallengeTTL), sessions)), sessions: sessions}
+
+    wallets := bc.GetTestWallets()
+    admin, creator, claimer := wallets[0], wallets[1], bc.GenerateTestAddress("claimer-1")
+
+    var apiErr struct {
+        Error string `json:"error"`
+    }
+    errorCases := []struct {
+        name    string
+        method  string
+        path    string
+        address string
+        body    interface{}
+        status  int
+    }{
+        {"unauthenticated write", "POST", "/tasks", "", types.Task{Title: "t", Bounty: "10"}, http.StatusUnauthorized},
+        {"unauthenticated address generation", "POST", "/generate-address", "", map[string]string{"seed": "s"}, http.StatusUnauthorized},
+        {"malformed creator", "POST", "/tasks", "serv1nope", types.Task{Title: "t", Bounty: "10"}, http.StatusBadRequest},
+        {"unknown route", "GET", "/nope", "", nil, http.StatusNotFound},
+        {"wrong method", "DELETE", "/tasks", "", nil, http.StatusMethodNotAllowed},
+        {"unknown status", "GET", "/tasks/status/bogus", "", nil, http.StatusBadRequest},
+        {"bad page size", "GET", "/tasks?limit=0", "", nil, http.StatusBadRequest},
+        {"bad cursor", "GET", "/tasks?cursor=nope", "", nil, http.StatusBadRequest},
+        {"approve by non-reviewer", "PUT", "/admin/tasks/task-1", creator, nil, http.StatusForbidden},
+        {"approve unknown task", "PUT", "/admin/tasks/task-1", admin, nil, http.StatusNotFound},
+        {"unknown task", "GET", "/tasks/task-1", "", nil, http.StatusNotFound},
+        {"unknown proposal", "GET", "/admin/proposals/prop-1", admin, nil, http.StatusNotFound},
+        {"remove last admin", "DELETE", "/admin/admins/" + admin, admin, nil, http.StatusBadRequest},
+        {"add malformed admin", "POST", "/admin/admins", admin, map[string]string{"address": "serv1nope"}, http.StatusBadRequest},
+    }
+    for _, tc := range errorCases {
+        apiErr.Error = ""
+        if status := api.do(tc.method, tc.path, tc.address, tc.body, &apiErr); status != tc.status {
+            t.Errorf("%s: expected status %d, got %d", tc.name, tc.status, status)
+        }
+        if apiErr.Error == "" {
+            t.Errorf("%s: expected a JSON error body", tc.name)
+        }
+    }
+
+    // Admins can be proposed whether or not the server holds their key
+    var proposal types.Proposal
+    outsider := client.TestWalletAddress("outside-1")
+    if status := api.do("POST", "/admin/admins", admin, map[string]string{"address": outsider}, &proposal); status != http.StatusAccepted || proposal.Target != outsider {
+        t.Errorf("expected 202 proposing an admin without a held key, got %d (%+v)", status, proposal)
+    }
+
+    // The mock backend simulates 80000 gas for every tx
+    var estimate client.FeeEstimate
+    if status := api.do("POST", "/tasks/estimate", creator, types.Task{Title: "Router task", Bounty: "1000"}, &estimate); status != http.StatusOK {
+        t.Fatalf("expected 200 estimating a task, got %d", status)
+    }
+    if estimate.GasUsed != 80000 || estimate.GasLimit != 104000 || estimate.Fee != "520" || estimate.Total != "1520" {
+        t.Errorf("unexpected estimate %+v", estimate)
+    }
+    var none store.TaskPage
+    if status := api.do("GET", "/tasks", "", nil, &none); status != http.StatusOK || len(none.Tasks) != 0 {
+        t.Errorf("estimating must not create a task, got %d tasks (status %d)", len(none.Tasks), status)
+    }
+
+    var task types.Task
+    if status := api.do("POST", "/tasks", creator, types.Task{Title: "Router task", Bounty: "1000"}, &task); status != http.StatusCreated {
+        t.Fatalf("expected 201 creating a task, got %d", status)
+    }
+    if task.Creator != creator || task.Status != types.TaskStatusOpen {
+        t.Fatalf("unexpected created task %+v", task)
+    }
+
+    // Tasks created within the same second must not overwrite each other
+    var second types.Task
+    if status := api.do("POST", "/tasks", creator, types.Task{Title: "Second task", Bounty: "1000"}, &second); status != http.StatusCreated {
+        t.Fatalf("expected 201 creating a second task, got %d", status)
+    }
+    if second.ID == task.ID || second.ID <= task.ID {
+        t.Fatalf("expected a distinct, later ID than %s, got %s", task.ID, second.ID)
+    }
+
+    claim := map[string]string{"proof": "https://github.com/proof"}
+    if status := api.do("PUT", "/tasks/"+task.ID+"/claim", claimer, claim, &task); status != http.StatusOK {
+        t.Fatalf("expected 200 claiming the task, got %d", status)
+    }
+
+    // A claimed task can no longer be cancelled
+    if status := api.do("PUT", "/tasks/"+task.ID+"/cancel", creator, nil, &apiErr); status != http.StatusConflict {
+        t.Errorf("expected 409 cancelling a claimed task, got %d", status)
+    }
+
+    var claimed store.TaskPage
+    if status := api.do("GET", "/tasks/status/claimed", "", nil, &claimed); status != http.StatusOK || len(claimed.Tasks) != 1 {
+        t.Errorf("expected one claimed task, got %d tasks (status %d)", len(claimed.Tasks), status)
+    }
+    var mine store.TaskPage
+    if status := api.do("GET", "/tasks?creator="+claimer+"&min_bounty=1", "", nil, &mine); status != http.StatusOK || len(mine.Tasks) != 0 {
+        t.Errorf("expected no tasks created by the claimer, got %d tasks (status %d)", len(mine.Tasks), status)
+    }
+
+    if status := api.do("PUT", "/admin/tasks/"+task.ID, admin, nil, &task); status != http.StatusOK {
+        t.Fatalf("expected 200 approving the task, got %d", status)
+    }
+    if task.Status != types.TaskStatusCompleted {
+        t.Errorf("expected COMPLETED after approval, got %s", task.Status)
+    }
+
+    var fetched types.Task
+    if status := api.do("GET", "/tasks/"+task.ID, "", nil, &fetched); status != http.StatusOK {
+        t.Fatalf("expected 200 fetching the task, got %d", status)
+    }
+    if n := len(fetched.History); n == 0 || fetched.History[n-1].Type != types.TaskEventPaid {
+        t.Errorf("expected the history to end with the payout, got %+v", fetched.History)
+    }
+}
+
+func TestConcurrentClaimsOverHTTP(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    bc := client.NewBlockchainClient(client.WithAdmin(client.TestWalletAddress("admin-1")))
+    sessions := auth.NewSessionManager(auth.RandomSecret(), auth.DefaultSessionTTL, auth.DefaultRefreshTTL, bc.GetRole)
+    api := apiClient{t: t, router: NewRouter(NewTaskHandler(bc, auth.NewAuthenticator(auth.DefaultChallengeTTL), sessions)), sessions: sessions}
+
+    var task types.Task
+    if status := api.do("POST", "/tasks", bc.GetTestWallets()[1], types.Task{Title: "Contested", Bounty: "1000"}, &task); status != http.StatusCreated {
+        t.Fatalf("expected 201 creating a task, got %d", status)
+    }
+
+    const claimers = 12
+    addresses := make([]string, claimers)
+    for i := range addresses {
+        addresses[i] = bc.GenerateTestAddress(fmt.Sprintf("claimer-%d", i))
+    }
+    statuses := make([]int, claimers)
+    start := make(chan struct{})
+    var wg sync.WaitGroup
+    for i := range addresses {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            <-start
+            statuses[i] = api.do("PUT", "/tasks/"+task.ID+"/claim", addresses[i], map[string]string{"proof": "https://github.com/proof"}, nil)
+        }(i)
+    }
+    close(start)
+    wg.Wait()
+
+    won := 0
+    for _, status := range statuses {
+        switch status {
+        case http.StatusOK:
+            won++
+        case http.StatusConflict:
+        default:
+            t.Errorf("unexpected claim status %d", status)
+        }
+    }
+    if won != 1 {
+        t.Fatalf("expected exactly one successful claim, got %d: %v", won, statuses)
+    }
+}
+
+func TestErrorStatus(t *testing.T) {
+    cases := []struct {
+        err  error
+        want int
+    }{
+        {types.Invalidf("proof is required"), http.StatusBadRequest},
+        {fmt.Errorf("failed to lock bounty: %w", types.Invalidf("invalid amount: x")), http.StatusBadRequest},
+        {store.ErrInvalidCursor, http.StatusBadRequest},
+        {auth.ErrTooManyChallenges, http.StatusTooManyRequests},
+        {&types.ForbiddenError{Address: "a", Role: types.ROLE_USER, Action: types.ActionApproveTask}, http.StatusForbidden},
+        {client.ErrTaskNotFound, http.StatusNotFound},
+        {fmt.Errorf("%w for task task-9", client.ErrEscrowNotFound), http.StatusNotFound},
+        {store.ErrConflict, http.StatusConflict},
+        {fmt.Errorf("failed to lock bounty: %w", &client.TxError{Hash: "AB", Code: 5}), http.StatusBadGateway},
+        {fmt.Errorf("failed to lock bounty: %w", &client.TxError{Hash: "AB", TimedOut: true}), http.StatusGatewayTimeout},
+        {fmt.Errorf("failed to lock bounty: %w", &client.BackendError{Err: fmt.Errorf("connection refused")}), http.StatusBadGateway},
+        {fmt.Errorf("failed to store task: %v", fmt.Errorf("disk full")), http.StatusInternalServerError},
+    }
+    for _, tc := range cases {
+        if got := errorStatus(tc.err); got != tc.want {
+            t.Errorf("errorStatus(%v) = %d, want %d", tc.err, got, tc.want)
+        }
+    }
+}
@@ -1,39 +1,80 @@
 package handlers
 
 import (
-    "fmt"
     "time"
     "log"
+    "math/big"
+    "net/http"
+    "strconv"
     "github.com/gin-gonic/gin"
     "bounty-system/internal/types"
+    "bounty-system/internal/auth"
     "bounty-system/internal/client"
+    "bounty-system/internal/ids"
+    "bounty-system/internal/store"
+    sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
 type TaskHandler struct {
     blockchainClient *client.BlockchainClient
-    tasks           map[string]types.Task
+    auth             *auth.Authenticator
+    sessions         *auth.SessionManager
 }
 
-func NewTaskHandler() *TaskHandler {
+// NewTaskHandler returns a handler backed by bc, so tasks are shared with
+// every other user of the same client and its store. Callers prove their
+// wallet with challenges issued by authenticator or with a session token
+// from sessions.
+func NewTaskHandler(bc *client.BlockchainClient, authenticator *auth.Authenticator, sessions *auth.SessionManager) *TaskHandler {
     return &TaskHandler{
-        blockchainClient: client.NewBlockchainClient(),
-        tasks:           make(map[string]types.Task),
+        blockchainClient: bc,
+        auth:             authenticator,
+        sessions:         sessions,
     }
 }
 
-// Admin middleware
-func (h *TaskHandler) AdminRequired() gin.HandlerFunc {
+// callerKey is the gin context key holding the verified wallet address.
+const callerKey = "wallet_address"
+
+// WalletAuth middleware verifies the session token or signed challenge of
+// the request and records the proven address for the handlers.
+func (h *TaskHandler) WalletAuth() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        address, err := auth.Identify(c.Request, h.sessions, h.auth)
+        if err != nil {
+            abortWithError(c, http.StatusUnauthorized, err.Error())
+            return
+        }
+
+        c.Set(callerKey, address)
+        c.Next()
+    }
+}
+
+// isAddress reports whether address is a bech32 account address. The
+// server need not hold its key.
+func isAddress(address string) bool {
+    _, err := sdk.AccAddressFromBech32(address)
+    return err == nil
+}
+
+// caller returns the address verified by WalletAuth.
+func caller(c *gin.Context) string {
+    return c.GetString(callerKey)
+}
+
+// Authorized middleware lets the request through only if the caller's role
+// may perform action. It must run after WalletAuth.
+func (h *TaskHandler) Authorized(action types.Action) gin.HandlerFunc {
     return func(c *gin.Context) {
-        address := c.GetHeader("X-Wallet-Address")
+        address := caller(c)
         if address == "" {
-            c.JSON(401, gin.H{"error": "wallet address required"})
-            c.Abort()
+            abortWithError(c, http.StatusUnauthorized, "wallet address required")
             return
         }
 
-        if !h.blockchainClient.adminManager.IsAdmin(address) {
-            c.JSON(403, gin.H{"error": "admin access required"})
-            c.Abort()
+        if err := h.blockchainClient.Authorize(address, action); err != nil {
+            respondError(c, err)
             return
         }
 
@@ -41,123 +82,430 @@ func (h *TaskHandler) AdminRequired() gin.HandlerFunc {
     }
 }
 
+func (h *TaskHandler) Challenge(c *gin.Context) {
+    var req struct {
+        Address string `json:"address"`
+    }
+    if err := c.ShouldBindJSON(&req); err != nil {
+        respondBadRequest(c, err)
+        return
+    }
+    
+    challenge, err := h.auth.Challenge(req.Address)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+    c.JSON(200, challenge)
+}
+
+func (h *TaskHandler) Login(c *gin.Context) {
+    var signed auth.SignedChallenge
+    if err := c.ShouldBindJSON(&signed); err != nil {
+        respondBadRequest(c, err)
+        return
+    }
+    
+    session, err := h.sessions.Login(h.auth, signed)
+    if err != nil {
+        abortWithError(c, http.StatusUnauthorized, err.Error())
+        return
+    }
+    c.JSON(200, session)
+}
+
+func (h *TaskHandler) Refresh(c *gin.Context) {
+    var req struct {
+        RefreshToken string `json:"refresh_token"`
+    }
+    if err := c.ShouldBindJSON(&req); err != nil {
+        respondBadRequest(c, err)
+        return
+    }
+    
+    session, err := h.sessions.Refresh(req.RefreshToken)
+    if err != nil {
+        abortWithError(c, http.StatusUnauthorized, err.Error())
+        return
+    }
+    c.JSON(200, session)
+}
+
+// Logout revokes the bearer token and, if given, the refresh token.
+func (h *TaskHandler) Logout(c *gin.Context) {
+    var req struct {
+        RefreshToken string `json:"refresh_token"`
+    }
+    if c.Request.ContentLength != 0 {
+        if err := c.ShouldBindJSON(&req); err != nil {
+            respondBadRequest(c, err)
+            return
+        }
+    }
+    
+    for _, token := range []string{auth.BearerToken(c.Request), req.RefreshToken} {
+        if token == "" {
+            continue
+        }
+        if err := h.sessions.Revoke(token); err != nil {
+            respondError(c, err)
+            return
+        }
+    }
+    c.JSON(200, gin.H{"message": "logged out"})
+}
+
+func (h *TaskHandler) ListAddresses(c *gin.Context) {
+    c.JSON(200, gin.H{
+        "admin_address": h.blockchainClient.GetAdminAddress(),
+        "all_addresses": h.blockchainClient.ListAddresses(),
+    })
+}
+
+func (h *TaskHandler) GenerateAddress(c *gin.Context) {
+    var req struct {
+        Seed string `json:"seed"`
+    }
+    if err := c.ShouldBindJSON(&req); err != nil {
+        respondBadRequest(c, err)
+        return
+    }
+    
+    address := h.blockchainClient.GenerateTestAddress(req.Seed)
+    if address == "" {
+        abortWithError(c, http.StatusInternalServerError, "failed to generate address")
+        return
+    }
+    c.JSON(201, gin.H{"address": address})
+}
+
+// ListTasks returns one page of tasks selected by the query parameters
+// creator, claimer, status, min_bounty, max_bounty, created_after and
+// created_before, ordered by sort and order, and paged by limit and cursor.
 func (h *TaskHandler) ListTasks(c *gin.Context) {
-    tasks := make([]types.Task, 0, len(h.tasks))
-    for _, task := range h.tasks {
-        tasks = append(tasks, task)
+    q, err := parseTaskQuery(c)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+    h.queryTasks(c, q)
+}
+
+func (h *TaskHandler) queryTasks(c *gin.Context, q store.TaskQuery) {
+    page, err := h.blockchainClient.QueryTasks(q)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+    c.JSON(200, page)
+}
+
+// parseTaskQuery reads the task listing query parameters. Tasks are listed
+// newest first unless order=asc is given.
+func parseTaskQuery(c *gin.Context) (store.TaskQuery, error) {
+    q := store.TaskQuery{
+        Creator:    c.Query("creator"),
+        Claimer:    c.Query("claimer"),
+        Sort:       store.TaskSort(c.Query("sort")),
+        Descending: true,
+        Cursor:     c.Query("cursor"),
+    }
+    
+    if status := c.Query("status"); status != "" {
+        parsed, err := types.ParseTaskStatus(status)
+        if err != nil {
+            return q, err
+        }
+        q.Status = parsed
     }
-    c.JSON(200, tasks)
+    switch c.Query("order") {
+    case "", "desc":
+    case "asc":
+        q.Descending = false
+    default:
+        return q, types.Invalidf("order must be asc or desc")
+    }
+    if limit := c.Query("limit"); limit != "" {
+        n, err := strconv.Atoi(limit)
+        if err != nil || n < 1 {
+            return q, types.Invalidf("limit must be a positive integer")
+        }
+        q.Limit = n
+    }
+    
+    for name, bound := range map[string]**big.Int{"min_bounty": &q.MinBounty, "max_bounty": &q.MaxBounty} {
+        if value := c.Query(name); value != "" {
+            amount, ok := new(big.Int).SetString(value, 10)
+            if !ok {
+                return q, types.Invalidf("invalid %s %q", name, value)
+            }
+            *bound = amount
+        }
+    }
+    for name, bound := range map[string]*time.Time{"created_after": &q.CreatedAfter, "created_before": &q.CreatedBefore} {
+        if value := c.Query(name); value != "" {
+            at, err := time.Parse(time.RFC3339, value)
+            if err != nil {
+                return q, types.Invalidf("invalid %s: %v", name, err)
+            }
+            *bound = at
+        }
+    }
+    return q, nil
 }
 
 func (h *TaskHandler) CreateTask(c *gin.Context) {
     var task types.Task
     if err := c.ShouldBindJSON(&task); err != nil {
-        c.JSON(400, gin.H{"error": err.Error()})
+        respondBadRequest(c, err)
         return
     }
     
+    if task.Creator == "" {
+        task.Creator = caller(c)
+    }
+    if task.Creator != caller(c) {
+        abortWithError(c, http.StatusForbidden, "tasks can only be created from the signing wallet")
+        return
+    }
     log.Printf("Attempting to create task with creator: %s", task.Creator)
     
-    // Validate address
-    if !h.blockchainClient.ValidateAddress(task.Creator) {
-        wallets := h.blockchainClient.GetTestWallets()
-        c.JSON(400, gin.H{
-            "error": "invalid creator address format",
-            "message": "Address must start with 'serv1' and be 39-44 characters long",
-            "valid_examples": wallets,
-            "received_address": task.Creator,
-            "received_length": len(task.Creator),
-        })
+    if !isAddress(task.Creator) {
+        abortWithError(c, http.StatusBadRequest, "invalid creator address "+task.Creator)
         return
     }
     
     // Generate task ID
-    task.ID = fmt.Sprintf("task-%d", time.Now().Unix())
-    task.Status = "OPEN"
+    task.ID = ids.New("task")
+    task.Status = types.TaskStatusOpen
     
-    // Store task
-    h.tasks[task.ID] = task
+    if err := h.blockchainClient.CreateTask(task); err != nil {
+        respondError(c, err)
+        return
+    }
     
-    c.JSON(201, task)
+    h.respondTask(c, 201, task.ID)
+}
+
+// EstimateTask takes the same body as CreateTask and reports what creating
+// the task would cost the signing wallet, without creating it.
+func (h *TaskHandler) EstimateTask(c *gin.Context) {
+    var task types.Task
+    if err := c.ShouldBindJSON(&task); err != nil {
+        respondBadRequest(c, err)
+        return
+    }
+    if task.Creator == "" {
+        task.Creator = caller(c)
+    }
+    if task.Creator != caller(c) {
+        abortWithError(c, http.StatusForbidden, "tasks can only be estimated for the signing wallet")
+        return
+    }
+    if !isAddress(task.Creator) {
+        abortWithError(c, http.StatusBadRequest, "invalid creator address "+task.Creator)
+        return
+    }
+
+    task.ID = ids.New("task")
+    task.Status = types.TaskStatusOpen
+    estimate, err := h.blockchainClient.EstimateCreateTask(task)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+    c.JSON(200, estimate)
 }
 
 func (h *TaskHandler) ClaimTask(c *gin.Context) {
     taskID := c.Param("id")
     
-    task, exists := h.tasks[taskID]
-    if !exists {
-        c.JSON(404, gin.H{"error": "task not found"})
+    var claim struct {
+        Claimer string `json:"claimer"`
+        Proof   string `json:"proof"`
+    }
+    
+    if err := c.ShouldBindJSON(&claim); err != nil {
+        respondBadRequest(c, err)
+        return
+    }
+    if claim.Claimer == "" {
+        claim.Claimer = caller(c)
+    }
+    if claim.Claimer != caller(c) {
+        abortWithError(c, http.StatusForbidden, "tasks can only be claimed by the signing wallet")
         return
     }
     
-    if task.Status != "OPEN" {
-        c.JSON(400, gin.H{"error": "task is not open for claiming"})
+    if !isAddress(claim.Claimer) {
+        abortWithError(c, http.StatusBadRequest, "invalid claimer address "+claim.Claimer)
         return
     }
     
-    var claim struct {
+    if err := h.blockchainClient.ClaimTask(taskID, claim.Claimer, claim.Proof); err != nil {
+        respondError(c, err)
+        return
+    }
+    
+    h.respondTask(c, 200, taskID)
+}
+
+func (h *TaskHandler) SubmitProof(c *gin.Context) {
+    taskID := c.Param("id")
+    
+    var submission struct {
         Claimer string `json:"claimer"`
         Proof   string `json:"proof"`
     }
     
-    if err := c.ShouldBindJSON(&claim); err != nil {
-        c.JSON(400, gin.H{"error": err.Error()})
+    if err := c.ShouldBindJSON(&submission); err != nil {
+        respondBadRequest(c, err)
+        return
+    }
+    if submission.Claimer == "" {
+        submission.Claimer = caller(c)
+    }
+    if submission.Claimer != caller(c) {
+        abortWithError(c, http.StatusForbidden, "proofs can only be submitted by the signing wallet")
         return
     }
     
-    // Validate claimer address
-    if !h.blockchainClient.ValidateAddress(claim.Claimer) {
-        c.JSON(400, gin.H{
-            "error": "invalid claimer address format",
-            "message": "Address must start with 'serv1' and be 39-44 characters long",
-        })
+    if err := h.blockchainClient.SubmitProof(taskID, submission.Claimer, submission.Proof); err != nil {
+        respondError(c, err)
         return
     }
     
-    task.Status = "CLAIMED"
-    task.Claimer = claim.Claimer
-    task.Proof = claim.Proof
-    h.tasks[taskID] = task
+    h.respondTask(c, 200, taskID)
+}
+
+func (h *TaskHandler) CancelTask(c *gin.Context) {
+    taskID := c.Param("id")
+    
+    if err := h.blockchainClient.CancelTask(taskID, caller(c)); err != nil {
+        respondError(c, err)
+        return
+    }
     
-    c.JSON(200, task)
+    h.respondTask(c, 200, taskID)
 }
 
 func (h *TaskHandler) ApproveTask(c *gin.Context) {
-    // Admin check is done by middleware
+    // Role check is done by middleware
     taskID := c.Param("id")
     
-    task, exists := h.tasks[taskID]
-    if !exists {
-        c.JSON(404, gin.H{"error": "task not found"})
+    task, err := h.blockchainClient.GetTask(taskID)
+    if err != nil {
+        respondError(c, err)
         return
     }
     
-    if task.Status != "CLAIMED" {
-        c.JSON(400, gin.H{"error": "task must be claimed before approval"})
+    if err := h.blockchainClient.ApproveTask(task, caller(c)); err != nil {
+        respondError(c, err)
         return
     }
     
-    task.Status = "COMPLETED"
-    h.tasks[taskID] = task
+    h.respondTask(c, 200, taskID)
+}
+
+func (h *TaskHandler) RejectClaim(c *gin.Context) {
+    // Role check is done by middleware
+    taskID := c.Param("id")
+    
+    var req struct {
+        Reason string `json:"reason"`
+    }
+    if err := c.ShouldBindJSON(&req); err != nil {
+        respondBadRequest(c, err)
+        return
+    }
     
-    c.JSON(200, task)
+    if err := h.blockchainClient.RejectClaim(taskID, caller(c), req.Reason); err != nil {
+        respondError(c, err)
+        return
+    }
+    
+    h.respondTask(c, 200, taskID)
+}
+
+// GetTask returns one task with its full history.
+func (h *TaskHandler) GetTask(c *gin.Context) {
+    h.respondTask(c, 200, c.Param("id"))
+}
+
+// respondTask writes the current state of a task that was just read or
+// changed.
+func (h *TaskHandler) respondTask(c *gin.Context, status int, taskID string) {
+    task, err := h.blockchainClient.GetTask(taskID)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+    c.JSON(status, task)
 }
 
+// GetTasksByStatus is ListTasks with the status taken from the path.
 func (h *TaskHandler) GetTasksByStatus(c *gin.Context) {
-    status := c.Query("status")
-    if status == "" {
-        c.JSON(400, gin.H{"error": "status parameter is required"})
+    q, err := parseTaskQuery(c)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+    if q.Status, err = types.ParseTaskStatus(c.Param("status")); err != nil {
+        respondError(c, err)
+        return
+    }
+    h.queryTasks(c, q)
+}
+
+func (h *TaskHandler) GetEscrow(c *gin.Context) {
+    total, err := h.blockchainClient.GetEscrowTotal()
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+    escrows, err := h.blockchainClient.ListEscrows()
+    if err != nil {
+        respondError(c, err)
         return
     }
     
-    var filteredTasks []types.Task
-    for _, task := range h.tasks {
-        if task.Status == status {
-            filteredTasks = append(filteredTasks, task)
-        }
+    c.JSON(200, gin.H{
+        "escrow_address": h.blockchainClient.GetEscrowAddress(),
+        "total_locked": total,
+        "escrows": escrows,
+    })
+}
+
+func (h *TaskHandler) GetTaskEscrow(c *gin.Context) {
+    escrow, err := h.blockchainClient.GetTaskEscrow(c.Param("id"))
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+    c.JSON(200, escrow)
+}
+
+func (h *TaskHandler) ListRoles(c *gin.Context) {
+    users, err := h.blockchainClient.ListUsers(caller(c))
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+    c.JSON(200, gin.H{"roles": types.Roles(), "users": users})
+}
+
+func (h *TaskHandler) AssignRole(c *gin.Context) {
+    var req types.User
+    if err := c.ShouldBindJSON(&req); err != nil {
+        respondBadRequest(c, err)
+        return
     }
     
-    c.JSON(200, filteredTasks)
+    if err := h.blockchainClient.AssignRole(req.Address, req.Role, caller(c)); err != nil {
+        respondError(c, err)
+        return
+    }
+    c.JSON(200, req)
 }
 
 // Admin only endpoints
@@ -167,29 +515,91 @@ func (h *TaskHandler) AddAdmin(c *gin.Context) {
     }
     
     if err := c.ShouldBindJSON(&req); err != nil {
-        c.JSON(400, gin.H{"error": err.Error()})
+        respondBadRequest(c, err)
         return
     }
     
-    if !h.blockchainClient.ValidateAddress(req.Address) {
-        c.JSON(400, gin.H{"error": "invalid address format"})
+    if !isAddress(req.Address) {
+        abortWithError(c, http.StatusBadRequest, "invalid address "+req.Address)
         return
     }
     
-    h.blockchainClient.adminManager.AddAdmin(req.Address)
-    c.JSON(200, gin.H{"message": "admin added successfully"})
+    proposal, err := h.blockchainClient.AddAdmin(req.Address, caller(c))
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+    c.JSON(202, proposal)
 }
 
+// RemoveAdmin proposes removing the admin named by the address path parameter.
 func (h *TaskHandler) RemoveAdmin(c *gin.Context) {
+    proposal, err := h.blockchainClient.RemoveAdmin(c.Param("address"), caller(c))
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+    c.JSON(202, proposal)
+}
+
+func (h *TaskHandler) ListProposals(c *gin.Context) {
+    proposals, err := h.blockchainClient.ListProposals(caller(c))
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+    c.JSON(200, proposals)
+}
+
+func (h *TaskHandler) GetProposal(c *gin.Context) {
+    proposal, err := h.blockchainClient.GetProposal(c.Param("id"))
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+    c.JSON(200, proposal)
+}
+
+func (h *TaskHandler) CreateProposal(c *gin.Context) {
     var req struct {
-        Address string `json:"address"`
+        Kind   types.ProposalKind `json:"kind"`
+        Target string             `json:"target"`
+    }
+    if err := c.ShouldBindJSON(&req); err != nil {
+        respondBadRequest(c, err)
+        return
     }
     
+    proposal, err := h.blockchainClient.ProposeAdminChange(req.Kind, req.Target, caller(c))
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+    c.JSON(201, proposal)
+}
+
+func (h *TaskHandler) VoteProposal(c *gin.Context) {
+    var req struct {
+        Approve bool `json:"approve"`
+    }
     if err := c.ShouldBindJSON(&req); err != nil {
-        c.JSON(400, gin.H{"error": err.Error()})
+        respondBadRequest(c, err)
         return
     }
     
-    h.blockchainClient.adminManager.RemoveAdmin(req.Address)
-    c.JSON(200, gin.H{"message": "admin removed successfully"})
+    proposal, err := h.blockchainClient.VoteProposal(c.Param("id"), caller(c), req.Approve)
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+    c.JSON(200, proposal)
+}
+
+func (h *TaskHandler) ExecuteProposal(c *gin.Context) {
+    proposal, err := h.blockchainClient.ExecuteProposal(c.Param("id"), caller(c))
+    if err != nil {
+        respondError(c, err)
+        return
+    }
+    c.JSON(200, proposal)
 }
@@ -0,0 +1,132 @@
+// Package ids generates ULIDs: 128-bit identifiers made of a millisecond
+// timestamp and 80 random bits, written as 26 Crockford base32 characters so
+// that they sort by creation time.
+package ids
+
+import (
+    "crypto/rand"
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "io"
+    "strings"
+    "sync"
+    "time"
+)
+
+// Length is the length of an encoded ULID.
+const Length = 26
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ErrExhausted is returned when more IDs were requested within one
+// millisecond than the random part can count.
+var ErrExhausted = errors.New("ulid entropy exhausted for this millisecond")
+
+// Generator produces strictly increasing ULIDs. Within one millisecond the
+// random part of the previous ID is incremented, so IDs from one generator
+// never collide and keep their order; IDs from other generators or earlier
+// runs differ by their random bits. A Generator is safe for concurrent use.
+type Generator struct {
+    mu      sync.Mutex
+    now     func() time.Time
+    entropy io.Reader
+    lastMs  uint64
+    last    [10]byte
+}
+
+// NewGenerator returns a generator using the wall clock and crypto/rand.
+func NewGenerator() *Generator {
+    return &Generator{now: time.Now, entropy: rand.Reader}
+}
+
+// New returns the next ULID.
+func (g *Generator) New() (string, error) {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+
+    ms := uint64(g.now().UnixNano() / int64(time.Millisecond))
+    if ms <= g.lastMs {
+        // Same millisecond, or the clock went back: keep counting from the
+        // previous ID so order is preserved
+        if !increment(&g.last) {
+            return "", ErrExhausted
+        }
+        ms = g.lastMs
+    } else {
+        if _, err := io.ReadFull(g.entropy, g.last[:]); err != nil {
+            return "", fmt.Errorf("failed to read entropy: %v", err)
+        }
+        g.lastMs = ms
+    }
+
+    var id [16]byte
+    binary.BigEndian.PutUint16(id[0:2], uint16(ms>>32))
+    binary.BigEndian.PutUint32(id[2:6], uint32(ms))
+    copy(id[6:], g.last[:])
+    return encode(id), nil
+}
+
+// increment adds one to the big-endian random part, reporting false on
+// overflow.
+func increment(b *[10]byte) bool {
+    for i := len(b) - 1; i >= 0; i-- {
+        b[i]++
+        if b[i] != 0 {
+            return true
+        }
+    }
+    return false
+}
+
+func encode(id [16]byte) string {
+    hi := binary.BigEndian.Uint64(id[:8])
+    lo := binary.BigEndian.Uint64(id[8:])
+    var out [Length]byte
+    for i := Length - 1; i >= 0; i-- {
+        out[i] = crockford[lo&31]
+        lo = lo>>5 | hi<<59
+        hi >>= 5
+    }
+    return string(out[:])
+}
+
+// Time returns the creation time encoded in a ULID.
+func Time(id string) (time.Time, error) {
+    if len(id) != Length {
+        return time.Time{}, fmt.Errorf("invalid ulid %q: expected %d characters", id, Length)
+    }
+    // The first ten characters hold two zero bits and the 48-bit timestamp
+    var ms uint64
+    for _, ch := range strings.ToUpper(id[:10]) {
+        v := strings.IndexRune(crockford, ch)
+        if v < 0 {
+            return time.Time{}, fmt.Errorf("invalid ulid %q: bad character %q", id, ch)
+        }
+        ms = ms<<5 | uint64(v)
+    }
+    if ms>>48 != 0 {
+        return time.Time{}, fmt.Errorf("invalid ulid %q: timestamp overflows", id)
+    }
+    return time.Unix(0, int64(ms)*int64(time.Millisecond)).UTC(), nil
+}
+
+var defaultGenerator = NewGenerator()
+
+// New returns a ULID from the process-wide generator, with prefix and a dash
+// in front when prefix is not empty, e.g. "task-01J0...". It panics only if
+// the system's secure random source fails.
+func New(prefix string) string {
+    id, err := defaultGenerator.New()
+    for err == ErrExhausted {
+        time.Sleep(time.Millisecond)
+        id, err = defaultGenerator.New()
+    }
+    if err != nil {
+        panic(err)
+    }
+    if prefix == "" {
+        return id
+    }
+    return prefix + "-" + id
+}
@@ -0,0 +1,93 @@
+package ids
+
+import (
+    "bytes"
+    "sort"
+    "strings"
+    "sync"
+    "testing"
+    "time"
+)
+
+func TestGeneratorOrderAndTime(t *testing.T) {
+    now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+    g := &Generator{now: func() time.Time { return now }, entropy: bytes.NewReader(bytes.Repeat([]byte{0x01}, 20))}
+
+    var generated []string
+    for i := 0; i < 3; i++ {
+        id, err := g.New()
+        if err != nil {
+            t.Fatalf("New failed: %v", err)
+        }
+        generated = append(generated, id)
+        if len(id) != Length {
+            t.Fatalf("expected %d characters, got %q", Length, id)
+        }
+        if at, err := Time(id); err != nil || !at.Equal(now) {
+            t.Fatalf("expected time %s in %s, got %s (%v)", now, id, at, err)
+        }
+    }
+    if !sort.StringsAreSorted(generated) || generated[0] == generated[1] {
+        t.Fatalf("IDs within one millisecond are not increasing: %v", generated)
+    }
+
+    // Once the random part is all ones the millisecond has no IDs left
+    g.last = [10]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+    if _, err := g.New(); err != ErrExhausted {
+        t.Fatalf("expected ErrExhausted, got %v", err)
+    }
+
+    // A later millisecond sorts after everything before it
+    now = now.Add(time.Millisecond)
+    later, err := g.New()
+    if err != nil || later <= generated[len(generated)-1] {
+        t.Fatalf("expected %s to sort after %v (%v)", later, generated, err)
+    }
+
+    // A clock going backwards must not break the order
+    now = now.Add(-time.Hour)
+    earlier, err := g.New()
+    if err != nil || earlier <= later {
+        t.Fatalf("expected %s to sort after %s when the clock went back (%v)", earlier, later, err)
+    }
+}
+
+func TestNewIsUniqueAcrossGoroutines(t *testing.T) {
+    const workers, perWorker = 8, 500
+    var mu sync.Mutex
+    seen := make(map[string]bool, workers*perWorker)
+    var wg sync.WaitGroup
+    for w := 0; w < workers; w++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for i := 0; i < perWorker; i++ {
+                id := New("task")
+                mu.Lock()
+                seen[id] = true
+                mu.Unlock()
+            }
+        }()
+    }
+    wg.Wait()
+
+    if len(seen) != workers*perWorker {
+        t.Fatalf("expected %d unique IDs, got %d", workers*perWorker, len(seen))
+    }
+    for id := range seen {
+        if !strings.HasPrefix(id, "task-") {
+            t.Fatalf("missing prefix in %q", id)
+        }
+        if _, err := Time(strings.TrimPrefix(id, "task-")); err != nil {
+            t.Fatalf("generated ID does not parse: %v", err)
+        }
+    }
+}
+
+func TestTimeRejectsInvalidIDs(t *testing.T) {
+    for _, id := range []string{"", "short", "01HZ!0000000000000000000000"[:26], "ZZZZZZZZZZZZZZZZZZZZZZZZZZ"} {
+        if _, err := Time(id); err == nil {
+            t.Errorf("expected an error for %q", id)
+        }
+    }
+}
@@ -0,0 +1,89 @@
+package indexer
+
+import (
+    "context"
+    "fmt"
+    "log"
+)
+
+// backfillLogEvery is how many blocks pass between progress messages.
+const backfillLogEvery = 1000
+
+// ReorgError reports that the chain no longer matches what was indexed:
+// block Height now has hash Got instead of Want.
+type ReorgError struct {
+    Height int64
+    Want   string
+    Got    string
+}
+
+func (e *ReorgError) Error() string {
+    return fmt.Sprintf("block %d is %s on the chain but was indexed as %s: the chain was reorganised or reset", e.Height, e.Got, e.Want)
+}
+
+// Backfiller indexes past blocks one height at a time. Unlike the
+// subscriber's catch-up it does not need the node to index transactions,
+// and it checks that the blocks it reads form one chain. Transactions go
+// through the indexer's memo checks, so a rebuilt escrow ledger only holds
+// transfers that went through the escrow account.
+type Backfiller struct {
+    source  BlockSource
+    indexer *Indexer
+    start   int64
+}
+
+// NewBackfiller reads blocks from height start on, or from the indexer's
+// checkpoint if that is later.
+func NewBackfiller(source BlockSource, indexer *Indexer, start int64) *Backfiller {
+    if start < 1 {
+        start = 1
+    }
+    return &Backfiller{source: source, indexer: indexer, start: start}
+}
+
+// Run indexes blocks up to and including end, or up to the latest block
+// when end is 0, and returns the last height indexed. The checkpointed
+// block is read again, since it may have been left half done, and must
+// still have the hash it was indexed with. Every later block must follow
+// the one before it. Otherwise Run stops with a *ReorgError before applying
+// anything from the other chain.
+func (b *Backfiller) Run(ctx context.Context, end int64) (int64, error) {
+    checkpoint, err := b.indexer.Checkpoint()
+    if err != nil {
+        return 0, err
+    }
+    from := b.start
+    if checkpoint.Height >= from {
+        from = checkpoint.Height
+    }
+    if end == 0 {
+        if end, err = b.source.LatestHeight(ctx); err != nil {
+            return 0, err
+        }
+    }
+
+    last, parent := from-1, ""
+    for height := from; height <= end; height++ {
+        if err := ctx.Err(); err != nil {
+            return last, err
+        }
+        block, err := b.source.Block(ctx, height)
+        if err != nil {
+            return last, err
+        }
+        if height == checkpoint.Height && checkpoint.BlockHash != "" && block.Hash != checkpoint.BlockHash {
+            return last, &ReorgError{Height: height, Want: checkpoint.BlockHash, Got: block.Hash}
+        }
+        if parent != "" && block.ParentHash != parent {
+            return last, &ReorgError{Height: height - 1, Want: parent, Got: block.ParentHash}
+        }
+        if err := b.indexer.ApplyBlock(block); err != nil {
+            return last, fmt.Errorf("failed to index block %d: %v", height, err)
+        }
+        last, parent = height, block.Hash
+        if height%backfillLogEvery == 0 {
+            log.Printf("Backfilled up to block %d of %d", height, end)
+        }
+    }
+    return last, nil
+}
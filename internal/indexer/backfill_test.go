@@ -0,0 +1,154 @@
+package indexer
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "testing"
+    "time"
+
+    "bounty-system/internal/store"
+    "bounty-system/internal/types"
+    authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+// fakeChain serves blocks 1..len(blocks), each pointing at its parent.
+type fakeChain struct {
+    blocks []Block
+}
+
+// add appends a block holding txs and returns its height.
+func (f *fakeChain) add(fork string, txs ...Tx) int64 {
+    height := int64(len(f.blocks)) + 1
+    block := Block{Height: height, Hash: fmt.Sprintf("%s%d", fork, height), Time: time.Date(2024, 5, 1, 12, 0, int(height), 0, time.UTC)}
+    if height > 1 {
+        block.ParentHash = f.blocks[height-2].Hash
+    }
+    for i, tx := range txs {
+        tx.Height, tx.Index, tx.BlockHash, tx.Time = height, uint32(i), block.Hash, block.Time
+        block.Txs = append(block.Txs, tx)
+    }
+    f.blocks = append(f.blocks, block)
+    return height
+}
+
+// fork replaces every block from height on with empty blocks of another chain.
+func (f *fakeChain) fork(height int64) {
+    n := len(f.blocks)
+    f.blocks = f.blocks[:height-1]
+    for len(f.blocks) < n {
+        f.add("B")
+    }
+}
+
+func (f *fakeChain) LatestHeight(ctx context.Context) (int64, error) {
+    return int64(len(f.blocks)), nil
+}
+
+func (f *fakeChain) Block(ctx context.Context, height int64) (Block, error) {
+    if height < 1 || height > int64(len(f.blocks)) {
+        return Block{}, fmt.Errorf("no block %d", height)
+    }
+    return f.blocks[height-1], nil
+}
+
+func TestBackfill(t *testing.T) {
+    st := store.NewMemoryStore()
+    chain := &fakeChain{}
+    chain.add("A")
+    chain.add("A", memoTx(t, 0, creator, escrow, 1000, map[string]interface{}{"type": "create_task", "task_id": "task-1", "title": "Fix"}))
+    chain.add("A")
+    chain.add("A",
+        memoTx(t, 0, claimer, claimer, 1, map[string]interface{}{"type": "claim_task", "task_id": "task-1", "proof": "p"}),
+        memoTx(t, 0, creator, escrow, 500, map[string]interface{}{"type": "create_task", "task_id": "task-2", "title": "Docs"}),
+    )
+    chain.add("A", memoTx(t, 0, escrow, claimer, 1000, map[string]interface{}{"type": "approve_task", "task_id": "task-1", "approver": approver.String()}))
+
+    ix := NewIndexer("backfill", st, encoding.TxConfig.TxDecoder(), escrow.String())
+    last, err := NewBackfiller(chain, ix, 1).Run(context.Background(), 3)
+    if err != nil || last != 3 {
+        t.Fatalf("expected to stop at block 3, got %d, %v", last, err)
+    }
+    if checkpoint, _ := ix.Checkpoint(); checkpoint.Height != 3 || checkpoint.BlockHash != "A3" {
+        t.Fatalf("expected checkpoint at block A3, got %+v", checkpoint)
+    }
+    if escrowed, _ := st.GetEscrow("task-1"); escrowed.Status != types.ESCROW_LOCKED || escrowed.Amount != "1000" {
+        t.Fatalf("expected 1000 locked for task-1, got %+v", escrowed)
+    }
+
+    // Resumes from the checkpoint up to the latest block
+    if last, err := NewBackfiller(chain, ix, 1).Run(context.Background(), 0); err != nil || last != 5 {
+        t.Fatalf("expected to stop at block 5, got %d, %v", last, err)
+    }
+    task, _ := st.Get("task-1")
+    if task.Status != types.TaskStatusCompleted || task.Claimer != claimer.String() || len(task.History) != 3 {
+        t.Fatalf("unexpected task-1 %+v", task)
+    }
+    paid, _ := st.GetEscrow("task-1")
+    if paid.Status != types.ESCROW_RELEASED || paid.Recipient != claimer.String() || paid.ReleaseTxHash != chain.blocks[4].Txs[0].Hash {
+        t.Fatalf("expected task-1 escrow released to the claimer, got %+v", paid)
+    }
+
+    // Rerunning the whole range changes nothing
+    if err := ix.Reset(); err != nil {
+        t.Fatalf("reset failed: %v", err)
+    }
+    if _, err := NewBackfiller(chain, ix, 1).Run(context.Background(), 0); err != nil {
+        t.Fatalf("rerun failed: %v", err)
+    }
+    if again, _ := st.Get("task-1"); len(again.History) != 3 || again.Version != task.Version {
+        t.Fatalf("rerun changed task-1: %+v", again)
+    }
+    escrows, _ := st.ListEscrows()
+    if len(escrows) != 2 {
+        t.Fatalf("expected 2 escrows, got %+v", escrows)
+    }
+    for _, e := range escrows {
+        if e.TaskID == "task-1" && e != paid || e.TaskID == "task-2" && (e.Status != types.ESCROW_LOCKED || e.Amount != "500") {
+            t.Fatalf("rerun changed escrow %+v", e)
+        }
+    }
+
+    // A chain that no longer has the checkpointed block is not mixed in
+    chain.fork(4)
+    chain.add("B")
+    _, err = NewBackfiller(chain, ix, 1).Run(context.Background(), 0)
+    var reorg *ReorgError
+    if !errors.As(err, &reorg) || reorg.Height != 5 || reorg.Want != "A5" || reorg.Got != "B5" {
+        t.Fatalf("expected a reorg at block 5, got %v", err)
+    }
+    if checkpoint, _ := ix.Checkpoint(); checkpoint.Height != 5 || checkpoint.BlockHash != "A5" {
+        t.Fatalf("reorg moved the checkpoint to %+v", checkpoint)
+    }
+}
+
+func TestBackfillIgnoresForgedMemos(t *testing.T) {
+    st := store.NewMemoryStore()
+    attacker := authtypes.NewModuleAddress("attacker")
+    chain := &fakeChain{}
+    chain.add("A", memoTx(t, 0, creator, escrow, 1000, map[string]interface{}{"type": "create_task", "task_id": "task-1", "title": "Fix"}))
+    chain.add("A", memoTx(t, 0, claimer, claimer, 1, map[string]interface{}{"type": "claim_task", "task_id": "task-1"}))
+    chain.add("A",
+        memoTx(t, 0, attacker, attacker, 1, map[string]interface{}{"type": "claim_task", "task_id": "task-1", "proof": "p"}),
+        memoTx(t, 0, attacker, attacker, 1, map[string]interface{}{"type": "submit_proof", "task_id": "task-1", "proof": "stolen"}),
+    )
+    chain.add("A", memoTx(t, 0, attacker, attacker, 1, map[string]interface{}{"type": "approve_task", "task_id": "task-1", "approver": attacker.String()}))
+
+    // Rebuilding the ledger from scratch repeats nothing forged
+    ix := NewIndexer("backfill", st, encoding.TxConfig.TxDecoder(), escrow.String())
+    for i := 0; i < 2; i++ {
+        if err := ix.Reset(); err != nil {
+            t.Fatalf("reset failed: %v", err)
+        }
+        if _, err := NewBackfiller(chain, ix, 1).Run(context.Background(), 0); err != nil {
+            t.Fatalf("backfill failed: %v", err)
+        }
+        task, _ := st.Get("task-1")
+        if task.Status != types.TaskStatusClaimed || task.Claimer != claimer.String() || task.Proof != "" {
+            t.Fatalf("expected task-1 claimed by the claimer without a proof, got %+v", task)
+        }
+        if escrowed, _ := st.GetEscrow("task-1"); escrowed.Status != types.ESCROW_LOCKED || escrowed.ReleaseTxHash != "" {
+            t.Fatalf("forged approval released the escrow: %+v", escrowed)
+        }
+    }
+}
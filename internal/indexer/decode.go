@@ -0,0 +1,164 @@
+package indexer
+
+import (
+    "encoding/json"
+    "fmt"
+    "strconv"
+
+    "bounty-system/internal/client"
+    "bounty-system/internal/types"
+    bountytypes "bounty-system/x/bounty/types"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+    abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// change is one task mutation read from a transaction.
+type change struct {
+    taskID    string
+    event     types.TaskEventType
+    actor     string
+    detail    string
+    status    types.TaskStatus // Empty leaves the status alone
+    created   *types.Task      // Set for the transaction that created the task
+    claimer   string
+    proof     string
+    escrow    string           // Escrow status the change leads to, if any
+    recipient string           // Of a payout or refund; empty means the claimer or creator
+    amount    string           // Moved by a memo transaction, checked against the task before applying
+    unchecked bool             // A memo claim or proof: the chain enforced none of the task rules
+}
+
+// memo is the task metadata the client writes into transaction memos.
+type memo struct {
+    Type        string `json:"type"`
+    TaskID      string `json:"task_id"`
+    Title       string `json:"title"`
+    Description string `json:"description"`
+    Status      string `json:"status"`
+    Proof       string `json:"proof"`
+    Approver    string `json:"approver"`
+    ClosedBy    string `json:"closed_by"`
+}
+
+// ModuleTaskID is the local ID of task id of the x/bounty module.
+func ModuleTaskID(id uint64) string {
+    return fmt.Sprintf("bounty-%d", id)
+}
+
+// changesOf reads the task changes in a decoded transaction: either the
+// memo metadata next to a bank send, or x/bounty messages.
+func changesOf(tx sdk.Tx, events []abci.Event, escrow string) []change {
+    msgs := tx.GetMsgs()
+    if len(msgs) == 0 {
+        return nil
+    }
+    if send, ok := msgs[0].(*banktypes.MsgSend); ok {
+        withMemo, ok := tx.(sdk.TxWithMemo)
+        if !ok || len(msgs) != 1 {
+            return nil
+        }
+        return memoChanges(send, withMemo.GetMemo(), escrow)
+    }
+    return moduleChanges(msgs, events)
+}
+
+// memoChanges reads the memo of a bank send. Anyone can write any memo, so
+// only sends shaped like the client's are accepted: bounties locked into the
+// escrow account, payouts and refunds out of it, and claims and proofs as
+// sends to oneself. Amounts and recipients are checked against the task
+// when the change is applied, and claims and proofs against the rules the
+// client enforces.
+func memoChanges(send *banktypes.MsgSend, memoText string, escrow string) []change {
+    var m memo
+    if memoText == "" || json.Unmarshal([]byte(memoText), &m) != nil || m.TaskID == "" {
+        return nil
+    }
+    amount := send.Amount.AmountOf(client.Denom)
+    moves := len(send.Amount) == 1 && amount.IsPositive()
+
+    switch {
+    case m.Type == "create_task" && moves && send.ToAddress == escrow && send.FromAddress != escrow:
+        return []change{{
+            taskID: m.TaskID, event: types.TaskEventCreated, actor: send.FromAddress, detail: amount.String() + " " + client.Denom,
+            created: &types.Task{ID: m.TaskID, Title: m.Title, Description: m.Description, Creator: send.FromAddress, Bounty: amount.String()},
+            escrow:  types.ESCROW_LOCKED, amount: amount.String(),
+        }}
+    case m.Type == "claim_task" && send.ToAddress == send.FromAddress:
+        return []change{{
+            taskID: m.TaskID, event: types.TaskEventClaimed, actor: send.FromAddress, detail: m.Proof,
+            status: types.TaskStatusClaimed, claimer: send.FromAddress, proof: m.Proof, unchecked: true,
+        }}
+    case m.Type == "submit_proof" && send.ToAddress == send.FromAddress:
+        return []change{{
+            taskID: m.TaskID, event: types.TaskEventProofSubmitted, actor: send.FromAddress, detail: m.Proof,
+            proof: m.Proof, unchecked: true,
+        }}
+    case m.Type == "approve_task" && moves && send.FromAddress == escrow:
+        return []change{{
+            taskID: m.TaskID, event: types.TaskEventPaid, actor: m.Approver, detail: "to " + send.ToAddress,
+            status: types.TaskStatusCompleted, escrow: types.ESCROW_RELEASED, recipient: send.ToAddress, amount: amount.String(),
+        }}
+    case m.Type == "refund_task" && moves && send.FromAddress == escrow:
+        status, _ := types.ParseTaskStatus(m.Status)
+        return []change{{
+            taskID: m.TaskID, event: types.TaskEventRefunded, actor: m.ClosedBy, detail: "to " + send.ToAddress,
+            status: status, escrow: types.ESCROW_REFUNDED, recipient: send.ToAddress, amount: amount.String(),
+        }}
+    }
+    return nil
+}
+
+func moduleChanges(msgs []sdk.Msg, events []abci.Event) []change {
+    // Each MsgCreateTask emits one create_task event carrying the new ID
+    var createdIDs []string
+    for _, event := range events {
+        if event.Type == bountytypes.EventTypeCreateTask {
+            if id, err := strconv.ParseUint(attribute(event, bountytypes.AttributeKeyTaskID), 10, 64); err == nil {
+                createdIDs = append(createdIDs, ModuleTaskID(id))
+            }
+        }
+    }
+
+    var changes []change
+    for _, msg := range msgs {
+        switch msg := msg.(type) {
+        case *bountytypes.MsgCreateTask:
+            if len(createdIDs) == 0 {
+                continue
+            }
+            id := createdIDs[0]
+            createdIDs = createdIDs[1:]
+            changes = append(changes, change{
+                taskID: id, event: types.TaskEventCreated, actor: msg.Creator, detail: msg.Bounty.String(),
+                created: &types.Task{ID: id, Title: msg.Title, Description: msg.Description, Creator: msg.Creator, Bounty: msg.Bounty.Amount.String()},
+                escrow:  types.ESCROW_LOCKED,
+            })
+        case *bountytypes.MsgClaimTask:
+            changes = append(changes, change{
+                taskID: ModuleTaskID(msg.TaskId), event: types.TaskEventClaimed, actor: msg.Claimer, detail: msg.Proof,
+                status: types.TaskStatusClaimed, claimer: msg.Claimer, proof: msg.Proof,
+            })
+        case *bountytypes.MsgApproveTask:
+            changes = append(changes, change{
+                taskID: ModuleTaskID(msg.TaskId), event: types.TaskEventPaid, actor: msg.Creator, status: types.TaskStatusCompleted,
+                escrow: types.ESCROW_RELEASED,
+            })
+        case *bountytypes.MsgCancelTask:
+            changes = append(changes, change{
+                taskID: ModuleTaskID(msg.TaskId), event: types.TaskEventCancelled, actor: msg.Creator, status: types.TaskStatusCancelled,
+                escrow: types.ESCROW_REFUNDED,
+            })
+        }
+    }
+    return changes
+}
+
+func attribute(event abci.Event, key string) string {
+    for _, attr := range event.Attributes {
+        if string(attr.Key) == key {
+            return string(attr.Value)
+        }
+    }
+    return ""
+}
@@ -0,0 +1,351 @@
+// Package indexer rebuilds task state from the chain. It reads the task
+// metadata the client writes into transaction memos as well as x/bounty
+// module transactions, and keeps the result in a task store and its escrow
+// ledger.
+package indexer
+
+import (
+    "errors"
+    "fmt"
+    "log"
+    "sync"
+    "time"
+
+    "bounty-system/internal/client"
+    "bounty-system/internal/store"
+    "bounty-system/internal/types"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Store is where the indexer keeps tasks, escrows and its progress. Roles
+// are read to check claims.
+type Store interface {
+    store.TaskStore
+    store.UserStore
+    store.EscrowStore
+    store.CheckpointStore
+}
+
+// DefaultCheckpoint names the checkpoint shared by the API server's
+// subscriber and the backfill command.
+const DefaultCheckpoint = "tendermint"
+
+// errApplied stops an update for a change the task already has.
+var errApplied = errors.New("already applied")
+
+// Indexer applies transactions to a task store. Its progress is saved as
+// the checkpoint called name after every transaction, and applying a
+// transaction twice has no effect, so a source may deliver one more than
+// once.
+type Indexer struct {
+    mu     sync.Mutex
+    name   string
+    store  Store
+    decode sdk.TxDecoder
+    escrow string // Account the client locks bounties in; memo payouts must come from it
+}
+
+func NewIndexer(name string, st Store, decode sdk.TxDecoder, escrow string) *Indexer {
+    return &Indexer{name: name, store: st, decode: decode, escrow: escrow}
+}
+
+// Checkpoint returns how far the indexer got, or the zero Checkpoint if it
+// has not applied anything yet.
+func (ix *Indexer) Checkpoint() (types.Checkpoint, error) {
+    checkpoint, err := ix.store.GetCheckpoint(ix.name)
+    if errors.Is(err, store.ErrNotFound) {
+        return types.Checkpoint{}, nil
+    }
+    return checkpoint, err
+}
+
+// Apply indexes tx. Transactions the checkpoint already covers are skipped;
+// failed, undecodable and unrelated ones only move the checkpoint.
+func (ix *Indexer) Apply(tx Tx) error {
+    ix.mu.Lock()
+    defer ix.mu.Unlock()
+
+    checkpoint, err := ix.Checkpoint()
+    if err != nil {
+        return fmt.Errorf("failed to read checkpoint: %v", err)
+    }
+    if checkpoint.Covers(tx.Height, tx.Index) {
+        return nil
+    }
+
+    if tx.Code == 0 {
+        decoded, err := ix.decode(tx.Bytes)
+        if err != nil {
+            log.Printf("Warning: skipping undecodable tx %s at height %d: %v", tx.Hash, tx.Height, err)
+        } else {
+            for _, ch := range changesOf(decoded, tx.Events, ix.escrow) {
+                if err := ix.apply(tx, ch); err != nil {
+                    return err
+                }
+            }
+        }
+    }
+
+    checkpoint = types.Checkpoint{Height: tx.Height, TxIndex: tx.Index, BlockHash: tx.BlockHash, UpdatedAt: time.Now().UTC()}
+    if err := ix.store.PutCheckpoint(ix.name, checkpoint); err != nil {
+        return fmt.Errorf("failed to save checkpoint: %v", err)
+    }
+    return nil
+}
+
+// ApplyBlock indexes the transactions of block and then checkpoints the
+// block itself, so blocks without transactions move the checkpoint too.
+func (ix *Indexer) ApplyBlock(block Block) error {
+    for _, tx := range block.Txs {
+        if err := ix.Apply(tx); err != nil {
+            return err
+        }
+    }
+
+    ix.mu.Lock()
+    defer ix.mu.Unlock()
+    checkpoint, err := ix.Checkpoint()
+    if err != nil {
+        return fmt.Errorf("failed to read checkpoint: %v", err)
+    }
+    if checkpoint.Height > block.Height || checkpoint.Height == block.Height && checkpoint.BlockHash == block.Hash {
+        return nil
+    }
+    if checkpoint.Height < block.Height {
+        checkpoint.TxIndex = 0
+    }
+    checkpoint.Height = block.Height
+    checkpoint.BlockHash = block.Hash
+    checkpoint.UpdatedAt = time.Now().UTC()
+    if err := ix.store.PutCheckpoint(ix.name, checkpoint); err != nil {
+        return fmt.Errorf("failed to save checkpoint: %v", err)
+    }
+    return nil
+}
+
+// Reset forgets the checkpoint, so the chain is read again from the start.
+// Changes the store already has are recognised and not applied twice.
+func (ix *Indexer) Reset() error {
+    ix.mu.Lock()
+    defer ix.mu.Unlock()
+    if err := ix.store.PutCheckpoint(ix.name, types.Checkpoint{UpdatedAt: time.Now().UTC()}); err != nil {
+        return fmt.Errorf("failed to reset checkpoint: %v", err)
+    }
+    return nil
+}
+
+// apply writes one change. Only store failures are returned; changes that
+// do not fit the stored task are logged and skipped.
+func (ix *Indexer) apply(tx Tx, ch change) error {
+    if ok, err := ix.verify(tx, ch); !ok || err != nil {
+        return err
+    }
+    event := types.TaskEvent{Type: ch.event, Actor: ch.actor, Time: tx.Time, TxHash: tx.Hash, TxStatus: types.TxConfirmed, Detail: ch.detail}
+
+    if ch.created != nil {
+        task := *ch.created
+        task.CreatedAt = tx.Time.UTC()
+        if _, err := task.Transition(types.TaskStatusOpen, task.Creator, tx.Time); err != nil {
+            return err
+        }
+        task.Record(event)
+        err := ix.store.Create(task)
+        if err == nil {
+            log.Printf("Indexed task %s created by %s at height %d", task.ID, task.Creator, tx.Height)
+            return ix.settle(tx, ch)
+        }
+        if !errors.Is(err, store.ErrExists) {
+            return fmt.Errorf("failed to store task %s: %v", task.ID, err)
+        }
+        // Created through this server or indexed before
+    }
+
+    check, err := ix.rules(tx, ch)
+    if err != nil {
+        return err
+    }
+    _, err = ix.store.Update(ch.taskID, func(task *types.Task) error {
+        return record(task, ch, event, check)
+    })
+    var invalid *types.InvalidTransitionError
+    var rejected *types.ValidationError
+    var forbidden *types.ForbiddenError
+    switch {
+    case err == nil, errors.Is(err, errApplied):
+        return ix.settle(tx, ch)
+    case errors.Is(err, store.ErrNotFound):
+        log.Printf("Warning: skipping %s of unknown task %s in tx %s", ch.event, ch.taskID, tx.Hash)
+        return nil
+    case errors.As(err, &invalid), errors.As(err, &rejected), errors.As(err, &forbidden):
+        log.Printf("Warning: skipping %s in tx %s: %v", ch.event, tx.Hash, err)
+        return nil
+    default:
+        return fmt.Errorf("failed to update task %s: %v", ch.taskID, err)
+    }
+}
+
+// rules returns the checks the client runs before it sends the claim or
+// proof ch, evaluated at the block time. Memos are signed by their sender
+// but the chain enforces none of the task rules, so without them any
+// account could claim any task. Other changes need no check.
+func (ix *Indexer) rules(tx Tx, ch change) (func(task types.Task) error, error) {
+    if !ch.unchecked {
+        return nil, nil
+    }
+    role := types.ROLE_USER
+    user, err := ix.store.GetUser(ch.actor)
+    if err == nil && user.Role != "" {
+        role = user.Role
+    } else if err != nil && !errors.Is(err, store.ErrNotFound) {
+        return nil, fmt.Errorf("failed to read role of %s: %v", ch.actor, err)
+    }
+
+    if ch.event == types.TaskEventProofSubmitted {
+        return func(task types.Task) error {
+            return client.CheckSubmission(task, ch.actor, tx.Time)
+        }, nil
+    }
+    return func(task types.Task) error {
+        if !types.Allowed(role, types.ActionClaimTask) {
+            return &types.ForbiddenError{Address: ch.actor, Role: role, Action: types.ActionClaimTask}
+        }
+        return client.CheckClaimable(task, ch.actor, ch.proof, tx.Time)
+    }, nil
+}
+
+// verify checks the funds a memo transaction moved against the stored task:
+// a lock must be the task's bounty from its creator, and a payout or refund
+// must send the escrowed amount to the claimer or creator.
+func (ix *Indexer) verify(tx Tx, ch change) (bool, error) {
+    if ch.amount == "" {
+        return true, nil
+    }
+    task, err := ix.store.Get(ch.taskID)
+    if errors.Is(err, store.ErrNotFound) {
+        // New tasks are created from the lock itself; apply skips anything else
+        return true, nil
+    }
+    if err != nil {
+        return false, fmt.Errorf("failed to read task %s: %v", ch.taskID, err)
+    }
+    escrowed := task.Bounty
+    if escrow, err := ix.store.GetEscrow(ch.taskID); err == nil {
+        escrowed = escrow.Amount
+    } else if !errors.Is(err, store.ErrNotFound) {
+        return false, fmt.Errorf("failed to read escrow of task %s: %v", ch.taskID, err)
+    }
+
+    var ok bool
+    switch {
+    case ch.created != nil:
+        ok = ch.actor == task.Creator && ch.amount == task.Bounty
+    case ch.escrow == types.ESCROW_RELEASED:
+        ok = ch.recipient == task.Claimer && ch.amount == escrowed
+    case ch.escrow == types.ESCROW_REFUNDED:
+        ok = ch.recipient == task.Creator && ch.amount == escrowed
+    }
+    if !ok {
+        log.Printf("Warning: skipping %s of task %s in tx %s: %s to %s does not match the task", ch.event, ch.taskID, tx.Hash, ch.amount, ch.recipient)
+    }
+    return ok, nil
+}
+
+// settle mirrors ch in the escrow ledger. Like record it leaves alone what
+// the ledger already has, so replaying transactions never locks or pays out
+// a bounty twice. ch has passed verify, so memo payouts really left escrow.
+func (ix *Indexer) settle(tx Tx, ch change) error {
+    if ch.escrow == "" {
+        return nil
+    }
+    task, err := ix.store.Get(ch.taskID)
+    if err != nil {
+        return fmt.Errorf("failed to read task %s: %v", ch.taskID, err)
+    }
+
+    escrow, err := ix.store.GetEscrow(ch.taskID)
+    switch {
+    case errors.Is(err, store.ErrNotFound):
+        if ch.escrow != types.ESCROW_LOCKED {
+            log.Printf("Warning: no escrow to settle for task %s in tx %s", ch.taskID, tx.Hash)
+            return nil
+        }
+        escrow = types.Escrow{TaskID: task.ID, Depositor: task.Creator, Amount: task.Bounty, Status: types.ESCROW_LOCKED, LockTxHash: tx.Hash}
+    case err != nil:
+        return fmt.Errorf("failed to read escrow of task %s: %v", ch.taskID, err)
+    case ch.escrow == types.ESCROW_LOCKED:
+        if escrow.LockTxHash != "" {
+            return nil
+        }
+        escrow.LockTxHash = tx.Hash
+    case escrow.Status != types.ESCROW_LOCKED:
+        // Settled already, maybe by the client before the hash was known
+        if escrow.Status != ch.escrow || escrow.ReleaseTxHash != "" {
+            return nil
+        }
+        escrow.ReleaseTxHash = tx.Hash
+    default:
+        escrow.Status = ch.escrow
+        escrow.Recipient = ch.recipient
+        if escrow.Recipient == "" && ch.escrow == types.ESCROW_RELEASED {
+            escrow.Recipient = task.Claimer
+        } else if escrow.Recipient == "" {
+            escrow.Recipient = task.Creator
+        }
+        escrow.ReleaseTxHash = tx.Hash
+    }
+    if err := ix.store.PutEscrow(escrow); err != nil {
+        return fmt.Errorf("failed to store escrow of task %s: %v", ch.taskID, err)
+    }
+    return nil
+}
+
+// record applies ch to task unless task already has it. Events the client
+// is still waiting on are confirmed; it checked them when it made them.
+// Anything else must pass check, if given.
+func record(task *types.Task, ch change, event types.TaskEvent, check func(task types.Task) error) error {
+    for i, existing := range task.History {
+        if existing.TxHash != event.TxHash || existing.Type != event.Type {
+            continue
+        }
+        if existing.TxStatus == types.TxPending {
+            task.History[i].TxStatus = types.TxConfirmed
+            return nil
+        }
+        return errApplied
+    }
+    // The client records its own changes before their tx hash is known
+    for i := len(task.History) - 1; i >= 0; i-- {
+        existing := task.History[i]
+        if existing.Type != event.Type {
+            continue
+        }
+        if existing.TxHash == "" && existing.Actor == event.Actor {
+            task.History[i].TxHash = event.TxHash
+            task.History[i].TxStatus = types.TxConfirmed
+            return nil
+        }
+        break
+    }
+    if ch.created != nil {
+        return errApplied
+    }
+    if check != nil {
+        if err := check(*task); err != nil {
+            return err
+        }
+    }
+
+    if ch.status != "" && task.Status != ch.status {
+        if _, err := task.Transition(ch.status, ch.actor, event.Time); err != nil {
+            return err
+        }
+    }
+    if ch.claimer != "" {
+        task.Claimer = ch.claimer
+    }
+    if ch.proof != "" {
+        task.Proof = ch.proof
+    }
+    task.Record(event)
+    return nil
+}
@@ -0,0 +1,349 @@
+package indexer
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "sync"
+    "testing"
+    "time"
+
+    "bounty-system/internal/client"
+    "bounty-system/internal/store"
+    "bounty-system/internal/types"
+    bountytypes "bounty-system/x/bounty/types"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+    banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+    abci "github.com/tendermint/tendermint/abci/types"
+    "github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+var encoding = client.MakeEncodingConfig()
+
+var (
+    creator  = authtypes.NewModuleAddress("creator")
+    claimer  = authtypes.NewModuleAddress("claimer")
+    escrow   = authtypes.NewModuleAddress("escrow")
+    approver = authtypes.NewModuleAddress("approver")
+)
+
+// fakeSource is a chain held in memory. Published transactions go to the
+// current subscribers and stay available to TxsFrom.
+type fakeSource struct {
+    mu   sync.Mutex
+    txs  []Tx
+    subs []chan Tx
+}
+
+func (f *fakeSource) Subscribe(ctx context.Context) (<-chan Tx, error) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    ch := make(chan Tx, 16)
+    f.subs = append(f.subs, ch)
+    return ch, nil
+}
+
+func (f *fakeSource) TxsFrom(ctx context.Context, height int64, fn func(Tx) error) error {
+    f.mu.Lock()
+    var txs []Tx
+    for _, tx := range f.txs {
+        if tx.Height >= height {
+            txs = append(txs, tx)
+        }
+    }
+    f.mu.Unlock()
+    for _, tx := range txs {
+        if err := fn(tx); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// publish commits tx; live reports whether subscribers see it.
+func (f *fakeSource) publish(tx Tx, live bool) {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    f.txs = append(f.txs, tx)
+    if live {
+        for _, ch := range f.subs {
+            ch <- tx
+        }
+    }
+}
+
+// drop ends every subscription, like a lost websocket connection.
+func (f *fakeSource) drop() {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    for _, ch := range f.subs {
+        close(ch)
+    }
+    f.subs = nil
+}
+
+func encodeTx(t *testing.T, memo string, msgs ...sdk.Msg) []byte {
+    builder := encoding.TxConfig.NewTxBuilder()
+    if err := builder.SetMsgs(msgs...); err != nil {
+        t.Fatalf("set msgs failed: %v", err)
+    }
+    builder.SetMemo(memo)
+    bz, err := encoding.TxConfig.TxEncoder()(builder.GetTx())
+    if err != nil {
+        t.Fatalf("encode failed: %v", err)
+    }
+    return bz
+}
+
+func memoTx(t *testing.T, height int64, from, to sdk.AccAddress, amount int64, fields map[string]interface{}) Tx {
+    memo, _ := json.Marshal(fields)
+    bz := encodeTx(t, string(memo), banktypes.NewMsgSend(from, to, sdk.NewCoins(sdk.NewInt64Coin(client.Denom, amount))))
+    return Tx{
+        Height: height,
+        Hash:   fmt.Sprintf("%X", tmhash.Sum(bz)),
+        Bytes:  bz,
+        Time:   time.Date(2024, 5, 1, 12, 0, int(height), 0, time.UTC),
+    }
+}
+
+func eventually(t *testing.T, what string, cond func() bool) {
+    t.Helper()
+    for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+        if cond() {
+            return
+        }
+    }
+    t.Fatalf("timed out waiting for %s", what)
+}
+
+func hasStatus(st store.TaskStore, id string, status types.TaskStatus) func() bool {
+    return func() bool {
+        task, err := st.Get(id)
+        return err == nil && task.Status == status
+    }
+}
+
+func TestSubscriberFollowsAndResumes(t *testing.T) {
+    st := store.NewMemoryStore()
+    source := &fakeSource{}
+    // Committed before the indexer first runs
+    source.publish(memoTx(t, 1, creator, escrow, 1000, map[string]interface{}{
+        "type": "create_task", "task_id": "task-1", "title": "Fix bug", "status": "OPEN",
+    }), false)
+
+    run := func(ctx context.Context) <-chan error {
+        subscriber := NewSubscriber(source, NewIndexer("test", st, encoding.TxConfig.TxDecoder(), escrow.String()))
+        subscriber.retry = 10 * time.Millisecond
+        done := make(chan error, 1)
+        go func() { done <- subscriber.Run(ctx) }()
+        return done
+    }
+
+    ctx, stop := context.WithCancel(context.Background())
+    done := run(ctx)
+    eventually(t, "catch-up", hasStatus(st, "task-1", types.TaskStatusOpen))
+
+    eventually(t, "subscription", func() bool {
+        source.mu.Lock()
+        defer source.mu.Unlock()
+        return len(source.subs) == 1
+    })
+    source.publish(memoTx(t, 2, claimer, claimer, 1, map[string]interface{}{
+        "type": "claim_task", "task_id": "task-1", "proof": "https://github.com/pr/1",
+    }), true)
+    eventually(t, "live claim", hasStatus(st, "task-1", types.TaskStatusClaimed))
+
+    // The approval lands while the connection is down
+    source.drop()
+    source.publish(memoTx(t, 3, escrow, claimer, 1000, map[string]interface{}{
+        "type": "approve_task", "task_id": "task-1", "approver": approver.String(), "status": "COMPLETED",
+    }), false)
+    eventually(t, "approval after reconnect", hasStatus(st, "task-1", types.TaskStatusCompleted))
+
+    stop()
+    if err := <-done; err != context.Canceled {
+        t.Fatalf("expected Run to stop with context.Canceled, got %v", err)
+    }
+
+    // A restarted indexer resumes from the checkpoint and sees block 3 again
+    source.publish(memoTx(t, 4, creator, escrow, 500, map[string]interface{}{
+        "type": "create_task", "task_id": "task-2", "title": "Write docs", "status": "OPEN",
+    }), false)
+    ctx, stop = context.WithCancel(context.Background())
+    defer stop()
+    done = run(ctx)
+    eventually(t, "catch-up after restart", hasStatus(st, "task-2", types.TaskStatusOpen))
+
+    task, _ := st.Get("task-1")
+    if task.Claimer != claimer.String() || task.Proof != "https://github.com/pr/1" || task.Creator != creator.String() || task.Bounty != "1000" {
+        t.Fatalf("unexpected task %+v", task)
+    }
+    var got []types.TaskEventType
+    for _, event := range task.History {
+        if event.TxHash == "" {
+            t.Errorf("event %s has no tx hash", event.Type)
+        }
+        got = append(got, event.Type)
+    }
+    want := []types.TaskEventType{types.TaskEventCreated, types.TaskEventClaimed, types.TaskEventPaid}
+    if fmt.Sprint(got) != fmt.Sprint(want) {
+        t.Fatalf("expected history %v, got %v", want, got)
+    }
+    if checkpoint, _ := st.GetCheckpoint("test"); checkpoint.Height != 4 {
+        t.Fatalf("expected checkpoint at height 4, got %+v", checkpoint)
+    }
+}
+
+func TestApplyMergesWithClientState(t *testing.T) {
+    st := store.NewMemoryStore()
+    ix := NewIndexer("test", st, encoding.TxConfig.TxDecoder(), escrow.String())
+
+    // The client stored the task and its claim before the claim tx hash was known
+    task := types.Task{ID: "task-1", Creator: creator.String(), Bounty: "1000", Status: types.TaskStatusClaimed, Claimer: claimer.String()}
+    task.Record(types.TaskEvent{Type: types.TaskEventClaimed, Actor: claimer.String(), Time: time.Now()})
+    st.Create(task)
+
+    claim := memoTx(t, 5, claimer, claimer, 1, map[string]interface{}{"type": "claim_task", "task_id": "task-1", "proof": "p"})
+    if err := ix.Apply(claim); err != nil {
+        t.Fatalf("apply failed: %v", err)
+    }
+    // Another indexer over the same store does not add anything either
+    if err := NewIndexer("other", st, encoding.TxConfig.TxDecoder(), escrow.String()).Apply(claim); err != nil {
+        t.Fatalf("apply failed: %v", err)
+    }
+    got, _ := st.Get("task-1")
+    if len(got.History) != 1 || got.History[0].TxHash != claim.Hash {
+        t.Fatalf("expected the claim event to get the tx hash, got %+v", got.History)
+    }
+
+    // A failed payout only moves the checkpoint
+    payout := memoTx(t, 6, escrow, claimer, 1000, map[string]interface{}{"type": "approve_task", "task_id": "task-1", "approver": approver.String()})
+    payout.Code = 5
+    if err := ix.Apply(payout); err != nil {
+        t.Fatalf("apply failed: %v", err)
+    }
+    if got, _ := st.Get("task-1"); got.Status != types.TaskStatusClaimed {
+        t.Fatalf("failed tx changed the task to %s", got.Status)
+    }
+    if checkpoint, _ := ix.Checkpoint(); checkpoint.Height != 6 {
+        t.Fatalf("expected checkpoint at height 6, got %+v", checkpoint)
+    }
+
+    // Unrelated and undecodable transactions are skipped
+    plain := memoTx(t, 7, creator, claimer, 10, nil)
+    if err := ix.Apply(plain); err != nil {
+        t.Fatalf("apply failed: %v", err)
+    }
+    if err := ix.Apply(Tx{Height: 8, Hash: "BAD", Bytes: []byte("garbage")}); err != nil {
+        t.Fatalf("apply failed: %v", err)
+    }
+}
+
+func TestModuleTransactions(t *testing.T) {
+    st := store.NewMemoryStore()
+    ix := NewIndexer("test", st, encoding.TxConfig.TxDecoder(), escrow.String())
+
+    create := encodeTx(t, "", bountytypes.NewMsgCreateTask(creator, "On-chain task", "details", sdk.NewInt64Coin(client.Denom, 700)))
+    err := ix.Apply(Tx{Height: 1, Hash: "A1", Bytes: create, Time: time.Now(), Events: []abci.Event{{
+        Type:       bountytypes.EventTypeCreateTask,
+        Attributes: []abci.EventAttribute{{Key: []byte(bountytypes.AttributeKeyTaskID), Value: []byte("7")}},
+    }}})
+    if err != nil {
+        t.Fatalf("apply create failed: %v", err)
+    }
+    ix.Apply(Tx{Height: 2, Hash: "A2", Bytes: encodeTx(t, "", bountytypes.NewMsgClaimTask(claimer, 7, "proof")), Time: time.Now()})
+    ix.Apply(Tx{Height: 3, Hash: "A3", Bytes: encodeTx(t, "", bountytypes.NewMsgApproveTask(creator, 7)), Time: time.Now()})
+
+    task, err := st.Get(ModuleTaskID(7))
+    if err != nil {
+        t.Fatalf("module task not indexed: %v", err)
+    }
+    if task.Title != "On-chain task" || task.Bounty != "700" || task.Claimer != claimer.String() || task.Status != types.TaskStatusCompleted {
+        t.Fatalf("unexpected task %+v", task)
+    }
+}
+
+func TestForgedMemosAreIgnored(t *testing.T) {
+    st := store.NewMemoryStore()
+    ix := NewIndexer("test", st, encoding.TxConfig.TxDecoder(), escrow.String())
+    attacker := authtypes.NewModuleAddress("attacker")
+    approve := map[string]interface{}{"type": "approve_task", "task_id": "task-1", "approver": approver.String()}
+
+    ix.Apply(memoTx(t, 1, creator, escrow, 1000, map[string]interface{}{"type": "create_task", "task_id": "task-1", "title": "Fix"}))
+    ix.Apply(memoTx(t, 2, claimer, claimer, 1, map[string]interface{}{"type": "claim_task", "task_id": "task-1", "proof": "p"}))
+
+    forged := []Tx{
+        memoTx(t, 3, attacker, attacker, 1, approve),
+        memoTx(t, 4, attacker, attacker, 1, map[string]interface{}{"type": "refund_task", "task_id": "task-1", "status": "CANCELLED"}),
+        memoTx(t, 5, escrow, attacker, 1000, approve),
+        memoTx(t, 6, escrow, claimer, 1, approve),
+        memoTx(t, 7, attacker, attacker, 1000, map[string]interface{}{"type": "create_task", "task_id": "task-2", "title": "Fake"}),
+        memoTx(t, 8, attacker, escrow, 5, map[string]interface{}{"type": "create_task", "task_id": "task-1", "title": "Takeover"}),
+    }
+    for _, tx := range forged {
+        if err := ix.Apply(tx); err != nil {
+            t.Fatalf("apply failed: %v", err)
+        }
+    }
+    task, _ := st.Get("task-1")
+    if task.Status != types.TaskStatusClaimed || task.Creator != creator.String() || len(task.History) != 2 {
+        t.Fatalf("forged memos changed task-1: %+v", task)
+    }
+    if locked, _ := st.GetEscrow("task-1"); locked.Status != types.ESCROW_LOCKED || locked.Amount != "1000" {
+        t.Fatalf("forged memos changed the escrow: %+v", locked)
+    }
+    if _, err := st.Get("task-2"); err == nil {
+        t.Fatalf("a create that locked nothing in escrow must not add a task")
+    }
+
+    // The real payout still applies
+    payout := memoTx(t, 9, escrow, claimer, 1000, approve)
+    if err := ix.Apply(payout); err != nil {
+        t.Fatalf("apply failed: %v", err)
+    }
+    if paid, _ := st.GetEscrow("task-1"); paid.Status != types.ESCROW_RELEASED || paid.Recipient != claimer.String() || paid.ReleaseTxHash != payout.Hash {
+        t.Fatalf("expected the bounty released to the claimer, got %+v", paid)
+    }
+}
+
+func TestMemoClaimsFollowTaskRules(t *testing.T) {
+    st := store.NewMemoryStore()
+    ix := NewIndexer("test", st, encoding.TxConfig.TxDecoder(), escrow.String())
+    stranger := authtypes.NewModuleAddress("stranger")
+    auditor := authtypes.NewModuleAddress("auditor")
+    st.PutUser(types.User{Address: auditor.String(), Role: types.ROLE_AUDITOR})
+
+    // task-2's claim deadline passes before the stranger's claim is in a block
+    deadline := time.Date(2024, 5, 1, 12, 0, 3, 0, time.UTC)
+    ix.Apply(memoTx(t, 1, creator, escrow, 1000, map[string]interface{}{"type": "create_task", "task_id": "task-1", "title": "Fix"}))
+    late := types.Task{ID: "task-2", Creator: creator.String(), Bounty: "500", ClaimDeadline: &deadline}
+    late.Transition(types.TaskStatusOpen, creator.String(), deadline.Add(-time.Hour))
+    st.Create(late)
+
+    forged := []Tx{
+        memoTx(t, 2, auditor, auditor, 1, map[string]interface{}{"type": "claim_task", "task_id": "task-1", "proof": "p"}),
+        memoTx(t, 3, stranger, stranger, 1, map[string]interface{}{"type": "submit_proof", "task_id": "task-1", "proof": "p"}),
+        memoTx(t, 4, stranger, stranger, 1, map[string]interface{}{"type": "claim_task", "task_id": "task-2", "proof": "p"}),
+        memoTx(t, 5, creator, creator, 1, map[string]interface{}{"type": "claim_task", "task_id": "task-1", "proof": "p"}),
+    }
+    for _, tx := range forged {
+        if err := ix.Apply(tx); err != nil {
+            t.Fatalf("apply failed: %v", err)
+        }
+    }
+    for _, id := range []string{"task-1", "task-2"} {
+        if task, _ := st.Get(id); task.Status != types.TaskStatusOpen || task.Claimer != "" || task.Proof != "" {
+            t.Fatalf("a memo breaking the task rules changed %s: %+v", id, task)
+        }
+    }
+
+    // A claim the client would have accepted is applied, and only its
+    // claimer can add the proof
+    ix.Apply(memoTx(t, 6, claimer, claimer, 1, map[string]interface{}{"type": "claim_task", "task_id": "task-1"}))
+    ix.Apply(memoTx(t, 7, stranger, stranger, 1, map[string]interface{}{"type": "submit_proof", "task_id": "task-1", "proof": "stolen"}))
+    ix.Apply(memoTx(t, 8, claimer, claimer, 1, map[string]interface{}{"type": "submit_proof", "task_id": "task-1", "proof": "mine"}))
+    if task, _ := st.Get("task-1"); task.Status != types.TaskStatusClaimed || task.Claimer != claimer.String() || task.Proof != "mine" {
+        t.Fatalf("expected task-1 claimed by the claimer with their proof, got %+v", task)
+    }
+}
@@ -0,0 +1,47 @@
+package indexer
+
+import (
+    "context"
+    "time"
+
+    abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// Tx is one transaction delivered in a block.
+type Tx struct {
+    Height    int64
+    Index     uint32       // Position of the tx in its block
+    Hash      string       // Upper-case hex, as the REST API prints it
+    Bytes     []byte
+    Code      uint32       // Non-zero when DeliverTx failed
+    Events    []abci.Event
+    Time      time.Time    // Block time
+    BlockHash string       // Empty if the source does not know it
+}
+
+// Block is one committed block with the results of its transactions.
+type Block struct {
+    Height     int64
+    Hash       string
+    ParentHash string // Hash of block Height-1
+    Time       time.Time
+    Txs        []Tx
+}
+
+// Source delivers committed transactions.
+type Source interface {
+    // Subscribe streams transactions as their blocks are committed. The
+    // channel is closed when the subscription ends, e.g. because the
+    // connection dropped and transactions may have been missed.
+    Subscribe(ctx context.Context) (<-chan Tx, error)
+
+    // TxsFrom calls fn with the transactions of block height and all
+    // later blocks, in chain order, stopping at the first error fn returns.
+    TxsFrom(ctx context.Context, height int64, fn func(Tx) error) error
+}
+
+// BlockSource reads committed blocks by height.
+type BlockSource interface {
+    LatestHeight(ctx context.Context) (int64, error)
+    Block(ctx context.Context, height int64) (Block, error)
+}
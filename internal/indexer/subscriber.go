@@ -0,0 +1,101 @@
+package indexer
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "time"
+)
+
+// DefaultRetryDelay is how long the subscriber waits before subscribing
+// again after a subscription ended.
+const DefaultRetryDelay = 5 * time.Second
+
+// Subscriber keeps an Indexer up to date with a Source.
+type Subscriber struct {
+    source  Source
+    indexer *Indexer
+    retry   time.Duration
+}
+
+func NewSubscriber(source Source, indexer *Indexer) *Subscriber {
+    return &Subscriber{source: source, indexer: indexer, retry: DefaultRetryDelay}
+}
+
+// Run follows the chain until ctx is done. Every pass subscribes first and
+// then catches up from the checkpoint before applying live transactions, so
+// nothing committed in between is missed; transactions seen twice are
+// skipped by the indexer. When a subscription ends Run starts over after a
+// pause, which also resumes from the checkpoint after a restart.
+func (s *Subscriber) Run(ctx context.Context) error {
+    for {
+        err := s.follow(ctx)
+        if ctx.Err() != nil {
+            return ctx.Err()
+        }
+        log.Printf("Warning: chain subscription ended: %v; resuming in %s", err, s.retry)
+        select {
+        case <-ctx.Done():
+            return ctx.Err()
+        case <-time.After(s.retry):
+        }
+    }
+}
+
+func (s *Subscriber) follow(ctx context.Context) error {
+    ctx, cancel := context.WithCancel(ctx)
+    defer cancel()
+
+    live, err := s.source.Subscribe(ctx)
+    if err != nil {
+        return fmt.Errorf("failed to subscribe: %v", err)
+    }
+    if err := s.CatchUp(ctx); err != nil {
+        return err
+    }
+    for {
+        select {
+        case <-ctx.Done():
+            return ctx.Err()
+        case tx, ok := <-live:
+            if !ok {
+                return errors.New("subscription closed")
+            }
+            if err := s.indexer.Apply(tx); err != nil {
+                return err
+            }
+        }
+    }
+}
+
+// CatchUp applies every transaction from the checkpoint's block on.
+func (s *Subscriber) CatchUp(ctx context.Context) error {
+    checkpoint, err := s.indexer.Checkpoint()
+    if err != nil {
+        return fmt.Errorf("failed to read checkpoint: %v", err)
+    }
+    from := checkpoint.Height
+    if from < 1 {
+        from = 1
+    }
+    applied := 0
+    var applyErr error
+    err = s.source.TxsFrom(ctx, from, func(tx Tx) error {
+        if applyErr = s.indexer.Apply(tx); applyErr != nil {
+            return applyErr
+        }
+        applied++
+        return nil
+    })
+    if applyErr != nil {
+        return applyErr
+    }
+    if err != nil {
+        return fmt.Errorf("failed to catch up from height %d: %v", from, err)
+    }
+    if applied > 0 {
+        log.Printf("Caught up on %d transactions from height %d", applied, from)
+    }
+    return nil
+}
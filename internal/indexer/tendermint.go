@@ -0,0 +1,223 @@
+package indexer
+
+import (
+    "context"
+    "fmt"
+    "strings"
+    "time"
+
+    rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+    ctypes "github.com/tendermint/tendermint/rpc/core/types"
+    jsonrpcclient "github.com/tendermint/tendermint/rpc/jsonrpc/client"
+    tmjson "github.com/tendermint/tendermint/libs/json"
+    tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// txQuery selects every delivered transaction; bounty ones can only be told
+// apart after decoding, since memos are not indexed.
+const txQuery = "tm.event='Tx'"
+
+// searchPageSize is the largest page tendermint's tx_search serves.
+const searchPageSize = 100
+
+// TendermintSource reads transactions from a tendermint v0.34 node: live
+// ones through the /websocket endpoint, past ones through tx_search or
+// block by block.
+type TendermintSource struct {
+    remote string
+    rpc    *rpchttp.HTTP
+}
+
+// NewTendermintSource connects to the node's RPC endpoint, e.g.
+// "http://localhost:26657".
+func NewTendermintSource(remote string) (*TendermintSource, error) {
+    rpc, err := rpchttp.New(remote, "/websocket")
+    if err != nil {
+        return nil, fmt.Errorf("failed to create rpc client for %s: %v", remote, err)
+    }
+    return &TendermintSource{remote: remote, rpc: rpc}, nil
+}
+
+// Subscribe opens a websocket subscription. The websocket client redials on
+// its own, but events sent while it was away are lost, so a reconnect ends
+// the subscription and the caller catches up before subscribing again.
+func (s *TendermintSource) Subscribe(ctx context.Context) (<-chan Tx, error) {
+    ctx, cancel := context.WithCancel(ctx)
+    ws, err := jsonrpcclient.NewWS(s.remote, "/websocket", jsonrpcclient.OnReconnect(cancel))
+    if err != nil {
+        cancel()
+        return nil, fmt.Errorf("failed to create websocket client: %v", err)
+    }
+    if err := ws.Start(); err != nil {
+        cancel()
+        return nil, fmt.Errorf("failed to connect to %s/websocket: %v", s.remote, err)
+    }
+    if err := ws.Subscribe(ctx, txQuery); err != nil {
+        ws.Stop()
+        cancel()
+        return nil, fmt.Errorf("failed to subscribe: %v", err)
+    }
+
+    out := make(chan Tx, searchPageSize)
+    go func() {
+        defer close(out)
+        defer ws.Stop()
+        defer cancel()
+        headers := &blockHeaders{}
+        for {
+            select {
+            case <-ctx.Done():
+                return
+            case response, ok := <-ws.ResponsesCh:
+                if !ok {
+                    return
+                }
+                if response.Error != nil {
+                    return
+                }
+                var event ctypes.ResultEvent
+                if err := tmjson.Unmarshal(response.Result, &event); err != nil {
+                    continue
+                }
+                data, ok := event.Data.(tmtypes.EventDataTx)
+                if !ok {
+                    // The reply to the subscribe call itself carries no event
+                    continue
+                }
+                header, err := headers.lookup(ctx, s.rpc, data.Height)
+                if err != nil {
+                    return
+                }
+                select {
+                case out <- Tx{
+                    Height:    data.Height,
+                    Index:     data.Index,
+                    Hash:      txHash(data.Tx),
+                    Bytes:     data.Tx,
+                    Code:      data.Result.Code,
+                    Events:    data.Result.Events,
+                    Time:      header.time,
+                    BlockHash: header.hash,
+                }:
+                case <-ctx.Done():
+                    return
+                }
+            }
+        }
+    }()
+    return out, nil
+}
+
+// TxsFrom pages through tx_search, handing each page to fn before the next
+// one is fetched. The node must index transactions.
+func (s *TendermintSource) TxsFrom(ctx context.Context, height int64, fn func(Tx) error) error {
+    query := fmt.Sprintf("tx.height >= %d", height)
+    headers := &blockHeaders{}
+    seen := 0
+    for page := 1; ; page++ {
+        pageNum, perPage := page, searchPageSize
+        result, err := s.rpc.TxSearch(ctx, query, false, &pageNum, &perPage, "asc")
+        if err != nil {
+            return err
+        }
+        for _, found := range result.Txs {
+            header, err := headers.lookup(ctx, s.rpc, found.Height)
+            if err != nil {
+                return err
+            }
+            err = fn(Tx{
+                Height:    found.Height,
+                Index:     found.Index,
+                Hash:      strings.ToUpper(found.Hash.String()),
+                Bytes:     found.Tx,
+                Code:      found.TxResult.Code,
+                Events:    found.TxResult.Events,
+                Time:      header.time,
+                BlockHash: header.hash,
+            })
+            if err != nil {
+                return err
+            }
+        }
+        seen += len(result.Txs)
+        if len(result.Txs) == 0 || seen >= result.TotalCount {
+            return nil
+        }
+    }
+}
+
+// LatestHeight returns the height of the node's latest block.
+func (s *TendermintSource) LatestHeight(ctx context.Context) (int64, error) {
+    status, err := s.rpc.Status(ctx)
+    if err != nil {
+        return 0, fmt.Errorf("failed to fetch node status: %v", err)
+    }
+    return status.SyncInfo.LatestBlockHeight, nil
+}
+
+// Block reads a block and its DeliverTx results. The node must still have
+// both, so backfilling old heights needs an archive node.
+func (s *TendermintSource) Block(ctx context.Context, height int64) (Block, error) {
+    block, err := s.rpc.Block(ctx, &height)
+    if err != nil {
+        return Block{}, fmt.Errorf("failed to fetch block %d: %v", height, err)
+    }
+    results, err := s.rpc.BlockResults(ctx, &height)
+    if err != nil {
+        return Block{}, fmt.Errorf("failed to fetch results of block %d: %v", height, err)
+    }
+    if len(results.TxsResults) != len(block.Block.Txs) {
+        return Block{}, fmt.Errorf("block %d has %d txs but %d results", height, len(block.Block.Txs), len(results.TxsResults))
+    }
+
+    out := Block{
+        Height:     height,
+        Hash:       block.BlockID.Hash.String(),
+        ParentHash: block.Block.LastBlockID.Hash.String(),
+        Time:       block.Block.Time,
+    }
+    for i, tx := range block.Block.Txs {
+        out.Txs = append(out.Txs, Tx{
+            Height:    height,
+            Index:     uint32(i),
+            Hash:      txHash(tx),
+            Bytes:     tx,
+            Code:      results.TxsResults[i].Code,
+            Events:    results.TxsResults[i].Events,
+            Time:      out.Time,
+            BlockHash: out.Hash,
+        })
+    }
+    return out, nil
+}
+
+// blockHeader is what transactions need to know about their block.
+type blockHeader struct {
+    hash string
+    time time.Time
+}
+
+// blockHeaders remembers the header of the last block looked up. Both
+// callers read transactions in height order, so that is the only one asked
+// for again and a long subscription does not grow the cache.
+type blockHeaders struct {
+    height int64
+    header blockHeader
+}
+
+func (b *blockHeaders) lookup(ctx context.Context, rpc *rpchttp.HTTP, height int64) (blockHeader, error) {
+    if b.height == height {
+        return b.header, nil
+    }
+    block, err := rpc.Block(ctx, &height)
+    if err != nil {
+        return blockHeader{}, fmt.Errorf("failed to fetch block %d: %v", height, err)
+    }
+    b.height = height
+    b.header = blockHeader{hash: block.BlockID.Hash.String(), time: block.Block.Time}
+    return b.header, nil
+}
+
+func txHash(tx tmtypes.Tx) string {
+    return fmt.Sprintf("%X", tx.Hash())
+}
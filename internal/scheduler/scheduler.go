@@ -0,0 +1,141 @@
+// Package scheduler enforces task deadlines in the background: it expires
+// OPEN tasks past their claim deadline and releases claims whose proof did
+// not arrive before the submission deadline.
+package scheduler
+
+import (
+    "log"
+    "sync"
+    "time"
+
+    "bounty-system/internal/store"
+    "bounty-system/internal/types"
+)
+
+// Clock tells the scheduler what time it is. Tests substitute a fake one.
+type Clock interface {
+    Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// TaskExpirer is the part of the blockchain client the scheduler drives.
+// ExpireTask is expected to send the refund without waiting for its block.
+type TaskExpirer interface {
+    QueryTasks(q store.TaskQuery) (store.TaskPage, error)
+    ExpireTask(taskID string, now time.Time) error
+    ReleaseClaim(taskID string, now time.Time) error
+}
+
+// Option configures a Scheduler.
+type Option func(*Scheduler)
+
+// WithClock replaces the wall clock used to evaluate deadlines.
+func WithClock(clock Clock) Option {
+    return func(s *Scheduler) {
+        s.clock = clock
+    }
+}
+
+// Scheduler periodically applies task deadlines.
+type Scheduler struct {
+    tasks    TaskExpirer
+    clock    Clock
+    interval time.Duration
+
+    stopOnce sync.Once
+    stop     chan struct{}
+    done     chan struct{}
+}
+
+// New creates a scheduler that checks deadlines every interval once started.
+func New(tasks TaskExpirer, interval time.Duration, opts ...Option) *Scheduler {
+    s := &Scheduler{
+        tasks:    tasks,
+        clock:    systemClock{},
+        interval: interval,
+        stop:     make(chan struct{}),
+        done:     make(chan struct{}),
+    }
+    for _, opt := range opts {
+        opt(s)
+    }
+    return s
+}
+
+// Start runs the scheduler in a goroutine until Stop is called.
+func (s *Scheduler) Start() {
+    go func() {
+        defer close(s.done)
+        ticker := time.NewTicker(s.interval)
+        defer ticker.Stop()
+        for {
+            select {
+            case <-ticker.C:
+                s.RunOnce()
+            case <-s.stop:
+                return
+            }
+        }
+    }()
+}
+
+// Stop halts a started scheduler and waits for the current pass to finish.
+func (s *Scheduler) Stop() {
+    s.stopOnce.Do(func() {
+        close(s.stop)
+    })
+    <-s.done
+}
+
+// RunOnce applies all deadlines that have passed at the clock's current time
+// and returns how many tasks were expired and how many claims released.
+// Only OPEN and CLAIMED tasks can have a deadline pass, so only those are
+// read. Failures are logged and the task is retried on the next pass.
+func (s *Scheduler) RunOnce() (expired int, released int) {
+    now := s.clock.Now()
+    s.each(types.TaskStatusOpen, func(task types.Task) {
+        if !task.ClaimExpired(now) {
+            return
+        }
+        if err := s.tasks.ExpireTask(task.ID, now); err != nil {
+            log.Printf("Scheduler: failed to expire task %s: %v", task.ID, err)
+            return
+        }
+        expired++
+    })
+    s.each(types.TaskStatusClaimed, func(task types.Task) {
+        if !task.SubmissionExpired(now) {
+            return
+        }
+        if err := s.tasks.ReleaseClaim(task.ID, now); err != nil {
+            log.Printf("Scheduler: failed to release claim on task %s: %v", task.ID, err)
+            return
+        }
+        released++
+    })
+    return expired, released
+}
+
+// each calls fn for every task with status, a page at a time. The cursor
+// marks a position, so tasks fn moves to another status do not shift the
+// pages that follow.
+func (s *Scheduler) each(status types.TaskStatus, fn func(task types.Task)) {
+    q := store.TaskQuery{Status: status, Sort: store.SortID, Limit: store.MaxPageSize}
+    for {
+        page, err := s.tasks.QueryTasks(q)
+        if err != nil {
+            log.Printf("Scheduler: failed to query %s tasks: %v", status, err)
+            return
+        }
+        for _, task := range page.Tasks {
+            fn(task)
+        }
+        if page.NextCursor == "" {
+            return
+        }
+        q.Cursor = page.NextCursor
+    }
+}
@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+    "testing"
+    "time"
+
+    "bounty-system/internal/client"
+    "bounty-system/internal/types"
+)
+
+type fakeClock struct {
+    now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestSchedulerExpiresAndReleases(t *testing.T) {
+    bc := client.NewBlockchainClient(client.WithBackend(client.NewSimBackend("sim-chain")))
+    wallets := bc.GetTestWallets()
+    creator := wallets[1]
+    claimer := bc.GenerateTestAddress("claimer-1")
+
+    start := time.Now()
+    claimBy := start.Add(time.Hour)
+    submitBy := start.Add(2 * time.Hour)
+    tasks := []types.Task{
+        {ID: "unclaimed", Title: "Unclaimed", Creator: creator, Bounty: "1000000", ClaimDeadline: &claimBy},
+        {ID: "no-proof", Title: "No proof", Creator: creator, Bounty: "1000000", SubmissionDeadline: &submitBy},
+        {ID: "with-proof", Title: "With proof", Creator: creator, Bounty: "1000000", SubmissionDeadline: &submitBy},
+        {ID: "no-deadline", Title: "No deadline", Creator: creator, Bounty: "1000000"},
+    }
+    for _, task := range tasks {
+        if err := bc.CreateTask(task); err != nil {
+            t.Fatalf("create %s failed: %v", task.ID, err)
+        }
+    }
+    if err := bc.ClaimTask("no-proof", claimer, ""); err != nil {
+        t.Fatalf("claim failed: %v", err)
+    }
+    if err := bc.ClaimTask("with-proof", claimer, ""); err != nil {
+        t.Fatalf("claim failed: %v", err)
+    }
+    if err := bc.SubmitProof("with-proof", claimer, "https://github.com/proof"); err != nil {
+        t.Fatalf("submit proof failed: %v", err)
+    }
+
+    clock := &fakeClock{now: start}
+    s := New(bc, time.Minute, WithClock(clock))
+
+    if expired, released := s.RunOnce(); expired != 0 || released != 0 {
+        t.Fatalf("nothing is due yet, got %d expired %d released", expired, released)
+    }
+
+    clock.now = start.Add(90 * time.Minute)
+    if expired, released := s.RunOnce(); expired != 1 || released != 0 {
+        t.Fatalf("expected one expiry, got %d expired %d released", expired, released)
+    }
+    if task := getTask(t, bc, "unclaimed"); task.Status != types.TaskStatusExpired {
+        t.Fatalf("unexpected state after expiry: %+v", task)
+    }
+    // The refund is settled in the background
+    deadline := time.Now().Add(5 * time.Second)
+    for {
+        escrow, _ := bc.GetTaskEscrow("unclaimed")
+        if escrow.Status == types.ESCROW_REFUNDED {
+            break
+        }
+        if time.Now().After(deadline) {
+            t.Fatalf("refund was not settled: %+v", escrow)
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+
+    clock.now = start.Add(3 * time.Hour)
+    if expired, released := s.RunOnce(); expired != 0 || released != 1 {
+        t.Fatalf("expected one release, got %d expired %d released", expired, released)
+    }
+    task := getTask(t, bc, "no-proof")
+    if task.Status != types.TaskStatusOpen || task.Claimer != "" || len(task.RejectedClaims) != 1 {
+        t.Fatalf("unexpected state after release: %+v", task)
+    }
+    last := task.Transitions[len(task.Transitions)-1]
+    if last.From != types.TaskStatusClaimed || last.Actor != client.ExpiryActor {
+        t.Fatalf("unexpected transition record: %+v", last)
+    }
+    if task := getTask(t, bc, "with-proof"); task.Status != types.TaskStatusClaimed {
+        t.Fatalf("claim with proof must not be released: %+v", task)
+    }
+    if task := getTask(t, bc, "no-deadline"); task.Status != types.TaskStatusOpen {
+        t.Fatalf("task without deadline must stay open: %+v", task)
+    }
+
+    // Nothing left to do on the next pass
+    if expired, released := s.RunOnce(); expired != 0 || released != 0 {
+        t.Fatalf("expected an idle pass, got %d expired %d released", expired, released)
+    }
+}
+
+func TestSchedulerStartStop(t *testing.T) {
+    bc := client.NewBlockchainClient()
+    s := New(bc, time.Millisecond)
+    s.Start()
+    time.Sleep(5 * time.Millisecond)
+    s.Stop()
+    s.Stop()
+}
+
+func getTask(t *testing.T, bc *client.BlockchainClient, id string) types.Task {
+    t.Helper()
+    tasks, err := bc.ListTasks()
+    if err != nil {
+        t.Fatalf("list failed: %v", err)
+    }
+    for _, task := range tasks {
+        if task.ID == id {
+            return task
+        }
+    }
+    t.Fatalf("task %s not found", id)
+    return types.Task{}
+}
@@ -0,0 +1,395 @@
+package store
+
+import (
+    "encoding/json"
+    "fmt"
+    "time"
+    "bounty-system/internal/types"
+    bolt "go.etcd.io/bbolt"
+)
+
+var (
+    tasksBucket   = []byte("tasks")
+    usersBucket   = []byte("users")
+    escrowsBucket = []byte("escrows")
+    proposalsBucket = []byte("proposals")
+    checkpointsBucket = []byte("checkpoints")
+    revocationsBucket = []byte("revocations")
+
+    // Task indexes, so queries can page through the tasks in their order or
+    // pick out the ones they filter for without loading all of them
+    tasksByCreatedBucket = []byte("tasks_by_created")
+    tasksByBountyBucket  = []byte("tasks_by_bounty")
+    tasksByStatusBucket  = []byte("tasks_by_status")
+    tasksByCreatorBucket = []byte("tasks_by_creator")
+    tasksByClaimerBucket = []byte("tasks_by_claimer")
+)
+
+// BoltStore persists tasks, users, escrows, proposals and indexer checkpoints in an embedded bbolt database file so
+// they survive restarts of the API server.
+type BoltStore struct {
+    db *bolt.DB
+}
+
+// OpenBoltStore opens (or creates) the database at path.
+func OpenBoltStore(path string) (*BoltStore, error) {
+    db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+    if err != nil {
+        return nil, fmt.Errorf("failed to open store %s: %v", path, err)
+    }
+
+    err = db.Update(func(tx *bolt.Tx) error {
+        for _, name := range [][]byte{tasksBucket, usersBucket, escrowsBucket, proposalsBucket, checkpointsBucket, revocationsBucket} {
+            if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+                return err
+            }
+        }
+        // Databases written before an index existed get it built once
+        for _, index := range taskIndexes {
+            if tx.Bucket(index.bucket) != nil {
+                continue
+            }
+            if err := index.build(tx); err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+    if err != nil {
+        db.Close()
+        return nil, fmt.Errorf("failed to initialize store: %v", err)
+    }
+
+    return &BoltStore{db: db}, nil
+}
+
+func (s *BoltStore) Get(id string) (types.Task, error) {
+    var task types.Task
+    err := s.db.View(func(tx *bolt.Tx) error {
+        return getJSON(tx.Bucket(tasksBucket), id, &task)
+    })
+    return task, err
+}
+
+func (s *BoltStore) Put(task types.Task) error {
+    return s.db.Update(func(tx *bolt.Tx) error {
+        _, err := putTask(tx, task)
+        return err
+    })
+}
+
+func (s *BoltStore) Create(task types.Task) error {
+    return s.db.Update(func(tx *bolt.Tx) error {
+        if tx.Bucket(tasksBucket).Get([]byte(task.ID)) != nil {
+            return ErrExists
+        }
+        _, err := putTask(tx, task)
+        return err
+    })
+}
+
+func (s *BoltStore) Delete(id string) error {
+    return s.db.Update(func(tx *bolt.Tx) error {
+        var task types.Task
+        err := getJSON(tx.Bucket(tasksBucket), id, &task)
+        if err == ErrNotFound {
+            return nil
+        }
+        if err != nil {
+            return err
+        }
+        for _, index := range taskIndexes {
+            if err := index.remove(tx, task); err != nil {
+                return err
+            }
+        }
+        return tx.Bucket(tasksBucket).Delete([]byte(id))
+    })
+}
+
+func (s *BoltStore) List() ([]types.Task, error) {
+    tasks := make([]types.Task, 0)
+    err := s.db.View(func(tx *bolt.Tx) error {
+        return tx.Bucket(tasksBucket).ForEach(func(k, v []byte) error {
+            var task types.Task
+            if err := json.Unmarshal(v, &task); err != nil {
+                return fmt.Errorf("failed to decode task %s: %v", k, err)
+            }
+            tasks = append(tasks, task)
+            return nil
+        })
+    })
+    return tasks, err
+}
+
+func (s *BoltStore) Update(id string, fn func(task *types.Task) error) (types.Task, error) {
+    var task types.Task
+    err := s.db.Update(func(tx *bolt.Tx) error {
+        if err := getJSON(tx.Bucket(tasksBucket), id, &task); err != nil {
+            return err
+        }
+        if err := fn(&task); err != nil {
+            return err
+        }
+        var err error
+        task, err = putTask(tx, task)
+        return err
+    })
+    if err != nil {
+        return types.Task{}, err
+    }
+    return task, nil
+}
+
+// Query narrows queries filtering by creator, claimer or status to the tasks
+// their indexes list and orders those in memory. Any other query pages
+// through the index of its sort order.
+func (s *BoltStore) Query(q TaskQuery) (TaskPage, error) {
+    if err := q.normalize(); err != nil {
+        return TaskPage{}, err
+    }
+    pos, err := q.decodeCursor()
+    if err != nil {
+        return TaskPage{}, err
+    }
+
+    var page TaskPage
+    err = s.db.View(func(tx *bolt.Tx) error {
+        ids, filtered := filteredIDs(tx, q)
+        if !filtered {
+            page, err = walkIndex(tx, q, pos)
+            return err
+        }
+        tasks := make([]types.Task, 0, len(ids))
+        for id := range ids {
+            var task types.Task
+            if err := getJSON(tx.Bucket(tasksBucket), id, &task); err != nil {
+                return fmt.Errorf("failed to load indexed task %s: %v", id, err)
+            }
+            tasks = append(tasks, task)
+        }
+        page, err = queryTasks(tasks, q)
+        return err
+    })
+    if err != nil {
+        return TaskPage{}, err
+    }
+    return page, nil
+}
+
+func (s *BoltStore) GetUser(address string) (types.User, error) {
+    var user types.User
+    err := s.db.View(func(tx *bolt.Tx) error {
+        return getJSON(tx.Bucket(usersBucket), address, &user)
+    })
+    return user, err
+}
+
+func (s *BoltStore) PutUser(user types.User) error {
+    return s.db.Update(func(tx *bolt.Tx) error {
+        return putJSON(tx.Bucket(usersBucket), user.Address, user)
+    })
+}
+
+func (s *BoltStore) DeleteUser(address string) error {
+    return s.db.Update(func(tx *bolt.Tx) error {
+        return tx.Bucket(usersBucket).Delete([]byte(address))
+    })
+}
+
+func (s *BoltStore) ListUsers() ([]types.User, error) {
+    users := make([]types.User, 0)
+    err := s.db.View(func(tx *bolt.Tx) error {
+        return tx.Bucket(usersBucket).ForEach(func(k, v []byte) error {
+            var user types.User
+            if err := json.Unmarshal(v, &user); err != nil {
+                return fmt.Errorf("failed to decode user %s: %v", k, err)
+            }
+            users = append(users, user)
+            return nil
+        })
+    })
+    return users, err
+}
+
+func (s *BoltStore) GetEscrow(taskID string) (types.Escrow, error) {
+    var escrow types.Escrow
+    err := s.db.View(func(tx *bolt.Tx) error {
+        return getJSON(tx.Bucket(escrowsBucket), taskID, &escrow)
+    })
+    return escrow, err
+}
+
+func (s *BoltStore) PutEscrow(escrow types.Escrow) error {
+    return s.db.Update(func(tx *bolt.Tx) error {
+        return putJSON(tx.Bucket(escrowsBucket), escrow.TaskID, escrow)
+    })
+}
+
+func (s *BoltStore) ListEscrows() ([]types.Escrow, error) {
+    escrows := make([]types.Escrow, 0)
+    err := s.db.View(func(tx *bolt.Tx) error {
+        return tx.Bucket(escrowsBucket).ForEach(func(k, v []byte) error {
+            var escrow types.Escrow
+            if err := json.Unmarshal(v, &escrow); err != nil {
+                return fmt.Errorf("failed to decode escrow %s: %v", k, err)
+            }
+            escrows = append(escrows, escrow)
+            return nil
+        })
+    })
+    return escrows, err
+}
+
+func (s *BoltStore) GetProposal(id string) (types.Proposal, error) {
+    var proposal types.Proposal
+    err := s.db.View(func(tx *bolt.Tx) error {
+        return getJSON(tx.Bucket(proposalsBucket), id, &proposal)
+    })
+    return proposal, err
+}
+
+func (s *BoltStore) PutProposal(proposal types.Proposal) error {
+    return s.db.Update(func(tx *bolt.Tx) error {
+        return putJSON(tx.Bucket(proposalsBucket), proposal.ID, proposal)
+    })
+}
+
+func (s *BoltStore) ListProposals() ([]types.Proposal, error) {
+    proposals := make([]types.Proposal, 0)
+    err := s.db.View(func(tx *bolt.Tx) error {
+        return tx.Bucket(proposalsBucket).ForEach(func(k, v []byte) error {
+            var proposal types.Proposal
+            if err := json.Unmarshal(v, &proposal); err != nil {
+                return fmt.Errorf("failed to decode proposal %s: %v", k, err)
+            }
+            proposals = append(proposals, proposal)
+            return nil
+        })
+    })
+    return proposals, err
+}
+
+func (s *BoltStore) UpdateProposal(id string, fn func(proposal *types.Proposal) error) (types.Proposal, error) {
+    var proposal types.Proposal
+    err := s.db.Update(func(tx *bolt.Tx) error {
+        bucket := tx.Bucket(proposalsBucket)
+        if err := getJSON(bucket, id, &proposal); err != nil {
+            return err
+        }
+        if err := fn(&proposal); err != nil {
+            return err
+        }
+        return putJSON(bucket, id, proposal)
+    })
+    if err != nil {
+        return types.Proposal{}, err
+    }
+    return proposal, nil
+}
+
+func (s *BoltStore) GetCheckpoint(name string) (types.Checkpoint, error) {
+    var checkpoint types.Checkpoint
+    err := s.db.View(func(tx *bolt.Tx) error {
+        return getJSON(tx.Bucket(checkpointsBucket), name, &checkpoint)
+    })
+    return checkpoint, err
+}
+
+func (s *BoltStore) PutCheckpoint(name string, checkpoint types.Checkpoint) error {
+    return s.db.Update(func(tx *bolt.Tx) error {
+        return putJSON(tx.Bucket(checkpointsBucket), name, checkpoint)
+    })
+}
+
+func (s *BoltStore) Revoke(id string, expiresAt time.Time) (bool, error) {
+    revoked := false
+    err := s.db.Update(func(tx *bolt.Tx) error {
+        bucket := tx.Bucket(revocationsBucket)
+        if bucket.Get([]byte(id)) != nil {
+            return nil
+        }
+        revoked = true
+        return putJSON(bucket, id, expiresAt)
+    })
+    return revoked, err
+}
+
+func (s *BoltStore) IsRevoked(id string) (bool, error) {
+    revoked := false
+    err := s.db.View(func(tx *bolt.Tx) error {
+        revoked = tx.Bucket(revocationsBucket).Get([]byte(id)) != nil
+        return nil
+    })
+    return revoked, err
+}
+
+func (s *BoltStore) PruneRevocations(now time.Time) error {
+    return s.db.Update(func(tx *bolt.Tx) error {
+        bucket := tx.Bucket(revocationsBucket)
+        var expired [][]byte
+        err := bucket.ForEach(func(k, v []byte) error {
+            var expiresAt time.Time
+            if err := json.Unmarshal(v, &expiresAt); err != nil {
+                return fmt.Errorf("failed to decode revocation %s: %v", k, err)
+            }
+            if expiresAt.Before(now) {
+                expired = append(expired, append([]byte(nil), k...))
+            }
+            return nil
+        })
+        if err != nil {
+            return err
+        }
+        for _, k := range expired {
+            if err := bucket.Delete(k); err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+}
+
+func (s *BoltStore) Close() error {
+    return s.db.Close()
+}
+
+// putTask stores task at the version after the stored one, moves its index
+// entries and returns the task as stored.
+func putTask(tx *bolt.Tx, task types.Task) (types.Task, error) {
+    tasks := tx.Bucket(tasksBucket)
+    var previous types.Task
+    if err := getJSON(tasks, task.ID, &previous); err == nil {
+        for _, index := range taskIndexes {
+            if err := index.remove(tx, previous); err != nil {
+                return types.Task{}, err
+            }
+        }
+    }
+    task.Version = previous.Version + 1
+    if err := putJSON(tasks, task.ID, task); err != nil {
+        return types.Task{}, err
+    }
+    for _, index := range taskIndexes {
+        if err := index.add(tx, task); err != nil {
+            return types.Task{}, err
+        }
+    }
+    return task, nil
+}
+
+func getJSON(bucket *bolt.Bucket, key string, v interface{}) error {
+    data := bucket.Get([]byte(key))
+    if data == nil {
+        return ErrNotFound
+    }
+    return json.Unmarshal(data, v)
+}
+
+func putJSON(bucket *bolt.Bucket, key string, v interface{}) error {
+    data, err := json.Marshal(v)
+    if err != nil {
+        return err
+    }
+    return bucket.Put([]byte(key), data)
+}
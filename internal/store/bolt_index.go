@@ -0,0 +1,195 @@
+package store
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "math/big"
+    "time"
+    "bounty-system/internal/types"
+    bolt "go.etcd.io/bbolt"
+)
+
+// taskIndex is a secondary index of the tasks bucket. Its keys are the
+// indexed value of a task, a zero byte and the task ID, so entries sort by
+// value and then by ID. Tasks with an empty value are left out.
+type taskIndex struct {
+    bucket []byte
+    value  func(task types.Task) string
+}
+
+var (
+    createdIndex = taskIndex{tasksByCreatedBucket, func(task types.Task) string { return createdValue(task.CreatedAt) }}
+    bountyIndex  = taskIndex{tasksByBountyBucket, func(task types.Task) string { return bountyValue(bountyOf(task)) }}
+    statusIndex  = taskIndex{tasksByStatusBucket, func(task types.Task) string { return string(task.Status) }}
+    creatorIndex = taskIndex{tasksByCreatorBucket, func(task types.Task) string { return task.Creator }}
+    claimerIndex = taskIndex{tasksByClaimerBucket, func(task types.Task) string { return task.Claimer }}
+
+    // taskIndexes are kept up to date by every write of a task.
+    taskIndexes = []taskIndex{createdIndex, bountyIndex, statusIndex, creatorIndex, claimerIndex}
+)
+
+// createdValue is a creation time in fixed width, so it sorts
+// chronologically.
+func createdValue(t time.Time) string {
+    return t.UTC().Format("20060102150405.000000000")
+}
+
+// bountyValue is a non-negative bounty as its digit count and digits, which
+// sorts like the amounts as long as they have fewer than 10000 digits.
+func bountyValue(bounty *big.Int) string {
+    digits := bounty.String()
+    return fmt.Sprintf("%04d%s", len(digits), digits)
+}
+
+// indexRange returns the index keys q's range filter on the sort field
+// allows: from lower, inclusive, up to upper, exclusive. A nil bound is open.
+func indexRange(q TaskQuery) (lower []byte, upper []byte) {
+    switch q.Sort {
+    case SortCreatedAt:
+        if !q.CreatedAfter.IsZero() {
+            lower = []byte(createdValue(q.CreatedAfter))
+        }
+        if !q.CreatedBefore.IsZero() {
+            upper = []byte(createdValue(q.CreatedBefore))
+        }
+    case SortBounty:
+        if q.MinBounty != nil && q.MinBounty.Sign() > 0 {
+            lower = []byte(bountyValue(q.MinBounty))
+        }
+        if q.MaxBounty != nil && q.MaxBounty.Sign() < 0 {
+            // Every indexed bounty is at least zero
+            upper = []byte{}
+        } else if q.MaxBounty != nil {
+            // Keys of the maximum itself continue with a zero byte
+            upper = []byte(bountyValue(q.MaxBounty) + "\x01")
+        }
+    }
+    return lower, upper
+}
+
+func (i taskIndex) key(task types.Task) []byte {
+    return []byte(i.value(task) + "\x00" + task.ID)
+}
+
+// id returns the task ID of an index key.
+func (i taskIndex) id(key []byte) string {
+    return string(key[bytes.IndexByte(key, 0)+1:])
+}
+
+func (i taskIndex) add(tx *bolt.Tx, task types.Task) error {
+    if i.value(task) == "" {
+        return nil
+    }
+    return tx.Bucket(i.bucket).Put(i.key(task), nil)
+}
+
+func (i taskIndex) remove(tx *bolt.Tx, task types.Task) error {
+    if i.value(task) == "" {
+        return nil
+    }
+    return tx.Bucket(i.bucket).Delete(i.key(task))
+}
+
+// build creates the index bucket and adds every stored task to it.
+func (i taskIndex) build(tx *bolt.Tx) error {
+    if _, err := tx.CreateBucket(i.bucket); err != nil {
+        return err
+    }
+    return tx.Bucket(tasksBucket).ForEach(func(k, v []byte) error {
+        var task types.Task
+        if err := json.Unmarshal(v, &task); err != nil {
+            return fmt.Errorf("failed to decode task %s: %v", k, err)
+        }
+        return i.add(tx, task)
+    })
+}
+
+// filteredIDs returns the IDs of the tasks matching every creator, claimer
+// and status filter of q, and false if q sets none of them.
+func filteredIDs(tx *bolt.Tx, q TaskQuery) (map[string]bool, bool) {
+    var ids map[string]bool
+    for _, filter := range []struct {
+        index taskIndex
+        value string
+    }{
+        {creatorIndex, q.Creator},
+        {claimerIndex, q.Claimer},
+        {statusIndex, string(q.Status)},
+    } {
+        if filter.value == "" {
+            continue
+        }
+        matched := make(map[string]bool)
+        prefix := []byte(filter.value + "\x00")
+        c := tx.Bucket(filter.index.bucket).Cursor()
+        for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+            if id := filter.index.id(k); ids == nil || ids[id] {
+                matched[id] = true
+            }
+        }
+        ids = matched
+    }
+    return ids, ids != nil
+}
+
+// walkIndex pages through the tasks in q's order, starting after pos. The
+// ID order needs no index of its own: the tasks bucket is keyed by ID. A
+// range filter on the sort field bounds the walk to the keys it allows.
+func walkIndex(tx *bolt.Tx, q TaskQuery, pos *types.Task) (TaskPage, error) {
+    tasks := tx.Bucket(tasksBucket)
+    keyOf := func(task types.Task) []byte { return []byte(task.ID) }
+    idOf := func(key []byte) string { return string(key) }
+    c := tasks.Cursor()
+    switch q.Sort {
+    case SortCreatedAt:
+        keyOf, idOf, c = createdIndex.key, createdIndex.id, tx.Bucket(createdIndex.bucket).Cursor()
+    case SortBounty:
+        keyOf, idOf, c = bountyIndex.key, bountyIndex.id, tx.Bucket(bountyIndex.bucket).Cursor()
+    }
+
+    lower, upper := indexRange(q)
+    next := c.Next
+    inRange := func(k []byte) bool { return upper == nil || bytes.Compare(k, upper) < 0 }
+    var k []byte
+    if q.Descending {
+        // Start below the cursor or the range end, whichever comes first
+        start := upper
+        if pos != nil && (start == nil || bytes.Compare(keyOf(*pos), start) < 0) {
+            start = keyOf(*pos)
+        }
+        if start == nil {
+            k, _ = c.Last()
+        } else if k, _ = c.Seek(start); k == nil {
+            k, _ = c.Last()
+        } else {
+            k, _ = c.Prev()
+        }
+        next = c.Prev
+        inRange = func(k []byte) bool { return lower == nil || bytes.Compare(k, lower) >= 0 }
+    } else {
+        // Start at the cursor or the range start, whichever comes last
+        start := lower
+        if pos != nil && (start == nil || bytes.Compare(keyOf(*pos), start) > 0) {
+            start = keyOf(*pos)
+        }
+        if start == nil {
+            k, _ = c.First()
+        } else {
+            k, _ = c.Seek(start)
+        }
+    }
+
+    // Collect one task past the page to know whether another page follows
+    matched := make([]types.Task, 0)
+    for ; k != nil && inRange(k) && len(matched) <= q.Limit; k, _ = next() {
+        var task types.Task
+        if err := getJSON(tasks, idOf(k), &task); err != nil {
+            return TaskPage{}, fmt.Errorf("failed to load indexed task %s: %v", idOf(k), err)
+        }
+        if q.Matches(task) && (pos == nil || q.after(task, *pos)) {
+            matched = append(matched, task)
+        }
+    }
+    return pageOf(matched, q), nil
+}
@@ -0,0 +1,281 @@
+package store
+
+import (
+    "sync"
+    "time"
+    "bounty-system/internal/types"
+)
+
+// MemoryStore keeps everything in process memory. Nothing survives a restart.
+type MemoryStore struct {
+    mu    sync.RWMutex
+    tasks   map[string]types.Task
+    users   map[string]types.User
+    escrows map[string]types.Escrow
+    proposals map[string]types.Proposal
+    checkpoints map[string]types.Checkpoint
+    revocations map[string]time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+    return &MemoryStore{
+        tasks:   make(map[string]types.Task),
+        users:   make(map[string]types.User),
+        escrows: make(map[string]types.Escrow),
+        proposals: make(map[string]types.Proposal),
+        checkpoints: make(map[string]types.Checkpoint),
+        revocations: make(map[string]time.Time),
+    }
+}
+
+func (s *MemoryStore) Get(id string) (types.Task, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    task, exists := s.tasks[id]
+    if !exists {
+        return types.Task{}, ErrNotFound
+    }
+    return task, nil
+}
+
+func (s *MemoryStore) Put(task types.Task) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    task.Version = s.tasks[task.ID].Version + 1
+    s.tasks[task.ID] = task
+    return nil
+}
+
+func (s *MemoryStore) Create(task types.Task) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.tasks[task.ID]; exists {
+        return ErrExists
+    }
+    task.Version = 1
+    s.tasks[task.ID] = task
+    return nil
+}
+
+func (s *MemoryStore) Delete(id string) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    delete(s.tasks, id)
+    return nil
+}
+
+func (s *MemoryStore) List() ([]types.Task, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    tasks := make([]types.Task, 0, len(s.tasks))
+    for _, task := range s.tasks {
+        tasks = append(tasks, task)
+    }
+    return tasks, nil
+}
+
+func (s *MemoryStore) Query(q TaskQuery) (TaskPage, error) {
+    tasks, err := s.List()
+    if err != nil {
+        return TaskPage{}, err
+    }
+    return queryTasks(tasks, q)
+}
+
+func (s *MemoryStore) Update(id string, fn func(task *types.Task) error) (types.Task, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    task, exists := s.tasks[id]
+    if !exists {
+        return types.Task{}, ErrNotFound
+    }
+    // Work on a copy so a failed precondition leaves the stored task
+    // untouched and tasks handed out earlier never change under a reader
+    task.Approvals = append([]types.Approval(nil), task.Approvals...)
+    task.RejectedClaims = append([]types.RejectedClaim(nil), task.RejectedClaims...)
+    task.Transitions = append([]types.TransitionRecord(nil), task.Transitions...)
+    task.History = append([]types.TaskEvent(nil), task.History...)
+    version := task.Version
+    if err := fn(&task); err != nil {
+        return types.Task{}, err
+    }
+    task.Version = version + 1
+    s.tasks[id] = task
+    return task, nil
+}
+
+func (s *MemoryStore) GetUser(address string) (types.User, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    user, exists := s.users[address]
+    if !exists {
+        return types.User{}, ErrNotFound
+    }
+    return user, nil
+}
+
+func (s *MemoryStore) PutUser(user types.User) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    s.users[user.Address] = user
+    return nil
+}
+
+func (s *MemoryStore) DeleteUser(address string) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    delete(s.users, address)
+    return nil
+}
+
+func (s *MemoryStore) ListUsers() ([]types.User, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    users := make([]types.User, 0, len(s.users))
+    for _, user := range s.users {
+        users = append(users, user)
+    }
+    return users, nil
+}
+
+func (s *MemoryStore) GetEscrow(taskID string) (types.Escrow, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    escrow, exists := s.escrows[taskID]
+    if !exists {
+        return types.Escrow{}, ErrNotFound
+    }
+    return escrow, nil
+}
+
+func (s *MemoryStore) PutEscrow(escrow types.Escrow) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    s.escrows[escrow.TaskID] = escrow
+    return nil
+}
+
+func (s *MemoryStore) ListEscrows() ([]types.Escrow, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    escrows := make([]types.Escrow, 0, len(s.escrows))
+    for _, escrow := range s.escrows {
+        escrows = append(escrows, escrow)
+    }
+    return escrows, nil
+}
+
+func (s *MemoryStore) GetProposal(id string) (types.Proposal, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    proposal, exists := s.proposals[id]
+    if !exists {
+        return types.Proposal{}, ErrNotFound
+    }
+    return proposal, nil
+}
+
+func (s *MemoryStore) PutProposal(proposal types.Proposal) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    s.proposals[proposal.ID] = proposal
+    return nil
+}
+
+func (s *MemoryStore) ListProposals() ([]types.Proposal, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    proposals := make([]types.Proposal, 0, len(s.proposals))
+    for _, proposal := range s.proposals {
+        proposals = append(proposals, proposal)
+    }
+    return proposals, nil
+}
+
+func (s *MemoryStore) UpdateProposal(id string, fn func(proposal *types.Proposal) error) (types.Proposal, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    proposal, exists := s.proposals[id]
+    if !exists {
+        return types.Proposal{}, ErrNotFound
+    }
+    // Work on a copy so a failed precondition leaves the stored proposal untouched
+    proposal.Votes = append([]types.Vote(nil), proposal.Votes...)
+    proposal.Audit = append([]types.ProposalEvent(nil), proposal.Audit...)
+    if err := fn(&proposal); err != nil {
+        return types.Proposal{}, err
+    }
+    s.proposals[id] = proposal
+    return proposal, nil
+}
+
+func (s *MemoryStore) GetCheckpoint(name string) (types.Checkpoint, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    checkpoint, exists := s.checkpoints[name]
+    if !exists {
+        return types.Checkpoint{}, ErrNotFound
+    }
+    return checkpoint, nil
+}
+
+func (s *MemoryStore) PutCheckpoint(name string, checkpoint types.Checkpoint) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    s.checkpoints[name] = checkpoint
+    return nil
+}
+
+func (s *MemoryStore) Close() error {
+    return nil
+}
+
+func (s *MemoryStore) Revoke(id string, expiresAt time.Time) (bool, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if _, exists := s.revocations[id]; exists {
+        return false, nil
+    }
+    s.revocations[id] = expiresAt
+    return true, nil
+}
+
+func (s *MemoryStore) IsRevoked(id string) (bool, error) {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+
+    _, exists := s.revocations[id]
+    return exists, nil
+}
+
+func (s *MemoryStore) PruneRevocations(now time.Time) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for id, expiresAt := range s.revocations {
+        if expiresAt.Before(now) {
+            delete(s.revocations, id)
+        }
+    }
+    return nil
+}
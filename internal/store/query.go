@@ -0,0 +1,218 @@
+package store
+
+import (
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "math/big"
+    "sort"
+    "strings"
+    "time"
+    "bounty-system/internal/types"
+)
+
+// Page sizes for task queries.
+const (
+    DefaultPageSize = 50
+    MaxPageSize     = 200
+)
+
+// ErrInvalidCursor is returned for a cursor that was not produced by a
+// query with the same sort order.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// TaskSort is the field task queries are ordered by. Ties are broken by task ID.
+type TaskSort string
+
+const (
+    SortCreatedAt TaskSort = "created_at"
+    SortBounty    TaskSort = "bounty"
+    SortID        TaskSort = "id"
+)
+
+// TaskQuery selects one page of tasks. Zero values leave a filter unset.
+type TaskQuery struct {
+    Creator       string
+    Claimer       string
+    Status        types.TaskStatus
+    MinBounty     *big.Int  // Inclusive
+    MaxBounty     *big.Int  // Inclusive
+    CreatedAfter  time.Time // Inclusive
+    CreatedBefore time.Time // Exclusive
+    Sort          TaskSort  // Defaults to SortCreatedAt
+    Descending    bool
+    Limit         int       // Defaults to DefaultPageSize, capped at MaxPageSize
+    Cursor        string    // NextCursor of the previous page
+}
+
+// TaskPage is one page of a task query. NextCursor is empty on the last page.
+type TaskPage struct {
+    Tasks      []types.Task `json:"tasks"`
+    NextCursor string       `json:"next_cursor,omitempty"`
+}
+
+// cursor is the position after the last task of a page, kept as the sort
+// key of that task so pages stay stable while tasks are added.
+type cursor struct {
+    Sort       TaskSort  `json:"s"`
+    Descending bool      `json:"d,omitempty"`
+    ID         string    `json:"id"`
+    CreatedAt  time.Time `json:"c,omitempty"`
+    Bounty     string    `json:"b,omitempty"`
+}
+
+// normalize fills in defaults and checks the sort field and page size.
+func (q *TaskQuery) normalize() error {
+    if q.Sort == "" {
+        q.Sort = SortCreatedAt
+    }
+    switch q.Sort {
+    case SortCreatedAt, SortBounty, SortID:
+    default:
+        return types.Invalidf("unknown sort field %q", q.Sort)
+    }
+    if q.Limit < 0 {
+        return types.Invalidf("limit must not be negative")
+    }
+    if q.Limit == 0 {
+        q.Limit = DefaultPageSize
+    }
+    if q.Limit > MaxPageSize {
+        q.Limit = MaxPageSize
+    }
+    return nil
+}
+
+// Matches reports whether task passes every filter of q.
+func (q TaskQuery) Matches(task types.Task) bool {
+    if q.Creator != "" && task.Creator != q.Creator {
+        return false
+    }
+    if q.Claimer != "" && task.Claimer != q.Claimer {
+        return false
+    }
+    if q.Status != "" && task.Status != q.Status {
+        return false
+    }
+    if q.MinBounty != nil || q.MaxBounty != nil {
+        bounty, ok := new(big.Int).SetString(task.Bounty, 10)
+        if !ok {
+            return false
+        }
+        if q.MinBounty != nil && bounty.Cmp(q.MinBounty) < 0 {
+            return false
+        }
+        if q.MaxBounty != nil && bounty.Cmp(q.MaxBounty) > 0 {
+            return false
+        }
+    }
+    if !q.CreatedAfter.IsZero() && task.CreatedAt.Before(q.CreatedAfter) {
+        return false
+    }
+    if !q.CreatedBefore.IsZero() && !task.CreatedAt.Before(q.CreatedBefore) {
+        return false
+    }
+    return true
+}
+
+// compareTasks orders a and b by field, then by ID.
+func compareTasks(a, b types.Task, field TaskSort) int {
+    switch field {
+    case SortCreatedAt:
+        if a.CreatedAt.Before(b.CreatedAt) {
+            return -1
+        }
+        if a.CreatedAt.After(b.CreatedAt) {
+            return 1
+        }
+    case SortBounty:
+        if c := bountyOf(a).Cmp(bountyOf(b)); c != 0 {
+            return c
+        }
+    }
+    return strings.Compare(a.ID, b.ID)
+}
+
+// bountyOf returns the bounty of task for sorting. An invalid or negative
+// bounty sorts as zero.
+func bountyOf(task types.Task) *big.Int {
+    bounty, ok := new(big.Int).SetString(task.Bounty, 10)
+    if !ok || bounty.Sign() < 0 {
+        return new(big.Int)
+    }
+    return bounty
+}
+
+// after reports whether task comes after the cursor position in q's order.
+func (q TaskQuery) after(task types.Task, pos types.Task) bool {
+    c := compareTasks(task, pos, q.Sort)
+    if q.Descending {
+        return c < 0
+    }
+    return c > 0
+}
+
+// decodeCursor returns the position q.Cursor points at, if any.
+func (q TaskQuery) decodeCursor() (*types.Task, error) {
+    if q.Cursor == "" {
+        return nil, nil
+    }
+    data, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+    if err != nil {
+        return nil, ErrInvalidCursor
+    }
+    var cur cursor
+    if err := json.Unmarshal(data, &cur); err != nil || cur.Sort != q.Sort || cur.Descending != q.Descending || cur.ID == "" {
+        return nil, ErrInvalidCursor
+    }
+    return &types.Task{ID: cur.ID, CreatedAt: cur.CreatedAt, Bounty: cur.Bounty}, nil
+}
+
+// encodeCursor returns the cursor resuming after task in q's order.
+func encodeCursor(task types.Task, q TaskQuery) string {
+    cur := cursor{Sort: q.Sort, Descending: q.Descending, ID: task.ID}
+    switch q.Sort {
+    case SortCreatedAt:
+        cur.CreatedAt = task.CreatedAt
+    case SortBounty:
+        cur.Bounty = task.Bounty
+    }
+    data, _ := json.Marshal(cur)
+    return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// queryTasks runs q over an unordered set of tasks. Stores without an index
+// for the requested order fall back to it.
+func queryTasks(tasks []types.Task, q TaskQuery) (TaskPage, error) {
+    if err := q.normalize(); err != nil {
+        return TaskPage{}, err
+    }
+    pos, err := q.decodeCursor()
+    if err != nil {
+        return TaskPage{}, err
+    }
+
+    matched := make([]types.Task, 0)
+    for _, task := range tasks {
+        if q.Matches(task) && (pos == nil || q.after(task, *pos)) {
+            matched = append(matched, task)
+        }
+    }
+    sort.Slice(matched, func(i, j int) bool {
+        c := compareTasks(matched[i], matched[j], q.Sort)
+        if q.Descending {
+            return c > 0
+        }
+        return c < 0
+    })
+    return pageOf(matched, q), nil
+}
+
+// pageOf cuts the first page of ordered, already filtered tasks.
+func pageOf(tasks []types.Task, q TaskQuery) TaskPage {
+    if len(tasks) <= q.Limit {
+        return TaskPage{Tasks: tasks}
+    }
+    tasks = tasks[:q.Limit]
+    return TaskPage{Tasks: tasks, NextCursor: encodeCursor(tasks[len(tasks)-1], q)}
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+    "errors"
+    "time"
+    "bounty-system/internal/types"
+)
+
+var (
+    // ErrNotFound is returned when a task, user, escrow, proposal or checkpoint does not exist in the store.
+    ErrNotFound = errors.New("not found")
+    // ErrExists is returned by Create for a task ID that is already taken.
+    ErrExists = errors.New("already exists")
+    // ErrConflict is returned by preconditions that find a task at another
+    // Version than the caller last saw.
+    ErrConflict = errors.New("task was changed concurrently")
+)
+
+// TaskStore persists tasks, including their claim state.
+// Implementations must be safe for concurrent use. Every write stamps the
+// task with the next Version, so callers can detect concurrent changes.
+type TaskStore interface {
+    Get(id string) (types.Task, error)
+    Put(task types.Task) error
+
+    // Create stores a new task atomically, returning ErrExists if a task
+    // with the same ID is already stored.
+    Create(task types.Task) error
+    Delete(id string) error
+    List() ([]types.Task, error)
+
+    // Query returns the page of tasks matching q in q's order.
+    Query(q TaskQuery) (TaskPage, error)
+
+    // Update loads the task with the given id and passes it to fn. fn acts as
+    // the precondition: if it returns an error nothing is written and the
+    // error is returned unchanged. Otherwise the modified task is stored
+    // atomically and returned. fn runs while the store is locked and must
+    // not call back into the store.
+    Update(id string, fn func(task *types.Task) error) (types.Task, error)
+}
+
+// UserStore persists wallet addresses and their roles (e.g. the admin set).
+type UserStore interface {
+    GetUser(address string) (types.User, error)
+    PutUser(user types.User) error
+    DeleteUser(address string) error
+    ListUsers() ([]types.User, error)
+}
+
+// EscrowStore persists the escrow ledger, one entry per task.
+type EscrowStore interface {
+    GetEscrow(taskID string) (types.Escrow, error)
+    PutEscrow(escrow types.Escrow) error
+    ListEscrows() ([]types.Escrow, error)
+}
+
+// ProposalStore persists admin governance proposals.
+type ProposalStore interface {
+    GetProposal(id string) (types.Proposal, error)
+    PutProposal(proposal types.Proposal) error
+    ListProposals() ([]types.Proposal, error)
+
+    // UpdateProposal has the same contract as TaskStore.Update.
+    UpdateProposal(id string, fn func(proposal *types.Proposal) error) (types.Proposal, error)
+}
+
+// CheckpointStore remembers how far each chain indexer has read, by name.
+type CheckpointStore interface {
+    GetCheckpoint(name string) (types.Checkpoint, error)
+    PutCheckpoint(name string, checkpoint types.Checkpoint) error
+}
+
+// RevocationStore remembers revoked session token IDs until the tokens
+// expire, so a revoked token stays revoked across restarts.
+type RevocationStore interface {
+    // Revoke records id as revoked until expiresAt. It reports false if id
+    // was already revoked, so a token can only be spent once.
+    Revoke(id string, expiresAt time.Time) (bool, error)
+    IsRevoked(id string) (bool, error)
+
+    // PruneRevocations forgets revocations that expired before now.
+    PruneRevocations(now time.Time) error
+}
+
+// Store is the full persistence layer shared by the blockchain client and
+// the HTTP layers.
+type Store interface {
+    TaskStore
+    UserStore
+    EscrowStore
+    ProposalStore
+    CheckpointStore
+    RevocationStore
+    Close() error
+}
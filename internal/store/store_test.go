@@ -0,0 +1,333 @@
+package store
+
+import (
+    "errors"
+    "fmt"
+    "math/big"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+    "bounty-system/internal/types"
+    bolt "go.etcd.io/bbolt"
+)
+
+func TestStoreImplementations(t *testing.T) {
+    boltStore, err := OpenBoltStore(filepath.Join(t.TempDir(), "tasks.db"))
+    if err != nil {
+        t.Fatalf("failed to open bolt store: %v", err)
+    }
+    defer boltStore.Close()
+
+    for name, s := range map[string]Store{"memory": NewMemoryStore(), "bolt": boltStore} {
+        t.Run(name, func(t *testing.T) {
+            testStore(t, s)
+        })
+    }
+}
+
+func testStore(t *testing.T, s Store) {
+    if _, err := s.Get("missing"); !errors.Is(err, ErrNotFound) {
+        t.Fatalf("expected ErrNotFound, got %v", err)
+    }
+
+    task := types.Task{ID: "task-1", Title: "Test Task", Bounty: "1000", Status: "OPEN"}
+    if err := s.Put(task); err != nil {
+        t.Fatalf("put failed: %v", err)
+    }
+
+    errNotOpen := errors.New("not open")
+    claim := func(task *types.Task) error {
+        if task.Status != "OPEN" {
+            return errNotOpen
+        }
+        task.Status = "CLAIMED"
+        task.Claimer = "claimer-1"
+        return nil
+    }
+
+    updated, err := s.Update("task-1", claim)
+    if err != nil {
+        t.Fatalf("update failed: %v", err)
+    }
+    if updated.Status != "CLAIMED" || updated.Claimer != "claimer-1" {
+        t.Fatalf("unexpected updated task: %+v", updated)
+    }
+    if stored, _ := s.Get("task-1"); updated.Version != stored.Version {
+        t.Fatalf("update returned version %d, stored version %d", updated.Version, stored.Version)
+    }
+
+    // A failed precondition must leave the stored task untouched
+    if _, err := s.Update("task-1", claim); !errors.Is(err, errNotOpen) {
+        t.Fatalf("expected precondition error, got %v", err)
+    }
+    if _, err := s.Update("missing", claim); !errors.Is(err, ErrNotFound) {
+        t.Fatalf("expected ErrNotFound, got %v", err)
+    }
+
+    tasks, err := s.List()
+    if err != nil || len(tasks) != 1 || tasks[0].Claimer != "claimer-1" {
+        t.Fatalf("unexpected task list: %+v (%v)", tasks, err)
+    }
+
+    // Every write bumps the version, whatever version the caller passes in
+    if tasks[0].Version != 2 {
+        t.Fatalf("expected version 2 after put and update, got %d", tasks[0].Version)
+    }
+    stale := tasks[0]
+    stale.Version = 1
+    if err := s.Put(stale); err != nil {
+        t.Fatalf("put failed: %v", err)
+    }
+    if task, _ := s.Get("task-1"); task.Version != 3 {
+        t.Fatalf("expected version 3, got %d", task.Version)
+    }
+
+    if err := s.Create(types.Task{ID: "task-1", Title: "Duplicate"}); !errors.Is(err, ErrExists) {
+        t.Fatalf("expected ErrExists, got %v", err)
+    }
+    if task, _ := s.Get("task-1"); task.Title != "Test Task" {
+        t.Fatalf("create overwrote an existing task: %+v", task)
+    }
+    if err := s.Create(types.Task{ID: "task-2", Title: "Created"}); err != nil {
+        t.Fatalf("create failed: %v", err)
+    }
+    if task, err := s.Get("task-2"); err != nil || task.Version != 1 {
+        t.Fatalf("unexpected created task: %+v (%v)", task, err)
+    }
+    if err := s.Delete("task-2"); err != nil {
+        t.Fatalf("delete failed: %v", err)
+    }
+
+    admin := types.User{Address: "admin-1", Role: types.ROLE_ADMIN}
+    if err := s.PutUser(admin); err != nil {
+        t.Fatalf("put user failed: %v", err)
+    }
+    if user, err := s.GetUser("admin-1"); err != nil || user != admin {
+        t.Fatalf("unexpected user: %+v (%v)", user, err)
+    }
+    if err := s.DeleteUser("admin-1"); err != nil {
+        t.Fatalf("delete user failed: %v", err)
+    }
+    if users, _ := s.ListUsers(); len(users) != 0 {
+        t.Fatalf("expected no users, got %+v", users)
+    }
+
+    proposal := types.Proposal{ID: "proposal-1", Kind: types.ProposalAddAdmin, Target: "admin-2", Status: types.ProposalPending}
+    if err := s.PutProposal(proposal); err != nil {
+        t.Fatalf("put proposal failed: %v", err)
+    }
+    vote := func(p *types.Proposal) error {
+        if p.Status != types.ProposalPending {
+            return errNotOpen
+        }
+        p.Votes = append(p.Votes, types.Vote{Voter: "admin-1", Approve: true})
+        p.Status = types.ProposalPassed
+        return nil
+    }
+    if updated, err := s.UpdateProposal("proposal-1", vote); err != nil || len(updated.Votes) != 1 {
+        t.Fatalf("unexpected proposal update: %+v (%v)", updated, err)
+    }
+    if _, err := s.UpdateProposal("proposal-1", vote); !errors.Is(err, errNotOpen) {
+        t.Fatalf("expected precondition error, got %v", err)
+    }
+    if _, err := s.GetProposal("missing"); !errors.Is(err, ErrNotFound) {
+        t.Fatalf("expected ErrNotFound, got %v", err)
+    }
+    if proposals, err := s.ListProposals(); err != nil || len(proposals) != 1 || proposals[0].Status != types.ProposalPassed {
+        t.Fatalf("unexpected proposal list: %+v (%v)", proposals, err)
+    }
+
+    if _, err := s.GetCheckpoint("indexer"); !errors.Is(err, ErrNotFound) {
+        t.Fatalf("expected ErrNotFound for a new checkpoint, got %v", err)
+    }
+    checkpoint := types.Checkpoint{Height: 42, TxIndex: 3, BlockHash: "AB12"}
+    if err := s.PutCheckpoint("indexer", checkpoint); err != nil {
+        t.Fatalf("put checkpoint failed: %v", err)
+    }
+    if got, err := s.GetCheckpoint("indexer"); err != nil || got != checkpoint {
+        t.Fatalf("unexpected checkpoint: %+v (%v)", got, err)
+    }
+
+    now := time.Now()
+    if first, err := s.Revoke("token-1", now.Add(time.Minute)); err != nil || !first {
+        t.Fatalf("expected a first revocation, got %v (%v)", first, err)
+    }
+    if first, err := s.Revoke("token-1", now.Add(time.Minute)); err != nil || first {
+        t.Fatalf("expected a repeated revocation to be reported, got %v (%v)", first, err)
+    }
+    s.Revoke("token-2", now.Add(time.Hour))
+    if err := s.PruneRevocations(now.Add(30 * time.Minute)); err != nil {
+        t.Fatalf("prune failed: %v", err)
+    }
+    if revoked, _ := s.IsRevoked("token-1"); revoked {
+        t.Fatalf("expected the expired revocation to be pruned")
+    }
+    if revoked, _ := s.IsRevoked("token-2"); !revoked {
+        t.Fatalf("expected the live revocation to be kept")
+    }
+}
+
+func TestBoltStoreSurvivesReopen(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "tasks.db")
+
+    s, err := OpenBoltStore(path)
+    if err != nil {
+        t.Fatalf("failed to open bolt store: %v", err)
+    }
+    s.Put(types.Task{ID: "task-1", Title: "Persisted", Bounty: "1000", Status: "CLAIMED", Claimer: "claimer-1"})
+    s.PutUser(types.User{Address: "admin-1", Role: types.ROLE_ADMIN})
+
+    // Databases from before an index was added get it on the next open
+    s.db.Update(func(tx *bolt.Tx) error {
+        return tx.DeleteBucket(tasksByClaimerBucket)
+    })
+    s.Close()
+
+    s, err = OpenBoltStore(path)
+    if err != nil {
+        t.Fatalf("failed to reopen bolt store: %v", err)
+    }
+    defer s.Close()
+
+    task, err := s.Get("task-1")
+    if err != nil || task.Claimer != "claimer-1" {
+        t.Fatalf("task did not survive reopen: %+v (%v)", task, err)
+    }
+    if _, err := s.GetUser("admin-1"); err != nil {
+        t.Fatalf("admin did not survive reopen: %v", err)
+    }
+    if page, err := s.Query(TaskQuery{Claimer: "claimer-1"}); err != nil || len(page.Tasks) != 1 {
+        t.Fatalf("expected the rebuilt claimer index to find task-1, got %+v (%v)", page, err)
+    }
+}
+
+func TestTaskQuery(t *testing.T) {
+    boltStore, err := OpenBoltStore(filepath.Join(t.TempDir(), "tasks.db"))
+    if err != nil {
+        t.Fatalf("failed to open bolt store: %v", err)
+    }
+    defer boltStore.Close()
+
+    for name, s := range map[string]Store{"memory": NewMemoryStore(), "bolt": boltStore} {
+        t.Run(name, func(t *testing.T) {
+            testTaskQuery(t, s)
+        })
+    }
+}
+
+func testTaskQuery(t *testing.T, s Store) {
+    base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+    bounties := []string{"500", "100", "300", "100", "900"}
+    for i, bounty := range bounties {
+        task := types.Task{
+            ID:        fmt.Sprintf("task-%d", i),
+            Title:     "Task",
+            Bounty:    bounty,
+            Creator:   fmt.Sprintf("creator-%d", i%2),
+            Status:    types.TaskStatusOpen,
+            CreatedAt: base.Add(time.Duration(i) * time.Hour),
+        }
+        if i == 2 {
+            task.Status, task.Claimer = types.TaskStatusClaimed, "claimer-1"
+        }
+        if err := s.Put(task); err != nil {
+            t.Fatalf("put failed: %v", err)
+        }
+    }
+
+    // Moving a task's creation time or bounty must move it in the ordering
+    // too; 1000 sorts after 900 although it does not as a string
+    if _, err := s.Update("task-0", func(task *types.Task) error {
+        task.CreatedAt = base.Add(10 * time.Hour)
+        task.Bounty = "1000"
+        return nil
+    }); err != nil {
+        t.Fatalf("update failed: %v", err)
+    }
+
+    ids := func(page TaskPage) []string {
+        out := make([]string, 0, len(page.Tasks))
+        for _, task := range page.Tasks {
+            out = append(out, task.ID)
+        }
+        return out
+    }
+    // all follows the cursors of q until the last page
+    all := func(q TaskQuery) []string {
+        var out []string
+        for pages := 0; ; pages++ {
+            page, err := s.Query(q)
+            if err != nil {
+                t.Fatalf("query %+v failed: %v", q, err)
+            }
+            if len(page.Tasks) > q.Limit {
+                t.Fatalf("page of %d tasks exceeds limit %d", len(page.Tasks), q.Limit)
+            }
+            out = append(out, ids(page)...)
+            if page.NextCursor == "" || pages > 10 {
+                return out
+            }
+            q.Cursor = page.NextCursor
+        }
+    }
+
+    cases := []struct {
+        name  string
+        query TaskQuery
+        want  string
+    }{
+        {"created ascending", TaskQuery{Limit: 2}, "task-1 task-2 task-3 task-4 task-0"},
+        {"created descending", TaskQuery{Limit: 2, Descending: true}, "task-0 task-4 task-3 task-2 task-1"},
+        {"bounty ascending", TaskQuery{Limit: 2, Sort: SortBounty}, "task-1 task-3 task-2 task-4 task-0"},
+        {"bounty descending", TaskQuery{Limit: 3, Sort: SortBounty, Descending: true}, "task-0 task-4 task-2 task-3 task-1"},
+        {"id descending", TaskQuery{Limit: 2, Sort: SortID, Descending: true}, "task-4 task-3 task-2 task-1 task-0"},
+        {"creator", TaskQuery{Limit: 1, Creator: "creator-1"}, "task-1 task-3"},
+        {"status by bounty", TaskQuery{Limit: 2, Status: types.TaskStatusOpen, Sort: SortBounty}, "task-1 task-3 task-4 task-0"},
+        {"status and claimer", TaskQuery{Limit: 5, Status: types.TaskStatusClaimed, Claimer: "claimer-1"}, "task-2"},
+        {"bounty range", TaskQuery{Limit: 5, Sort: SortID, MinBounty: big.NewInt(100), MaxBounty: big.NewInt(300)}, "task-1 task-2 task-3"},
+        {"created window", TaskQuery{Limit: 5, CreatedAfter: base.Add(2 * time.Hour), CreatedBefore: base.Add(4 * time.Hour)}, "task-2 task-3"},
+        {"created window descending", TaskQuery{Limit: 2, Descending: true, CreatedAfter: base.Add(time.Hour), CreatedBefore: base.Add(4 * time.Hour)}, "task-3 task-2 task-1"},
+        {"created since", TaskQuery{Limit: 2, CreatedAfter: base.Add(3 * time.Hour)}, "task-3 task-4 task-0"},
+        {"bounty range by bounty", TaskQuery{Limit: 1, Sort: SortBounty, MinBounty: big.NewInt(100), MaxBounty: big.NewInt(500)}, "task-1 task-3 task-2"},
+        {"bounty range by bounty descending", TaskQuery{Limit: 1, Sort: SortBounty, Descending: true, MinBounty: big.NewInt(300), MaxBounty: big.NewInt(900)}, "task-4 task-2"},
+        {"bounty at most", TaskQuery{Limit: 2, Sort: SortBounty, Descending: true, MaxBounty: big.NewInt(300)}, "task-2 task-3 task-1"},
+        {"negative bounty bound", TaskQuery{Limit: 5, Sort: SortBounty, MaxBounty: big.NewInt(-1)}, ""},
+    }
+    for _, tc := range cases {
+        if got := strings.Join(all(tc.query), " "); got != tc.want {
+            t.Errorf("%s: expected %s, got %s", tc.name, tc.want, got)
+        }
+    }
+
+    // A released claim must leave the claimer and status indexes
+    if _, err := s.Update("task-2", func(task *types.Task) error {
+        task.Status, task.Claimer = types.TaskStatusOpen, ""
+        return nil
+    }); err != nil {
+        t.Fatalf("update failed: %v", err)
+    }
+    if got := all(TaskQuery{Limit: 5, Claimer: "claimer-1"}); len(got) != 0 {
+        t.Errorf("expected no tasks claimed by claimer-1, got %v", got)
+    }
+    if got := strings.Join(all(TaskQuery{Limit: 5, Status: types.TaskStatusOpen, Creator: "creator-0", Sort: SortID}), " "); got != "task-0 task-2 task-4" {
+        t.Errorf("expected the released task to be OPEN again, got %s", got)
+    }
+
+    page, err := s.Query(TaskQuery{Limit: 2})
+    if err != nil {
+        t.Fatalf("query failed: %v", err)
+    }
+    if _, err := s.Query(TaskQuery{Sort: SortBounty, Cursor: page.NextCursor}); !errors.Is(err, ErrInvalidCursor) {
+        t.Errorf("expected ErrInvalidCursor for a cursor of another order, got %v", err)
+    }
+    if _, err := s.Query(TaskQuery{Descending: true, Cursor: page.NextCursor}); !errors.Is(err, ErrInvalidCursor) {
+        t.Errorf("expected ErrInvalidCursor for a cursor of the other direction, got %v", err)
+    }
+    if _, err := s.Query(TaskQuery{Cursor: "garbage!"}); !errors.Is(err, ErrInvalidCursor) {
+        t.Errorf("expected ErrInvalidCursor, got %v", err)
+    }
+    if _, err := s.Query(TaskQuery{Sort: "title"}); err == nil {
+        t.Errorf("expected an error for an unknown sort field")
+    }
+}
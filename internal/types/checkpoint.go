@@ -0,0 +1,22 @@
+package types
+
+import (
+    "time"
+)
+
+// Checkpoint records how far a chain indexer has read: every transaction up
+// to and including number TxIndex of block Height has been applied.
+// BlockHash is the hash of block Height if the source reported it, which
+// lets a resumed indexer notice that the chain no longer has that block.
+type Checkpoint struct {
+    Height    int64     `json:"height"`
+    TxIndex   uint32    `json:"tx_index"`
+    BlockHash string    `json:"block_hash,omitempty"`
+    UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Covers reports whether the transaction at index in block height was
+// already applied.
+func (c Checkpoint) Covers(height int64, index uint32) bool {
+    return height < c.Height || height == c.Height && index <= c.TxIndex
+}
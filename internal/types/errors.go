@@ -0,0 +1,19 @@
+package types
+
+import "fmt"
+
+// ValidationError is returned when a request is malformed or asks for
+// something the current state does not allow. It is the caller's mistake,
+// unlike a failing store or chain.
+type ValidationError struct {
+    Reason string
+}
+
+func (e *ValidationError) Error() string {
+    return e.Reason
+}
+
+// Invalidf returns a *ValidationError with the formatted reason.
+func Invalidf(format string, args ...interface{}) error {
+    return &ValidationError{Reason: fmt.Sprintf(format, args...)}
+}
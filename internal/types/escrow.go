@@ -0,0 +1,18 @@
+package types
+
+const (
+    ESCROW_LOCKED   = "LOCKED"
+    ESCROW_RELEASED = "RELEASED"
+    ESCROW_REFUNDED = "REFUNDED"
+)
+
+// Escrow records the bounty held for one task.
+type Escrow struct {
+    TaskID        string `json:"task_id"`
+    Depositor     string `json:"depositor"`
+    Amount        string `json:"amount"`
+    Status        string `json:"status"`
+    LockTxHash    string `json:"lock_tx_hash,omitempty"`
+    Recipient     string `json:"recipient,omitempty"` // Claimer on payout, depositor on refund
+    ReleaseTxHash string `json:"release_tx_hash,omitempty"`
+}
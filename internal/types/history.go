@@ -0,0 +1,45 @@
+package types
+
+import "time"
+
+// TaskEventType is what happened to a task in one history entry.
+type TaskEventType string
+
+const (
+    TaskEventCreated        TaskEventType = "CREATED"         // Bounty locked in escrow
+    TaskEventClaimed        TaskEventType = "CLAIMED"
+    TaskEventProofSubmitted TaskEventType = "PROOF_SUBMITTED"
+    TaskEventApproved       TaskEventType = "APPROVED"        // One approval of the current claim
+    TaskEventRejected       TaskEventType = "REJECTED"        // Claim turned down or released after its deadline
+    TaskEventPaid           TaskEventType = "PAID"            // Bounty released to the claimer
+    TaskEventCancelled      TaskEventType = "CANCELLED"
+    TaskEventExpired        TaskEventType = "EXPIRED"
+    TaskEventRefunded       TaskEventType = "REFUNDED"        // Bounty returned to the creator
+    TaskEventTxFailed       TaskEventType = "TX_FAILED"       // A transaction failed and its change was rolled back
+)
+
+// TxStatus is the state of the transaction behind a history event.
+type TxStatus string
+
+const (
+    TxPending   TxStatus = "PENDING"   // Accepted by the node, not in a block yet
+    TxConfirmed TxStatus = "CONFIRMED" // Succeeded in a block
+    TxFailed    TxStatus = "FAILED"    // Failed in its block or not included in time
+)
+
+// TaskEvent is one entry of a task's history. TxHash and TxStatus are set
+// for events backed by a chain transaction.
+type TaskEvent struct {
+    Type     TaskEventType `json:"type"`
+    Actor    string        `json:"actor"`
+    Time     time.Time     `json:"time"`
+    TxHash   string        `json:"tx_hash,omitempty"`
+    TxStatus TxStatus      `json:"tx_status,omitempty"`
+    Detail   string        `json:"detail,omitempty"`
+}
+
+// Record appends an event to the task's history.
+func (t *Task) Record(event TaskEvent) {
+    event.Time = event.Time.UTC()
+    t.History = append(t.History, event)
+}
@@ -0,0 +1,98 @@
+package types
+
+import "time"
+
+// ProposalKind is the admin-set change a proposal would make.
+type ProposalKind string
+
+const (
+    ProposalAddAdmin    ProposalKind = "ADD_ADMIN"
+    ProposalRemoveAdmin ProposalKind = "REMOVE_ADMIN"
+)
+
+// ProposalStatus is where a proposal is in its life.
+type ProposalStatus string
+
+const (
+    ProposalPending  ProposalStatus = "PENDING"  // Voting open
+    ProposalPassed   ProposalStatus = "PASSED"   // Quorum reached, waiting for the time-lock
+    ProposalRejected ProposalStatus = "REJECTED" // Enough admins voted no that it cannot pass
+    ProposalExpired  ProposalStatus = "EXPIRED"  // Voting window closed without quorum
+    ProposalExecuted ProposalStatus = "EXECUTED"
+    ProposalFailed   ProposalStatus = "FAILED" // Passed, but could no longer be applied
+)
+
+// Proposal is a vote among admins on changing the admin set.
+type Proposal struct {
+    ID            string          `json:"id"`
+    Kind          ProposalKind    `json:"kind"`
+    Target        string          `json:"target"`
+    Proposer      string          `json:"proposer"`
+    Status        ProposalStatus  `json:"status"`
+    Electorate    int             `json:"electorate"`     // Admins when the proposal was made
+    RequiredVotes int             `json:"required_votes"` // Yes votes needed to pass
+    CreatedAt     time.Time       `json:"created_at"`
+    VotingEndsAt  time.Time       `json:"voting_ends_at"`
+    ExecutableAt  *time.Time      `json:"executable_at,omitempty"` // Set once passed
+    Votes         []Vote          `json:"votes"`
+    Audit         []ProposalEvent `json:"audit"`
+}
+
+// Vote is one admin's ballot on a proposal.
+type Vote struct {
+    Voter   string    `json:"voter"`
+    Approve bool      `json:"approve"`
+    VotedAt time.Time `json:"voted_at"`
+}
+
+// ProposalEvent is one entry of a proposal's audit trail.
+type ProposalEvent struct {
+    Action string    `json:"action"`
+    Actor  string    `json:"actor"`
+    Time   time.Time `json:"time"`
+    Detail string    `json:"detail,omitempty"`
+}
+
+// Tally counts the yes and no votes cast so far.
+func (p Proposal) Tally() (yes int, no int) {
+    for _, vote := range p.Votes {
+        if vote.Approve {
+            yes++
+        } else {
+            no++
+        }
+    }
+    return yes, no
+}
+
+// Record appends an entry to the audit trail.
+func (p *Proposal) Record(action string, actor string, at time.Time, detail string) {
+    p.Audit = append(p.Audit, ProposalEvent{Action: action, Actor: actor, Time: at.UTC(), Detail: detail})
+}
+
+// Settle moves a pending proposal on once its votes or the clock decide it:
+// PASSED when enough admins approved, REJECTED when too many refused for it
+// to pass, EXPIRED when the voting window closed. It reports whether the
+// status changed.
+func (p *Proposal) Settle(now time.Time, timelock time.Duration) bool {
+    if p.Status != ProposalPending {
+        return false
+    }
+    yes, no := p.Tally()
+    switch {
+    case yes >= p.RequiredVotes:
+        executable := now.Add(timelock).UTC()
+        p.Status = ProposalPassed
+        p.ExecutableAt = &executable
+        p.Record("passed", "", now, "")
+    case no > p.Electorate-p.RequiredVotes:
+        p.Status = ProposalRejected
+        p.Record("rejected", "", now, "")
+    case !now.Before(p.VotingEndsAt):
+        p.Status = ProposalExpired
+        p.Record("expired", "", now, "")
+    default:
+        return false
+    }
+    return true
+}
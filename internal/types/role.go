@@ -1,11 +1,83 @@
 package types
 
+import (
+    "fmt"
+    "strings"
+)
+
 const (
-    ROLE_ADMIN = "ADMIN"
-    ROLE_USER  = "USER"
+    ROLE_ADMIN    = "ADMIN"    // Everything, including role management
+    ROLE_REVIEWER = "REVIEWER" // Approves and rejects claims
+    ROLE_CREATOR  = "CREATOR"  // Posts and cancels tasks, never claims
+    ROLE_AUDITOR  = "AUDITOR"  // Read-only access to roles and escrow
+    ROLE_USER     = "USER"     // Default for any wallet: posts and claims tasks
 )
 
 type User struct {
     Address string `json:"address"`
     Role    string `json:"role"`
 }
+
+// Action is something a wallet may or may not be permitted to do.
+type Action string
+
+const (
+    ActionCreateTask    Action = "create_task"
+    ActionClaimTask     Action = "claim_task"
+    ActionCancelAnyTask Action = "cancel_any_task" // Creators may always cancel their own
+    ActionApproveTask   Action = "approve_task"
+    ActionRejectClaim   Action = "reject_claim"
+    ActionManageRoles   Action = "manage_roles"
+    ActionViewRoles     Action = "view_roles"
+    ActionGovernAdmins  Action = "govern_admins" // Propose, vote on and execute admin-set changes
+    ActionViewProposals Action = "view_proposals"
+)
+
+// rolePolicy is the permission table: the roles allowed to perform each action.
+var rolePolicy = map[Action][]string{
+    ActionCreateTask:    {ROLE_ADMIN, ROLE_CREATOR, ROLE_USER},
+    ActionClaimTask:     {ROLE_ADMIN, ROLE_USER},
+    ActionCancelAnyTask: {ROLE_ADMIN},
+    ActionApproveTask:   {ROLE_ADMIN, ROLE_REVIEWER},
+    ActionRejectClaim:   {ROLE_ADMIN, ROLE_REVIEWER},
+    ActionManageRoles:   {ROLE_ADMIN},
+    ActionViewRoles:     {ROLE_ADMIN, ROLE_AUDITOR},
+    ActionGovernAdmins:  {ROLE_ADMIN},
+    ActionViewProposals: {ROLE_ADMIN, ROLE_AUDITOR},
+}
+
+// Roles lists every known role.
+func Roles() []string {
+    return []string{ROLE_ADMIN, ROLE_REVIEWER, ROLE_CREATOR, ROLE_AUDITOR, ROLE_USER}
+}
+
+// ValidRole reports whether role is one of Roles.
+func ValidRole(role string) bool {
+    for _, known := range Roles() {
+        if role == known {
+            return true
+        }
+    }
+    return false
+}
+
+// Allowed reports whether the policy table lets role perform action.
+func Allowed(role string, action Action) bool {
+    for _, allowed := range rolePolicy[action] {
+        if allowed == role {
+            return true
+        }
+    }
+    return false
+}
+
+// ForbiddenError is returned when a wallet's role does not permit an action.
+type ForbiddenError struct {
+    Address string
+    Role    string
+    Action  Action
+}
+
+func (e *ForbiddenError) Error() string {
+    return fmt.Sprintf("%s (role %s) is not allowed to %s", e.Address, e.Role, strings.ReplaceAll(string(e.Action), "_", " "))
+}
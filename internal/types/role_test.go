@@ -0,0 +1,42 @@
+package types
+
+import "testing"
+
+func TestRolePolicy(t *testing.T) {
+    for _, role := range Roles() {
+        if !ValidRole(role) {
+            t.Fatalf("%s should be a valid role", role)
+        }
+    }
+    if ValidRole("OWNER") {
+        t.Fatalf("OWNER should not be a valid role")
+    }
+
+    // Admins may do everything the policy table knows about
+    for action := range rolePolicy {
+        if !Allowed(ROLE_ADMIN, action) {
+            t.Fatalf("admin should be allowed to %s", action)
+        }
+    }
+
+    cases := []struct {
+        role    string
+        action  Action
+        allowed bool
+    }{
+        {ROLE_REVIEWER, ActionApproveTask, true},
+        {ROLE_REVIEWER, ActionManageRoles, false},
+        {ROLE_CREATOR, ActionCreateTask, true},
+        {ROLE_CREATOR, ActionClaimTask, false},
+        {ROLE_AUDITOR, ActionViewRoles, true},
+        {ROLE_AUDITOR, ActionCreateTask, false},
+        {ROLE_USER, ActionClaimTask, true},
+        {ROLE_USER, ActionApproveTask, false},
+        {"", ActionCreateTask, false},
+    }
+    for _, tc := range cases {
+        if got := Allowed(tc.role, tc.action); got != tc.allowed {
+            t.Fatalf("Allowed(%q, %s) = %v, want %v", tc.role, tc.action, got, tc.allowed)
+        }
+    }
+}
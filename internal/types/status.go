@@ -0,0 +1,84 @@
+package types
+
+import (
+    "fmt"
+    "strings"
+    "time"
+)
+
+// TaskStatus is the lifecycle state of a task.
+type TaskStatus string
+
+const (
+    TaskStatusOpen      TaskStatus = "OPEN"
+    TaskStatusClaimed   TaskStatus = "CLAIMED"
+    TaskStatusCompleted TaskStatus = "COMPLETED"
+    TaskStatusCancelled TaskStatus = "CANCELLED"
+    TaskStatusRejected  TaskStatus = "REJECTED"
+    TaskStatusExpired   TaskStatus = "EXPIRED"
+)
+
+// taskTransitions is the task state machine: the statuses each status may
+// move to. The empty status is a task that does not exist yet.
+var taskTransitions = map[TaskStatus][]TaskStatus{
+    "":                  {TaskStatusOpen},
+    TaskStatusOpen:      {TaskStatusClaimed, TaskStatusCancelled, TaskStatusExpired},
+    TaskStatusClaimed:   {TaskStatusCompleted, TaskStatusOpen, TaskStatusRejected},
+    TaskStatusCompleted: {},
+    TaskStatusCancelled: {},
+    TaskStatusRejected:  {},
+    TaskStatusExpired:   {},
+}
+
+// ParseTaskStatus converts a case-insensitive status name into a TaskStatus.
+func ParseTaskStatus(s string) (TaskStatus, error) {
+    status := TaskStatus(strings.ToUpper(strings.TrimSpace(s)))
+    if _, known := taskTransitions[status]; !known || status == "" {
+        return "", Invalidf("unknown task status %q", s)
+    }
+    return status, nil
+}
+
+// CanTransition reports whether the state machine allows from -> to.
+func CanTransition(from TaskStatus, to TaskStatus) bool {
+    for _, allowed := range taskTransitions[from] {
+        if allowed == to {
+            return true
+        }
+    }
+    return false
+}
+
+// InvalidTransitionError is returned when a mutation would move a task along
+// an edge the state machine does not have.
+type InvalidTransitionError struct {
+    TaskID string
+    From   TaskStatus
+    To     TaskStatus
+}
+
+func (e *InvalidTransitionError) Error() string {
+    return fmt.Sprintf("task %s cannot move from %s to %s", e.TaskID, e.From, e.To)
+}
+
+// TransitionRecord is one status change in a task's life.
+type TransitionRecord struct {
+    From  TaskStatus `json:"from"`
+    To    TaskStatus `json:"to"`
+    Actor string     `json:"actor"`
+    Time  time.Time  `json:"time"`
+}
+
+// Transition moves the task to status to on behalf of actor, appending the
+// change to the task's transition log. Illegal moves return an
+// *InvalidTransitionError and leave the task untouched.
+func (t *Task) Transition(to TaskStatus, actor string, at time.Time) (TransitionRecord, error) {
+    if !CanTransition(t.Status, to) {
+        return TransitionRecord{}, &InvalidTransitionError{TaskID: t.ID, From: t.Status, To: to}
+    }
+
+    record := TransitionRecord{From: t.Status, To: to, Actor: actor, Time: at.UTC()}
+    t.Status = to
+    t.Transitions = append(t.Transitions, record)
+    return record, nil
+}
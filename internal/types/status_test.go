@@ -0,0 +1,61 @@
+package types
+
+import (
+    "errors"
+    "testing"
+    "time"
+)
+
+func TestTaskTransitions(t *testing.T) {
+    task := Task{ID: "task-1"}
+    now := time.Now()
+
+    steps := []struct {
+        to    TaskStatus
+        actor string
+    }{
+        {TaskStatusOpen, "creator"},
+        {TaskStatusClaimed, "claimer"},
+        {TaskStatusOpen, "admin"},
+        {TaskStatusClaimed, "claimer"},
+        {TaskStatusCompleted, "admin"},
+    }
+    for _, step := range steps {
+        from := task.Status
+        record, err := task.Transition(step.to, step.actor, now)
+        if err != nil {
+            t.Fatalf("transition %s -> %s failed: %v", from, step.to, err)
+        }
+        if record.From != from || record.To != step.to || record.Actor != step.actor {
+            t.Fatalf("unexpected record: %+v", record)
+        }
+    }
+    if task.Status != TaskStatusCompleted || len(task.Transitions) != len(steps) {
+        t.Fatalf("unexpected task after transitions: %+v", task)
+    }
+
+    // Terminal states have no way out, and a failed move changes nothing
+    _, err := task.Transition(TaskStatusOpen, "admin", now)
+    var invalid *InvalidTransitionError
+    if !errors.As(err, &invalid) || invalid.From != TaskStatusCompleted || invalid.To != TaskStatusOpen {
+        t.Fatalf("expected InvalidTransitionError, got %v", err)
+    }
+    if task.Status != TaskStatusCompleted || len(task.Transitions) != len(steps) {
+        t.Fatalf("failed transition modified task: %+v", task)
+    }
+
+    if CanTransition(TaskStatusOpen, TaskStatusCompleted) {
+        t.Fatalf("OPEN tasks must be claimed before completion")
+    }
+}
+
+func TestParseTaskStatus(t *testing.T) {
+    if status, err := ParseTaskStatus(" claimed "); err != nil || status != TaskStatusClaimed {
+        t.Fatalf("unexpected parse result: %q %v", status, err)
+    }
+    for _, s := range []string{"", "APPROVED"} {
+        if _, err := ParseTaskStatus(s); err == nil {
+            t.Fatalf("expected error parsing %q", s)
+        }
+    }
+}
@@ -1,14 +1,69 @@
 package types
 
+import "time"
+
 type Task struct {
-    ID          string `json:"id"`
-    Title       string `json:"title"`
-    Description string `json:"description"`
-    Creator     string `json:"creator"`
-    Bounty      string `json:"bounty"`
-    Status      string `json:"status"`
-    Claimer     string `json:"claimer,omitempty"`
-    Proof       string `json:"proof,omitempty"`
+    ID                 string             `json:"id"`
+    Title              string             `json:"title"`
+    Description        string             `json:"description"`
+    Creator            string             `json:"creator"`
+    Bounty             string             `json:"bounty"`
+    Status             TaskStatus         `json:"status"`
+    Claimer            string             `json:"claimer,omitempty"`
+    Proof              string             `json:"proof,omitempty"`
+    CreatedAt          time.Time          `json:"created_at"`
+    ClaimDeadline      *time.Time         `json:"claim_deadline,omitempty"`      // OPEN tasks expire after this
+    SubmissionDeadline *time.Time         `json:"submission_deadline,omitempty"` // Claims without proof are released after this
+    RequiredApprovals  int                `json:"required_approvals,omitempty"`  // Distinct approvals needed for payout
+    Approvals          []Approval         `json:"approvals,omitempty"`           // Approvals of the current claim
+    RejectedClaims     []RejectedClaim    `json:"rejected_claims,omitempty"`
+    Transitions        []TransitionRecord `json:"transitions,omitempty"`
+    History            []TaskEvent        `json:"history,omitempty"`              // Everything that happened to the task, oldest first
+    Version            uint64             `json:"version"`                        // Bumped by the store on every write
 }
 
+// ClaimExpired reports whether the task's claim deadline has passed at now.
+func (t Task) ClaimExpired(now time.Time) bool {
+    return t.ClaimDeadline != nil && !now.Before(*t.ClaimDeadline)
+}
 
+// SubmissionExpired reports whether a claim without proof has run past the
+// task's submission deadline at now.
+func (t Task) SubmissionExpired(now time.Time) bool {
+    return t.Status == TaskStatusClaimed && t.Proof == "" &&
+        t.SubmissionDeadline != nil && !now.Before(*t.SubmissionDeadline)
+}
+
+// PendingApprovals returns how many more approvals the current claim needs.
+// The claimer's own approval never counts.
+func (t Task) PendingApprovals() int {
+    required := t.RequiredApprovals
+    if required < 1 {
+        required = 1
+    }
+    approved := 0
+    for _, approval := range t.Approvals {
+        if approval.Approver != t.Claimer {
+            approved++
+        }
+    }
+    if pending := required - approved; pending > 0 {
+        return pending
+    }
+    return 0
+}
+
+// Approval is one approver's sign-off on the current claim.
+type Approval struct {
+    Approver   string    `json:"approver"`
+    ApprovedAt time.Time `json:"approved_at"`
+}
+
+// RejectedClaim is a claim an admin turned down, kept for the task history.
+type RejectedClaim struct {
+    Claimer    string    `json:"claimer"`
+    Proof      string    `json:"proof"`
+    Reason     string    `json:"reason"`
+    RejectedBy string    `json:"rejected_by"`
+    RejectedAt time.Time `json:"rejected_at"`
+}
@@ -0,0 +1,21 @@
+#!/usr/bin/env bash
+# Regenerates the Go code in x/bounty/types from proto/bounty/v1. Needs protoc,
+# protoc-gen-gocosmos (github.com/regen-network/cosmos-proto) and
+# protoc-gen-grpc-gateway v1 on the PATH. Run it from the repository root.
+
+set -eo pipefail
+
+sdk=$(go list -m -f '{{.Dir}}' github.com/cosmos/cosmos-sdk)
+
+protoc \
+  -I proto \
+  -I "$sdk/proto" \
+  -I "$sdk/third_party/proto" \
+  --gocosmos_out=plugins=interfacetype+grpc,\
+Mgoogle/protobuf/any.proto=github.com/cosmos/cosmos-sdk/codec/types:. \
+  --grpc-gateway_out=logtostderr=true,allow_colon_final_segments=true:. \
+  proto/bounty/v1/*.proto
+
+# The files are written under their go_package path
+cp bounty-system/x/bounty/types/* x/bounty/types/
+rm -rf bounty-system
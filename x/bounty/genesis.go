@@ -0,0 +1,23 @@
+package bounty
+
+import (
+    "bounty-system/x/bounty/keeper"
+    "bounty-system/x/bounty/types"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// InitGenesis loads the tasks. Their escrow must already be in the module
+// account's balance in the bank genesis.
+func InitGenesis(ctx sdk.Context, k keeper.Keeper, gs types.GenesisState) {
+    for _, task := range gs.Tasks {
+        k.SetTask(ctx, task)
+    }
+    k.SetNextTaskID(ctx, gs.NextTaskId)
+}
+
+func ExportGenesis(ctx sdk.Context, k keeper.Keeper) *types.GenesisState {
+    return &types.GenesisState{
+        NextTaskId: k.GetNextTaskID(ctx),
+        Tasks:      k.GetAllTasks(ctx),
+    }
+}
@@ -0,0 +1,35 @@
+package bounty
+
+import (
+    "bounty-system/x/bounty/keeper"
+    "bounty-system/x/bounty/types"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// NewHandler routes the module's messages to the Msg service, for apps that
+// still reach modules through Route().
+func NewHandler(k keeper.Keeper) sdk.Handler {
+    msgServer := keeper.NewMsgServerImpl(k)
+
+    return func(ctx sdk.Context, msg sdk.Msg) (*sdk.Result, error) {
+        ctx = ctx.WithEventManager(sdk.NewEventManager())
+
+        switch msg := msg.(type) {
+        case *types.MsgCreateTask:
+            res, err := msgServer.CreateTask(sdk.WrapSDKContext(ctx), msg)
+            return sdk.WrapServiceResult(ctx, res, err)
+        case *types.MsgClaimTask:
+            res, err := msgServer.ClaimTask(sdk.WrapSDKContext(ctx), msg)
+            return sdk.WrapServiceResult(ctx, res, err)
+        case *types.MsgApproveTask:
+            res, err := msgServer.ApproveTask(sdk.WrapSDKContext(ctx), msg)
+            return sdk.WrapServiceResult(ctx, res, err)
+        case *types.MsgCancelTask:
+            res, err := msgServer.CancelTask(sdk.WrapSDKContext(ctx), msg)
+            return sdk.WrapServiceResult(ctx, res, err)
+        default:
+            return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unrecognized %s message type: %T", types.ModuleName, msg)
+        }
+    }
+}
@@ -0,0 +1,50 @@
+package keeper
+
+import (
+    "context"
+
+    "bounty-system/x/bounty/types"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    "github.com/cosmos/cosmos-sdk/types/query"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+)
+
+var _ types.QueryServer = Keeper{}
+
+func (k Keeper) Task(c context.Context, req *types.QueryTaskRequest) (*types.QueryTaskResponse, error) {
+    if req == nil {
+        return nil, status.Error(codes.InvalidArgument, "empty request")
+    }
+    task, found := k.GetTask(sdk.UnwrapSDKContext(c), req.TaskId)
+    if !found {
+        return nil, status.Errorf(codes.NotFound, "task %d not found", req.TaskId)
+    }
+    return &types.QueryTaskResponse{Task: task}, nil
+}
+
+func (k Keeper) Tasks(c context.Context, req *types.QueryTasksRequest) (*types.QueryTasksResponse, error) {
+    if req == nil {
+        return nil, status.Error(codes.InvalidArgument, "empty request")
+    }
+    ctx := sdk.UnwrapSDKContext(c)
+
+    tasks := []types.Task{}
+    pageRes, err := query.FilteredPaginate(k.tasksStore(ctx), req.Pagination, func(key, value []byte, accumulate bool) (bool, error) {
+        var task types.Task
+        if err := k.cdc.Unmarshal(value, &task); err != nil {
+            return false, err
+        }
+        if req.Status != "" && task.Status != req.Status {
+            return false, nil
+        }
+        if accumulate {
+            tasks = append(tasks, task)
+        }
+        return true, nil
+    })
+    if err != nil {
+        return nil, status.Error(codes.InvalidArgument, err.Error())
+    }
+    return &types.QueryTasksResponse{Tasks: tasks, Pagination: pageRes}, nil
+}
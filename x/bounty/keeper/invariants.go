@@ -0,0 +1,25 @@
+package keeper
+
+import (
+    "fmt"
+
+    "bounty-system/x/bounty/types"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
+    ir.RegisterRoute(types.ModuleName, "escrow", EscrowInvariant(k))
+}
+
+// EscrowInvariant checks that the module account holds every open and
+// claimed task's bounty.
+func EscrowInvariant(k Keeper) sdk.Invariant {
+    return func(ctx sdk.Context) (string, bool) {
+        escrowed := k.EscrowedCoins(ctx)
+        held := k.bank.GetAllBalances(ctx, authtypes.NewModuleAddress(types.ModuleName))
+        broken := !held.IsAllGTE(escrowed)
+        return sdk.FormatInvariant(types.ModuleName, "escrow",
+            fmt.Sprintf("\tescrowed bounties: %s\n\tmodule account balance: %s\n", escrowed, held)), broken
+    }
+}
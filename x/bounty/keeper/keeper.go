@@ -0,0 +1,227 @@
+package keeper
+
+import (
+    "fmt"
+    "strconv"
+
+    "bounty-system/x/bounty/types"
+    "github.com/cosmos/cosmos-sdk/codec"
+    "github.com/cosmos/cosmos-sdk/store/prefix"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+    "github.com/tendermint/tendermint/libs/log"
+)
+
+// Keeper owns the bounty module's tasks. Escrowed bounties are held in the
+// module account named types.ModuleName, so the app must register that
+// account with x/auth.
+type Keeper struct {
+    cdc      codec.BinaryCodec
+    storeKey sdk.StoreKey
+    bank     types.BankKeeper
+}
+
+func NewKeeper(cdc codec.BinaryCodec, storeKey sdk.StoreKey, ak types.AccountKeeper, bk types.BankKeeper) Keeper {
+    if addr := ak.GetModuleAddress(types.ModuleName); addr == nil {
+        panic(fmt.Sprintf("the %s module account has not been set", types.ModuleName))
+    }
+    return Keeper{cdc: cdc, storeKey: storeKey, bank: bk}
+}
+
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+    return ctx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetTask returns the task with id.
+func (k Keeper) GetTask(ctx sdk.Context, id uint64) (types.Task, bool) {
+    bz := ctx.KVStore(k.storeKey).Get(types.TaskKey(id))
+    if bz == nil {
+        return types.Task{}, false
+    }
+    var task types.Task
+    k.cdc.MustUnmarshal(bz, &task)
+    return task, true
+}
+
+func (k Keeper) SetTask(ctx sdk.Context, task types.Task) {
+    ctx.KVStore(k.storeKey).Set(types.TaskKey(task.Id), k.cdc.MustMarshal(&task))
+}
+
+// IterateTasks calls fn for every task in ID order until fn returns true.
+func (k Keeper) IterateTasks(ctx sdk.Context, fn func(task types.Task) (stop bool)) {
+    iterator := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.TaskKeyPrefix)
+    defer iterator.Close()
+
+    for ; iterator.Valid(); iterator.Next() {
+        var task types.Task
+        k.cdc.MustUnmarshal(iterator.Value(), &task)
+        if fn(task) {
+            break
+        }
+    }
+}
+
+func (k Keeper) GetAllTasks(ctx sdk.Context) []types.Task {
+    tasks := []types.Task{}
+    k.IterateTasks(ctx, func(task types.Task) bool {
+        tasks = append(tasks, task)
+        return false
+    })
+    return tasks
+}
+
+// GetNextTaskID returns the ID the next created task gets.
+func (k Keeper) GetNextTaskID(ctx sdk.Context) uint64 {
+    bz := ctx.KVStore(k.storeKey).Get(types.NextTaskIDKey)
+    if bz == nil {
+        return 1
+    }
+    return sdk.BigEndianToUint64(bz)
+}
+
+func (k Keeper) SetNextTaskID(ctx sdk.Context, id uint64) {
+    ctx.KVStore(k.storeKey).Set(types.NextTaskIDKey, sdk.Uint64ToBigEndian(id))
+}
+
+// EscrowedCoins sums the bounties of open and claimed tasks. The module
+// account must always hold at least this much.
+func (k Keeper) EscrowedCoins(ctx sdk.Context) sdk.Coins {
+    total := sdk.NewCoins()
+    k.IterateTasks(ctx, func(task types.Task) bool {
+        if task.Escrowed() {
+            total = total.Add(task.Bounty)
+        }
+        return false
+    })
+    return total
+}
+
+// CreateTask moves the bounty from the creator into escrow and stores a new
+// open task.
+func (k Keeper) CreateTask(ctx sdk.Context, msg *types.MsgCreateTask) (types.Task, error) {
+    creator, err := sdk.AccAddressFromBech32(msg.Creator)
+    if err != nil {
+        return types.Task{}, sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, err.Error())
+    }
+    if err := k.bank.SendCoinsFromAccountToModule(ctx, creator, types.ModuleName, sdk.NewCoins(msg.Bounty)); err != nil {
+        return types.Task{}, sdkerrors.Wrap(err, "failed to escrow bounty")
+    }
+
+    id := k.GetNextTaskID(ctx)
+    task := types.Task{
+        Id:            id,
+        Creator:       msg.Creator,
+        Title:         msg.Title,
+        Description:   msg.Description,
+        Bounty:        msg.Bounty,
+        Status:        types.StatusOpen,
+        CreatedHeight: ctx.BlockHeight(),
+        UpdatedHeight: ctx.BlockHeight(),
+    }
+    k.SetTask(ctx, task)
+    k.SetNextTaskID(ctx, id+1)
+
+    k.emit(ctx, types.EventTypeCreateTask, task)
+    k.Logger(ctx).Info("task created", "id", id, "creator", msg.Creator, "bounty", msg.Bounty.String())
+    return task, nil
+}
+
+// ClaimTask assigns an open task to the claimer along with its proof.
+func (k Keeper) ClaimTask(ctx sdk.Context, msg *types.MsgClaimTask) (types.Task, error) {
+    task, err := k.taskInStatus(ctx, msg.TaskId, types.StatusOpen)
+    if err != nil {
+        return types.Task{}, err
+    }
+    if task.Creator == msg.Claimer {
+        return types.Task{}, sdkerrors.Wrapf(types.ErrSelfClaim, "task %d", task.Id)
+    }
+
+    task.Status = types.StatusClaimed
+    task.Claimer = msg.Claimer
+    task.Proof = msg.Proof
+    task.UpdatedHeight = ctx.BlockHeight()
+    k.SetTask(ctx, task)
+
+    k.emit(ctx, types.EventTypeClaimTask, task)
+    return task, nil
+}
+
+// ApproveTask pays the escrow of a claimed task to its claimer.
+func (k Keeper) ApproveTask(ctx sdk.Context, msg *types.MsgApproveTask) (types.Task, error) {
+    task, err := k.taskInStatus(ctx, msg.TaskId, types.StatusClaimed)
+    if err != nil {
+        return types.Task{}, err
+    }
+    if task.Creator != msg.Creator {
+        return types.Task{}, sdkerrors.Wrapf(types.ErrNotCreator, "approve task %d", task.Id)
+    }
+    claimer, err := sdk.AccAddressFromBech32(task.Claimer)
+    if err != nil {
+        return types.Task{}, sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, err.Error())
+    }
+    if err := k.bank.SendCoinsFromModuleToAccount(ctx, types.ModuleName, claimer, sdk.NewCoins(task.Bounty)); err != nil {
+        return types.Task{}, sdkerrors.Wrap(err, "failed to pay bounty")
+    }
+
+    task.Status = types.StatusCompleted
+    task.UpdatedHeight = ctx.BlockHeight()
+    k.SetTask(ctx, task)
+
+    k.emit(ctx, types.EventTypeApproveTask, task)
+    k.Logger(ctx).Info("task paid", "id", task.Id, "claimer", task.Claimer, "bounty", task.Bounty.String())
+    return task, nil
+}
+
+// CancelTask refunds the escrow of an open task to its creator.
+func (k Keeper) CancelTask(ctx sdk.Context, msg *types.MsgCancelTask) (types.Task, error) {
+    task, err := k.taskInStatus(ctx, msg.TaskId, types.StatusOpen)
+    if err != nil {
+        return types.Task{}, err
+    }
+    if task.Creator != msg.Creator {
+        return types.Task{}, sdkerrors.Wrapf(types.ErrNotCreator, "cancel task %d", task.Id)
+    }
+    creator, err := sdk.AccAddressFromBech32(task.Creator)
+    if err != nil {
+        return types.Task{}, sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, err.Error())
+    }
+    if err := k.bank.SendCoinsFromModuleToAccount(ctx, types.ModuleName, creator, sdk.NewCoins(task.Bounty)); err != nil {
+        return types.Task{}, sdkerrors.Wrap(err, "failed to refund bounty")
+    }
+
+    task.Status = types.StatusCancelled
+    task.UpdatedHeight = ctx.BlockHeight()
+    k.SetTask(ctx, task)
+
+    k.emit(ctx, types.EventTypeCancelTask, task)
+    return task, nil
+}
+
+func (k Keeper) taskInStatus(ctx sdk.Context, id uint64, status string) (types.Task, error) {
+    task, found := k.GetTask(ctx, id)
+    if !found {
+        return types.Task{}, sdkerrors.Wrapf(types.ErrTaskNotFound, "task %d", id)
+    }
+    if task.Status != status {
+        return types.Task{}, sdkerrors.Wrapf(types.ErrInvalidTransition, "task %d is %s, expected %s", id, task.Status, status)
+    }
+    return task, nil
+}
+
+func (k Keeper) emit(ctx sdk.Context, eventType string, task types.Task) {
+    attributes := []sdk.Attribute{
+        sdk.NewAttribute(types.AttributeKeyTaskID, strconv.FormatUint(task.Id, 10)),
+        sdk.NewAttribute(types.AttributeKeyCreator, task.Creator),
+        sdk.NewAttribute(types.AttributeKeyBounty, task.Bounty.String()),
+        sdk.NewAttribute(types.AttributeKeyStatus, task.Status),
+    }
+    if task.Claimer != "" {
+        attributes = append(attributes, sdk.NewAttribute(types.AttributeKeyClaimer, task.Claimer))
+    }
+    ctx.EventManager().EmitEvent(sdk.NewEvent(eventType, attributes...))
+}
+
+// tasksStore is the task prefix of the module store, for paginated queries.
+func (k Keeper) tasksStore(ctx sdk.Context) prefix.Store {
+    return prefix.NewStore(ctx.KVStore(k.storeKey), types.TaskKeyPrefix)
+}
@@ -0,0 +1,161 @@
+package keeper_test
+
+import (
+    "testing"
+
+    "bounty-system/x/bounty/keeper"
+    "bounty-system/x/bounty/testutil"
+    "bounty-system/x/bounty/types"
+    "github.com/cosmos/cosmos-sdk/baseapp"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+    "github.com/cosmos/cosmos-sdk/types/query"
+    authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+const denom = "microSERVDR"
+
+func TestTaskLifecycle(t *testing.T) {
+    k, ctx, bank := testutil.Keeper(t)
+    creator, claimer := testutil.Address("creator"), testutil.Address("claimer")
+    escrow := authtypes.NewModuleAddress(types.ModuleName)
+    bank.Fund(creator, sdk.NewCoins(sdk.NewInt64Coin(denom, 5000)))
+
+    task, err := k.CreateTask(ctx, types.NewMsgCreateTask(creator, "Fix bug", "details", sdk.NewInt64Coin(denom, 1000)))
+    if err != nil {
+        t.Fatalf("create failed: %v", err)
+    }
+    if task.Id != 1 || task.Status != types.StatusOpen || k.GetNextTaskID(ctx) != 2 {
+        t.Fatalf("unexpected task %+v, next ID %d", task, k.GetNextTaskID(ctx))
+    }
+    if got := bank.GetAllBalances(ctx, escrow); !got.IsEqual(sdk.NewCoins(sdk.NewInt64Coin(denom, 1000))) {
+        t.Fatalf("expected the bounty in escrow, module holds %s", got)
+    }
+
+    if _, err := k.ClaimTask(ctx, types.NewMsgClaimTask(creator, task.Id, "proof")); !sdkerrors.IsOf(err, types.ErrSelfClaim) {
+        t.Fatalf("expected ErrSelfClaim, got %v", err)
+    }
+    if _, err := k.ClaimTask(ctx, types.NewMsgClaimTask(claimer, task.Id, "https://github.com/pr/1")); err != nil {
+        t.Fatalf("claim failed: %v", err)
+    }
+    if _, err := k.CancelTask(ctx, types.NewMsgCancelTask(creator, task.Id)); !sdkerrors.IsOf(err, types.ErrInvalidTransition) {
+        t.Fatalf("expected ErrInvalidTransition cancelling a claimed task, got %v", err)
+    }
+    if _, err := k.ApproveTask(ctx, types.NewMsgApproveTask(claimer, task.Id)); !sdkerrors.IsOf(err, types.ErrNotCreator) {
+        t.Fatalf("expected ErrNotCreator, got %v", err)
+    }
+
+    paid, err := k.ApproveTask(ctx, types.NewMsgApproveTask(creator, task.Id))
+    if err != nil {
+        t.Fatalf("approve failed: %v", err)
+    }
+    if paid.Status != types.StatusCompleted || paid.Claimer != claimer.String() {
+        t.Fatalf("unexpected paid task %+v", paid)
+    }
+    if got := bank.GetAllBalances(ctx, claimer); !got.IsEqual(sdk.NewCoins(sdk.NewInt64Coin(denom, 1000))) {
+        t.Fatalf("expected the claimer to be paid, has %s", got)
+    }
+    if !bank.GetAllBalances(ctx, escrow).IsZero() {
+        t.Fatalf("escrow not released: %s", bank.GetAllBalances(ctx, escrow))
+    }
+    if _, err := k.ApproveTask(ctx, types.NewMsgApproveTask(creator, task.Id)); !sdkerrors.IsOf(err, types.ErrInvalidTransition) {
+        t.Fatalf("expected a second approval to fail, got %v", err)
+    }
+
+    var approved bool
+    for _, event := range ctx.EventManager().Events() {
+        if event.Type == types.EventTypeApproveTask {
+            approved = true
+        }
+    }
+    if !approved {
+        t.Errorf("no %s event emitted", types.EventTypeApproveTask)
+    }
+}
+
+func TestCancelAndEscrow(t *testing.T) {
+    k, ctx, bank := testutil.Keeper(t)
+    creator, other := testutil.Address("creator"), testutil.Address("other")
+    bank.Fund(creator, sdk.NewCoins(sdk.NewInt64Coin(denom, 1500)))
+
+    first, _ := k.CreateTask(ctx, types.NewMsgCreateTask(creator, "First", "", sdk.NewInt64Coin(denom, 1000)))
+    if _, err := k.CreateTask(ctx, types.NewMsgCreateTask(creator, "Too big", "", sdk.NewInt64Coin(denom, 1000))); !sdkerrors.IsOf(err, sdkerrors.ErrInsufficientFunds) {
+        t.Fatalf("expected ErrInsufficientFunds, got %v", err)
+    }
+    if _, found := k.GetTask(ctx, 2); found {
+        t.Fatalf("failed create left a task behind")
+    }
+
+    if _, err := k.CancelTask(ctx, types.NewMsgCancelTask(other, first.Id)); !sdkerrors.IsOf(err, types.ErrNotCreator) {
+        t.Fatalf("expected ErrNotCreator, got %v", err)
+    }
+    if _, err := k.CancelTask(ctx, types.NewMsgCancelTask(creator, first.Id)); err != nil {
+        t.Fatalf("cancel failed: %v", err)
+    }
+    if got := bank.GetAllBalances(ctx, creator); !got.IsEqual(sdk.NewCoins(sdk.NewInt64Coin(denom, 1500))) {
+        t.Fatalf("expected a full refund, creator has %s", got)
+    }
+    if _, err := k.CancelTask(ctx, types.NewMsgCancelTask(creator, 99)); !sdkerrors.IsOf(err, types.ErrTaskNotFound) {
+        t.Fatalf("expected ErrTaskNotFound, got %v", err)
+    }
+
+    if msg, broken := keeper.EscrowInvariant(k)(ctx); broken {
+        t.Fatalf("invariant broken: %s", msg)
+    }
+    k.SetTask(ctx, types.Task{Id: 7, Creator: creator.String(), Bounty: sdk.NewInt64Coin(denom, 1), Status: types.StatusOpen})
+    if _, broken := keeper.EscrowInvariant(k)(ctx); !broken {
+        t.Fatalf("expected the invariant to catch an unfunded task")
+    }
+}
+
+func TestGRPCQueries(t *testing.T) {
+    k, ctx, bank := testutil.Keeper(t)
+    creator, claimer := testutil.Address("creator"), testutil.Address("claimer")
+    bank.Fund(creator, sdk.NewCoins(sdk.NewInt64Coin(denom, 10000)))
+    for i := 0; i < 5; i++ {
+        if _, err := k.CreateTask(ctx, types.NewMsgCreateTask(creator, "Task", "", sdk.NewInt64Coin(denom, 100))); err != nil {
+            t.Fatalf("create failed: %v", err)
+        }
+    }
+    k.ClaimTask(ctx, types.NewMsgClaimTask(claimer, 2, "proof"))
+    k.ClaimTask(ctx, types.NewMsgClaimTask(claimer, 4, "proof"))
+
+    // Go through the query router so requests and responses are encoded
+    // the way a node would
+    _, registry := testutil.Codec()
+    helper := baseapp.NewQueryServerTestHelper(ctx, registry)
+    types.RegisterQueryServer(helper, k)
+    client := types.NewQueryClient(helper)
+    goCtx := sdk.WrapSDKContext(ctx)
+
+    res, err := client.Task(goCtx, &types.QueryTaskRequest{TaskId: 2})
+    if err != nil {
+        t.Fatalf("task query failed: %v", err)
+    }
+    if res.Task.Claimer != claimer.String() || res.Task.Bounty.Amount.Int64() != 100 {
+        t.Fatalf("unexpected task %+v", res.Task)
+    }
+    if _, err := client.Task(goCtx, &types.QueryTaskRequest{TaskId: 42}); err == nil {
+        t.Fatalf("expected an error for an unknown task")
+    }
+
+    page, err := client.Tasks(goCtx, &types.QueryTasksRequest{Pagination: &query.PageRequest{Limit: 3}})
+    if err != nil {
+        t.Fatalf("tasks query failed: %v", err)
+    }
+    if len(page.Tasks) != 3 || page.Tasks[0].Id != 1 || page.Pagination.NextKey == nil {
+        t.Fatalf("unexpected first page %+v", page)
+    }
+    rest, err := client.Tasks(goCtx, &types.QueryTasksRequest{Pagination: &query.PageRequest{Key: page.Pagination.NextKey}})
+    if err != nil || len(rest.Tasks) != 2 || rest.Tasks[0].Id != 4 {
+        t.Fatalf("unexpected second page %+v (%v)", rest, err)
+    }
+
+    claimed, err := client.Tasks(goCtx, &types.QueryTasksRequest{Status: types.StatusClaimed})
+    if err != nil {
+        t.Fatalf("filtered query failed: %v", err)
+    }
+    if len(claimed.Tasks) != 2 || claimed.Tasks[0].Id != 2 || claimed.Tasks[1].Id != 4 {
+        t.Fatalf("expected tasks 2 and 4, got %+v", claimed.Tasks)
+    }
+}
@@ -0,0 +1,48 @@
+package keeper
+
+import (
+    "context"
+
+    "bounty-system/x/bounty/types"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+type msgServer struct {
+    Keeper
+}
+
+var _ types.MsgServer = msgServer{}
+
+// NewMsgServerImpl serves the bounty.v1.Msg service with k.
+func NewMsgServerImpl(k Keeper) types.MsgServer {
+    return msgServer{Keeper: k}
+}
+
+func (k msgServer) CreateTask(c context.Context, msg *types.MsgCreateTask) (*types.MsgCreateTaskResponse, error) {
+    task, err := k.Keeper.CreateTask(sdk.UnwrapSDKContext(c), msg)
+    if err != nil {
+        return nil, err
+    }
+    return &types.MsgCreateTaskResponse{TaskId: task.Id}, nil
+}
+
+func (k msgServer) ClaimTask(c context.Context, msg *types.MsgClaimTask) (*types.MsgClaimTaskResponse, error) {
+    if _, err := k.Keeper.ClaimTask(sdk.UnwrapSDKContext(c), msg); err != nil {
+        return nil, err
+    }
+    return &types.MsgClaimTaskResponse{}, nil
+}
+
+func (k msgServer) ApproveTask(c context.Context, msg *types.MsgApproveTask) (*types.MsgApproveTaskResponse, error) {
+    if _, err := k.Keeper.ApproveTask(sdk.UnwrapSDKContext(c), msg); err != nil {
+        return nil, err
+    }
+    return &types.MsgApproveTaskResponse{}, nil
+}
+
+func (k msgServer) CancelTask(c context.Context, msg *types.MsgCancelTask) (*types.MsgCancelTaskResponse, error) {
+    if _, err := k.Keeper.CancelTask(sdk.UnwrapSDKContext(c), msg); err != nil {
+        return nil, err
+    }
+    return &types.MsgCancelTaskResponse{}, nil
+}
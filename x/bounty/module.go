@@ -0,0 +1,129 @@
+// Package bounty is a Cosmos SDK v0.45 module that keeps bounty tasks and
+// their escrow on-chain. Apps wire it up with:
+//
+//  maccPerms[types.ModuleName] = nil
+//  app.BountyKeeper = keeper.NewKeeper(appCodec, keys[types.StoreKey], app.AccountKeeper, app.BankKeeper)
+//  bounty.NewAppModule(appCodec, app.BountyKeeper)
+//
+// The API server in cmd/api does not send these messages. It has to work on
+// chains without this module, such as the public testnet, so it locks
+// bounties with MsgSend to its escrow account and records tasks in memos.
+// Its tasks also carry what the module does not model: string IDs, roles,
+// approval quorums and deadlines. The indexer reads both kinds of
+// transaction, so tasks created with MsgCreateTask show up in the API.
+package bounty
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+
+    "bounty-system/x/bounty/keeper"
+    "bounty-system/x/bounty/types"
+    "github.com/cosmos/cosmos-sdk/client"
+    "github.com/cosmos/cosmos-sdk/codec"
+    codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    "github.com/cosmos/cosmos-sdk/types/module"
+    "github.com/gorilla/mux"
+    "github.com/grpc-ecosystem/grpc-gateway/runtime"
+    "github.com/spf13/cobra"
+    abci "github.com/tendermint/tendermint/abci/types"
+)
+
+var (
+    _ module.AppModule      = AppModule{}
+    _ module.AppModuleBasic = AppModuleBasic{}
+)
+
+type AppModuleBasic struct {
+    cdc codec.Codec
+}
+
+func (AppModuleBasic) Name() string {
+    return types.ModuleName
+}
+
+func (AppModuleBasic) RegisterLegacyAminoCodec(cdc *codec.LegacyAmino) {
+    types.RegisterLegacyAminoCodec(cdc)
+}
+
+func (AppModuleBasic) RegisterInterfaces(registry codectypes.InterfaceRegistry) {
+    types.RegisterInterfaces(registry)
+}
+
+func (AppModuleBasic) DefaultGenesis(cdc codec.JSONCodec) json.RawMessage {
+    return cdc.MustMarshalJSON(types.DefaultGenesis())
+}
+
+func (AppModuleBasic) ValidateGenesis(cdc codec.JSONCodec, config client.TxEncodingConfig, bz json.RawMessage) error {
+    var gs types.GenesisState
+    if err := cdc.UnmarshalJSON(bz, &gs); err != nil {
+        return fmt.Errorf("failed to unmarshal %s genesis state: %v", types.ModuleName, err)
+    }
+    return gs.Validate()
+}
+
+// There is no legacy REST or CLI; the module is reached through gRPC, its
+// gateway and signed transactions.
+func (AppModuleBasic) RegisterRESTRoutes(client.Context, *mux.Router) {}
+
+func (AppModuleBasic) RegisterGRPCGatewayRoutes(clientCtx client.Context, mux *runtime.ServeMux) {
+    if err := types.RegisterQueryHandlerClient(context.Background(), mux, types.NewQueryClient(clientCtx)); err != nil {
+        panic(err)
+    }
+}
+
+func (AppModuleBasic) GetTxCmd() *cobra.Command { return nil }
+
+func (AppModuleBasic) GetQueryCmd() *cobra.Command { return nil }
+
+type AppModule struct {
+    AppModuleBasic
+    keeper keeper.Keeper
+}
+
+func NewAppModule(cdc codec.Codec, k keeper.Keeper) AppModule {
+    return AppModule{AppModuleBasic: AppModuleBasic{cdc: cdc}, keeper: k}
+}
+
+func (am AppModule) RegisterInvariants(ir sdk.InvariantRegistry) {
+    keeper.RegisterInvariants(ir, am.keeper)
+}
+
+func (am AppModule) Route() sdk.Route {
+    return sdk.NewRoute(types.RouterKey, NewHandler(am.keeper))
+}
+
+func (AppModule) QuerierRoute() string {
+    return types.QuerierRoute
+}
+
+// LegacyQuerierHandler returns nil: queries go through the gRPC service.
+func (AppModule) LegacyQuerierHandler(*codec.LegacyAmino) sdk.Querier {
+    return nil
+}
+
+func (am AppModule) RegisterServices(cfg module.Configurator) {
+    types.RegisterMsgServer(cfg.MsgServer(), keeper.NewMsgServerImpl(am.keeper))
+    types.RegisterQueryServer(cfg.QueryServer(), am.keeper)
+}
+
+func (am AppModule) InitGenesis(ctx sdk.Context, cdc codec.JSONCodec, bz json.RawMessage) []abci.ValidatorUpdate {
+    var gs types.GenesisState
+    cdc.MustUnmarshalJSON(bz, &gs)
+    InitGenesis(ctx, am.keeper, gs)
+    return []abci.ValidatorUpdate{}
+}
+
+func (am AppModule) ExportGenesis(ctx sdk.Context, cdc codec.JSONCodec) json.RawMessage {
+    return cdc.MustMarshalJSON(ExportGenesis(ctx, am.keeper))
+}
+
+func (AppModule) ConsensusVersion() uint64 { return 1 }
+
+func (AppModule) BeginBlock(sdk.Context, abci.RequestBeginBlock) {}
+
+func (AppModule) EndBlock(sdk.Context, abci.RequestEndBlock) []abci.ValidatorUpdate {
+    return []abci.ValidatorUpdate{}
+}
@@ -0,0 +1,100 @@
+package bounty
+
+import (
+    "testing"
+
+    "bounty-system/x/bounty/testutil"
+    "bounty-system/x/bounty/types"
+    "github.com/cosmos/cosmos-sdk/baseapp"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+    "github.com/cosmos/cosmos-sdk/types/module"
+    banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+func TestHandler(t *testing.T) {
+    k, ctx, bank := testutil.Keeper(t)
+    creator := testutil.Address("creator")
+    bank.Fund(creator, sdk.NewCoins(sdk.NewInt64Coin("microSERVDR", 1000)))
+    handler := NewAppModule(nil, k).Route().Handler()
+
+    res, err := handler(ctx, types.NewMsgCreateTask(creator, "Fix bug", "", sdk.NewInt64Coin("microSERVDR", 400)))
+    if err != nil {
+        t.Fatalf("create failed: %v", err)
+    }
+    if len(res.Events) != 1 || res.Events[0].Type != types.EventTypeCreateTask {
+        t.Fatalf("expected a single %s event, got %+v", types.EventTypeCreateTask, res.Events)
+    }
+    if _, err := handler(ctx, types.NewMsgCancelTask(creator, 1)); err != nil {
+        t.Fatalf("cancel failed: %v", err)
+    }
+    if _, err := handler(ctx, &banktypes.MsgSend{}); !sdkerrors.IsOf(err, sdkerrors.ErrUnknownRequest) {
+        t.Fatalf("expected ErrUnknownRequest for a foreign message, got %v", err)
+    }
+}
+
+func TestGenesisRoundTrip(t *testing.T) {
+    k, ctx, bank := testutil.Keeper(t)
+    creator, claimer := testutil.Address("creator"), testutil.Address("claimer")
+    bank.Fund(creator, sdk.NewCoins(sdk.NewInt64Coin("microSERVDR", 1000)))
+    k.CreateTask(ctx, types.NewMsgCreateTask(creator, "Open", "", sdk.NewInt64Coin("microSERVDR", 100)))
+    k.CreateTask(ctx, types.NewMsgCreateTask(creator, "Claimed", "", sdk.NewInt64Coin("microSERVDR", 200)))
+    k.CreateTask(ctx, types.NewMsgCreateTask(creator, "Cancelled", "", sdk.NewInt64Coin("microSERVDR", 300)))
+    k.ClaimTask(ctx, types.NewMsgClaimTask(claimer, 2, "proof"))
+    k.CancelTask(ctx, types.NewMsgCancelTask(creator, 3))
+
+    cdc, _ := testutil.Codec()
+    module := NewAppModule(cdc, k)
+    exported := module.ExportGenesis(ctx, cdc)
+    if err := module.ValidateGenesis(cdc, nil, exported); err != nil {
+        t.Fatalf("exported genesis is invalid: %v\n%s", err, exported)
+    }
+
+    imported, importCtx, _ := testutil.Keeper(t)
+    NewAppModule(cdc, imported).InitGenesis(importCtx, cdc, exported)
+    if got := ExportGenesis(importCtx, imported); got.NextTaskId != 4 || len(got.Tasks) != 3 {
+        t.Fatalf("unexpected imported state %+v", got)
+    }
+    for _, task := range k.GetAllTasks(ctx) {
+        got, found := imported.GetTask(importCtx, task.Id)
+        if !found || got.String() != task.String() {
+            t.Fatalf("task %d changed in the round trip: %v -> %v", task.Id, task, got)
+        }
+    }
+    if !imported.EscrowedCoins(importCtx).IsEqual(sdk.NewCoins(sdk.NewInt64Coin("microSERVDR", 300))) {
+        t.Fatalf("unexpected escrow after import: %s", imported.EscrowedCoins(importCtx))
+    }
+
+    if err := module.ValidateGenesis(cdc, nil, module.DefaultGenesis(cdc)); err != nil {
+        t.Fatalf("default genesis is invalid: %v", err)
+    }
+}
+
+func TestMsgService(t *testing.T) {
+    k, ctx, bank := testutil.Keeper(t)
+    creator := testutil.Address("creator")
+    bank.Fund(creator, sdk.NewCoins(sdk.NewInt64Coin("microSERVDR", 1000)))
+
+    // Registering the service checks every Msg type is in the interface registry
+    _, registry := testutil.Codec()
+    router := baseapp.NewMsgServiceRouter()
+    router.SetInterfaceRegistry(registry)
+    NewAppModule(nil, k).RegisterServices(module.NewConfigurator(nil, router, baseapp.NewGRPCQueryRouter()))
+
+    msg := types.NewMsgCreateTask(creator, "Fix bug", "", sdk.NewInt64Coin("microSERVDR", 400))
+    res, err := router.Handler(msg)(ctx, msg)
+    if err != nil {
+        t.Fatalf("create failed: %v", err)
+    }
+    var created types.MsgCreateTaskResponse
+    if err := created.Unmarshal(res.Data); err != nil || created.TaskId != 1 {
+        t.Fatalf("expected task 1 in the response, got %+v (%v)", created, err)
+    }
+    cancel := types.NewMsgCancelTask(creator, created.TaskId)
+    if _, err := router.Handler(cancel)(ctx, cancel); err != nil {
+        t.Fatalf("cancel failed: %v", err)
+    }
+    if task, _ := k.GetTask(ctx, 1); task.Status != types.StatusCancelled {
+        t.Fatalf("expected the task to be cancelled, got %s", task.Status)
+    }
+}
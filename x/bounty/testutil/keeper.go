@@ -0,0 +1,86 @@
+// Package testutil sets up a bounty keeper on an in-memory store with a fake
+// bank for tests.
+package testutil
+
+import (
+    "fmt"
+    "testing"
+
+    "bounty-system/x/bounty/keeper"
+    "bounty-system/x/bounty/types"
+    "github.com/cosmos/cosmos-sdk/codec"
+    codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+    "github.com/cosmos/cosmos-sdk/store"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+    authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+    "github.com/tendermint/tendermint/libs/log"
+    tmproto "github.com/tendermint/tendermint/proto/tendermint/types"
+    dbm "github.com/tendermint/tm-db"
+)
+
+// Bank is a fake x/bank keeping balances in a map.
+type Bank struct {
+    balances map[string]sdk.Coins
+}
+
+func (b *Bank) Fund(addr sdk.AccAddress, coins sdk.Coins) {
+    b.balances[addr.String()] = b.balances[addr.String()].Add(coins...)
+}
+
+func (b *Bank) GetAllBalances(ctx sdk.Context, addr sdk.AccAddress) sdk.Coins {
+    return b.balances[addr.String()]
+}
+
+func (b *Bank) SendCoinsFromAccountToModule(ctx sdk.Context, senderAddr sdk.AccAddress, recipientModule string, amt sdk.Coins) error {
+    return b.send(senderAddr, authtypes.NewModuleAddress(recipientModule), amt)
+}
+
+func (b *Bank) SendCoinsFromModuleToAccount(ctx sdk.Context, senderModule string, recipientAddr sdk.AccAddress, amt sdk.Coins) error {
+    return b.send(authtypes.NewModuleAddress(senderModule), recipientAddr, amt)
+}
+
+func (b *Bank) send(from, to sdk.AccAddress, amt sdk.Coins) error {
+    balance, negative := b.balances[from.String()].SafeSub(amt)
+    if negative {
+        return sdkerrors.Wrapf(sdkerrors.ErrInsufficientFunds, "%s is smaller than %s", b.balances[from.String()], amt)
+    }
+    b.balances[from.String()] = balance
+    b.balances[to.String()] = b.balances[to.String()].Add(amt...)
+    return nil
+}
+
+type accounts struct{}
+
+func (accounts) GetModuleAddress(name string) sdk.AccAddress {
+    return authtypes.NewModuleAddress(name)
+}
+
+// Codec returns a proto codec with the bounty messages registered.
+func Codec() (*codec.ProtoCodec, codectypes.InterfaceRegistry) {
+    registry := codectypes.NewInterfaceRegistry()
+    types.RegisterInterfaces(registry)
+    return codec.NewProtoCodec(registry), registry
+}
+
+// Keeper returns a keeper on a fresh in-memory store at block height 1.
+func Keeper(t testing.TB) (keeper.Keeper, sdk.Context, *Bank) {
+    key := sdk.NewKVStoreKey(types.StoreKey)
+    db := dbm.NewMemDB()
+    ms := store.NewCommitMultiStore(db)
+    ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+    if err := ms.LoadLatestVersion(); err != nil {
+        t.Fatalf("failed to load store: %v", err)
+    }
+
+    cdc, _ := Codec()
+    bank := &Bank{balances: make(map[string]sdk.Coins)}
+    k := keeper.NewKeeper(cdc, key, accounts{}, bank)
+    ctx := sdk.NewContext(ms, tmproto.Header{Height: 1}, false, log.NewNopLogger())
+    return k, ctx, bank
+}
+
+// Address returns a deterministic test address.
+func Address(name string) sdk.AccAddress {
+    return authtypes.NewModuleAddress(fmt.Sprintf("test-%s", name))
+}
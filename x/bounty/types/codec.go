@@ -0,0 +1,39 @@
+package types
+
+import (
+    "github.com/cosmos/cosmos-sdk/codec"
+    codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+    cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    "github.com/cosmos/cosmos-sdk/types/msgservice"
+)
+
+var (
+    amino = codec.NewLegacyAmino()
+
+    // ModuleCdc encodes the amino JSON sign bytes of the module's messages.
+    ModuleCdc = codec.NewAminoCodec(amino)
+)
+
+func init() {
+    RegisterLegacyAminoCodec(amino)
+    cryptocodec.RegisterCrypto(amino)
+    amino.Seal()
+}
+
+func RegisterLegacyAminoCodec(cdc *codec.LegacyAmino) {
+    cdc.RegisterConcrete(&MsgCreateTask{}, "bounty/MsgCreateTask", nil)
+    cdc.RegisterConcrete(&MsgClaimTask{}, "bounty/MsgClaimTask", nil)
+    cdc.RegisterConcrete(&MsgApproveTask{}, "bounty/MsgApproveTask", nil)
+    cdc.RegisterConcrete(&MsgCancelTask{}, "bounty/MsgCancelTask", nil)
+}
+
+func RegisterInterfaces(registry codectypes.InterfaceRegistry) {
+    registry.RegisterImplementations((*sdk.Msg)(nil),
+        &MsgCreateTask{},
+        &MsgClaimTask{},
+        &MsgApproveTask{},
+        &MsgCancelTask{},
+    )
+    msgservice.RegisterMsgServiceDesc(registry, &_Msg_serviceDesc)
+}
@@ -0,0 +1,12 @@
+package types
+
+import (
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var (
+    ErrTaskNotFound      = sdkerrors.Register(ModuleName, 2, "task not found")
+    ErrInvalidTransition = sdkerrors.Register(ModuleName, 3, "invalid task status transition")
+    ErrNotCreator        = sdkerrors.Register(ModuleName, 4, "only the task creator may do this")
+    ErrSelfClaim         = sdkerrors.Register(ModuleName, 5, "creator cannot claim own task")
+)
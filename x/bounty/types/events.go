@@ -0,0 +1,16 @@
+package types
+
+// Event types carry the same names as the memo actions the client used to
+// write, so indexers can read either.
+const (
+    EventTypeCreateTask  = "create_task"
+    EventTypeClaimTask   = "claim_task"
+    EventTypeApproveTask = "approve_task"
+    EventTypeCancelTask  = "cancel_task"
+
+    AttributeKeyTaskID  = "task_id"
+    AttributeKeyCreator = "creator"
+    AttributeKeyClaimer = "claimer"
+    AttributeKeyBounty  = "bounty"
+    AttributeKeyStatus  = "status"
+)
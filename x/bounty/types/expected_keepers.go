@@ -0,0 +1,17 @@
+package types
+
+import (
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// AccountKeeper is the part of x/auth the bounty keeper uses.
+type AccountKeeper interface {
+    GetModuleAddress(name string) sdk.AccAddress
+}
+
+// BankKeeper is the part of x/bank the bounty keeper uses to move escrow.
+type BankKeeper interface {
+    SendCoinsFromAccountToModule(ctx sdk.Context, senderAddr sdk.AccAddress, recipientModule string, amt sdk.Coins) error
+    SendCoinsFromModuleToAccount(ctx sdk.Context, senderModule string, recipientAddr sdk.AccAddress, amt sdk.Coins) error
+    GetAllBalances(ctx sdk.Context, addr sdk.AccAddress) sdk.Coins
+}
@@ -0,0 +1,31 @@
+package types
+
+import (
+    "fmt"
+)
+
+// DefaultGenesis starts with no tasks; IDs begin at 1.
+func DefaultGenesis() *GenesisState {
+    return &GenesisState{NextTaskId: 1, Tasks: []Task{}}
+}
+
+// Validate checks every task and that no task ID is reused.
+func (gs GenesisState) Validate() error {
+    if gs.NextTaskId == 0 {
+        return fmt.Errorf("next task ID must be positive")
+    }
+    seen := make(map[uint64]bool, len(gs.Tasks))
+    for _, task := range gs.Tasks {
+        if err := task.Validate(); err != nil {
+            return err
+        }
+        if seen[task.Id] {
+            return fmt.Errorf("duplicate task ID %d", task.Id)
+        }
+        if task.Id >= gs.NextTaskId {
+            return fmt.Errorf("task ID %d is not below next task ID %d", task.Id, gs.NextTaskId)
+        }
+        seen[task.Id] = true
+    }
+    return nil
+}
@@ -0,0 +1,24 @@
+package types
+
+import (
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+    // ModuleName is also the name of the module account holding the escrow
+    ModuleName   = "bounty"
+    StoreKey     = ModuleName
+    RouterKey    = ModuleName
+    QuerierRoute = ModuleName
+)
+
+var (
+    TaskKeyPrefix = []byte{0x01}
+    NextTaskIDKey = []byte{0x02}
+)
+
+// TaskKey is the store key of a task. IDs are big-endian so tasks iterate in
+// creation order.
+func TaskKey(id uint64) []byte {
+    return append(append([]byte{}, TaskKeyPrefix...), sdk.Uint64ToBigEndian(id)...)
+}
@@ -0,0 +1,136 @@
+package types
+
+import (
+    "strings"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// Message types, also used as the amino route types.
+const (
+    TypeMsgCreateTask  = "create_task"
+    TypeMsgClaimTask   = "claim_task"
+    TypeMsgApproveTask = "approve_task"
+    TypeMsgCancelTask  = "cancel_task"
+)
+
+var (
+    _ sdk.Msg = &MsgCreateTask{}
+    _ sdk.Msg = &MsgClaimTask{}
+    _ sdk.Msg = &MsgApproveTask{}
+    _ sdk.Msg = &MsgCancelTask{}
+)
+
+func NewMsgCreateTask(creator sdk.AccAddress, title, description string, bounty sdk.Coin) *MsgCreateTask {
+    return &MsgCreateTask{Creator: creator.String(), Title: title, Description: description, Bounty: bounty}
+}
+
+func (msg MsgCreateTask) Route() string { return RouterKey }
+func (msg MsgCreateTask) Type() string  { return TypeMsgCreateTask }
+
+func (msg MsgCreateTask) ValidateBasic() error {
+    if _, err := sdk.AccAddressFromBech32(msg.Creator); err != nil {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidAddress, "invalid creator: %v", err)
+    }
+    if strings.TrimSpace(msg.Title) == "" {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "title is required")
+    }
+    if !msg.Bounty.IsValid() || !msg.Bounty.IsPositive() {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, msg.Bounty.String())
+    }
+    return nil
+}
+
+func (msg MsgCreateTask) GetSignBytes() []byte {
+    return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(&msg))
+}
+
+func (msg MsgCreateTask) GetSigners() []sdk.AccAddress {
+    return []sdk.AccAddress{mustAddress(msg.Creator)}
+}
+
+func NewMsgClaimTask(claimer sdk.AccAddress, taskID uint64, proof string) *MsgClaimTask {
+    return &MsgClaimTask{Claimer: claimer.String(), TaskId: taskID, Proof: proof}
+}
+
+func (msg MsgClaimTask) Route() string { return RouterKey }
+func (msg MsgClaimTask) Type() string  { return TypeMsgClaimTask }
+
+func (msg MsgClaimTask) ValidateBasic() error {
+    if _, err := sdk.AccAddressFromBech32(msg.Claimer); err != nil {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidAddress, "invalid claimer: %v", err)
+    }
+    if msg.TaskId == 0 {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "task ID is required")
+    }
+    if strings.TrimSpace(msg.Proof) == "" {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "proof is required")
+    }
+    return nil
+}
+
+func (msg MsgClaimTask) GetSignBytes() []byte {
+    return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(&msg))
+}
+
+func (msg MsgClaimTask) GetSigners() []sdk.AccAddress {
+    return []sdk.AccAddress{mustAddress(msg.Claimer)}
+}
+
+func NewMsgApproveTask(creator sdk.AccAddress, taskID uint64) *MsgApproveTask {
+    return &MsgApproveTask{Creator: creator.String(), TaskId: taskID}
+}
+
+func (msg MsgApproveTask) Route() string { return RouterKey }
+func (msg MsgApproveTask) Type() string  { return TypeMsgApproveTask }
+
+func (msg MsgApproveTask) ValidateBasic() error {
+    return validateCreatorAndTask(msg.Creator, msg.TaskId)
+}
+
+func (msg MsgApproveTask) GetSignBytes() []byte {
+    return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(&msg))
+}
+
+func (msg MsgApproveTask) GetSigners() []sdk.AccAddress {
+    return []sdk.AccAddress{mustAddress(msg.Creator)}
+}
+
+func NewMsgCancelTask(creator sdk.AccAddress, taskID uint64) *MsgCancelTask {
+    return &MsgCancelTask{Creator: creator.String(), TaskId: taskID}
+}
+
+func (msg MsgCancelTask) Route() string { return RouterKey }
+func (msg MsgCancelTask) Type() string  { return TypeMsgCancelTask }
+
+func (msg MsgCancelTask) ValidateBasic() error {
+    return validateCreatorAndTask(msg.Creator, msg.TaskId)
+}
+
+func (msg MsgCancelTask) GetSignBytes() []byte {
+    return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(&msg))
+}
+
+func (msg MsgCancelTask) GetSigners() []sdk.AccAddress {
+    return []sdk.AccAddress{mustAddress(msg.Creator)}
+}
+
+func validateCreatorAndTask(creator string, taskID uint64) error {
+    if _, err := sdk.AccAddressFromBech32(creator); err != nil {
+        return sdkerrors.Wrapf(sdkerrors.ErrInvalidAddress, "invalid creator: %v", err)
+    }
+    if taskID == 0 {
+        return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "task ID is required")
+    }
+    return nil
+}
+
+// mustAddress parses a signer that ValidateBasic has already checked.
+func mustAddress(address string) sdk.AccAddress {
+    addr, err := sdk.AccAddressFromBech32(address)
+    if err != nil {
+        panic(err)
+    }
+    return addr
+}
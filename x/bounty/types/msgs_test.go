@@ -0,0 +1,88 @@
+package types
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/cosmos/cosmos-sdk/codec"
+    codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+    sdk "github.com/cosmos/cosmos-sdk/types"
+    authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+    authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+func TestValidateBasic(t *testing.T) {
+    addr := authtypes.NewModuleAddress("someone")
+    bounty := sdk.NewInt64Coin("microSERVDR", 1000)
+    cases := []struct {
+        name  string
+        msg   sdk.Msg
+        valid bool
+    }{
+        {"create", NewMsgCreateTask(addr, "Fix bug", "", bounty), true},
+        {"create without title", NewMsgCreateTask(addr, " ", "", bounty), false},
+        {"create with zero bounty", NewMsgCreateTask(addr, "Fix bug", "", sdk.NewInt64Coin("microSERVDR", 0)), false},
+        {"create with bad address", &MsgCreateTask{Creator: "nope", Title: "Fix bug", Bounty: bounty}, false},
+        {"claim", NewMsgClaimTask(addr, 1, "proof"), true},
+        {"claim without proof", NewMsgClaimTask(addr, 1, ""), false},
+        {"claim task zero", NewMsgClaimTask(addr, 0, "proof"), false},
+        {"approve", NewMsgApproveTask(addr, 1), true},
+        {"approve task zero", NewMsgApproveTask(addr, 0), false},
+        {"cancel", NewMsgCancelTask(addr, 1), true},
+        {"cancel with bad address", &MsgCancelTask{Creator: "", TaskId: 1}, false},
+    }
+    for _, tc := range cases {
+        if err := tc.msg.ValidateBasic(); (err == nil) != tc.valid {
+            t.Errorf("%s: expected valid=%v, got %v", tc.name, tc.valid, err)
+        }
+    }
+}
+
+func TestMsgEncoding(t *testing.T) {
+    addr := authtypes.NewModuleAddress("someone")
+    msg := NewMsgCreateTask(addr, "Fix bug", "details", sdk.NewInt64Coin("microSERVDR", 1000))
+
+    signBytes := string(msg.GetSignBytes())
+    if !strings.Contains(signBytes, `"type":"bounty/MsgCreateTask"`) || !strings.Contains(signBytes, `"title":"Fix bug"`) {
+        t.Fatalf("unexpected sign bytes %s", signBytes)
+    }
+    if signers := msg.GetSigners(); len(signers) != 1 || !signers[0].Equals(addr) {
+        t.Fatalf("unexpected signers %v", signers)
+    }
+
+    // Messages travel in transactions packed into Any
+    registry := codectypes.NewInterfaceRegistry()
+    RegisterInterfaces(registry)
+    packed, err := codectypes.NewAnyWithValue(msg)
+    if err != nil {
+        t.Fatalf("pack failed: %v", err)
+    }
+    if packed.TypeUrl != "/bounty.v1.MsgCreateTask" {
+        t.Fatalf("unexpected type URL %s", packed.TypeUrl)
+    }
+    var unpacked sdk.Msg
+    if err := registry.UnpackAny(packed, &unpacked); err != nil {
+        t.Fatalf("unpack failed: %v", err)
+    }
+    if got, ok := unpacked.(*MsgCreateTask); !ok || got.String() != msg.String() {
+        t.Fatalf("round trip changed the message: %v -> %v", msg, unpacked)
+    }
+
+    // The tx decoder checks every message against its descriptor
+    txConfig := authtx.NewTxConfig(codec.NewProtoCodec(registry), authtx.DefaultSignModes)
+    builder := txConfig.NewTxBuilder()
+    if err := builder.SetMsgs(msg, NewMsgClaimTask(addr, 1, "proof")); err != nil {
+        t.Fatalf("set msgs failed: %v", err)
+    }
+    bz, err := txConfig.TxEncoder()(builder.GetTx())
+    if err != nil {
+        t.Fatalf("encode failed: %v", err)
+    }
+    decoded, err := txConfig.TxDecoder()(bz)
+    if err != nil {
+        t.Fatalf("decode failed: %v", err)
+    }
+    if msgs := decoded.GetMsgs(); len(msgs) != 2 || msgs[0].(*MsgCreateTask).Title != "Fix bug" {
+        t.Fatalf("unexpected decoded messages %v", msgs)
+    }
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+    "fmt"
+
+    sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Task statuses. They match the names used off-chain in internal/types.
+const (
+    StatusOpen      = "OPEN"
+    StatusClaimed   = "CLAIMED"
+    StatusCompleted = "COMPLETED"
+    StatusCancelled = "CANCELLED"
+)
+
+// Escrowed reports whether the task's bounty is still held by the module.
+func (t Task) Escrowed() bool {
+    return t.Status == StatusOpen || t.Status == StatusClaimed
+}
+
+// Validate checks a task read from genesis.
+func (t Task) Validate() error {
+    if t.Id == 0 {
+        return fmt.Errorf("task ID must be positive")
+    }
+    if _, err := sdk.AccAddressFromBech32(t.Creator); err != nil {
+        return fmt.Errorf("task %d: invalid creator: %v", t.Id, err)
+    }
+    if !t.Bounty.IsValid() || !t.Bounty.IsPositive() {
+        return fmt.Errorf("task %d: invalid bounty %s", t.Id, t.Bounty)
+    }
+    switch t.Status {
+    case StatusOpen, StatusCancelled:
+        if t.Claimer != "" {
+            return fmt.Errorf("task %d: %s task has a claimer", t.Id, t.Status)
+        }
+    case StatusClaimed, StatusCompleted:
+        if _, err := sdk.AccAddressFromBech32(t.Claimer); err != nil {
+            return fmt.Errorf("task %d: invalid claimer: %v", t.Id, err)
+        }
+    default:
+        return fmt.Errorf("task %d: unknown status %q", t.Id, t.Status)
+    }
+    return nil
+}